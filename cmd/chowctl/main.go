@@ -0,0 +1,131 @@
+// Command chowctl runs maintenance tasks against the chow database
+package main
+
+import (
+	"chow/internal/config"
+	"chow/internal/database"
+	"chow/internal/repository"
+	"chow/internal/service"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `usage: chowctl <command> [flags]
+
+commands:
+  votes reconcile [-dry-run]        recompute joint vote counts from the votes table and report discrepancies
+  keys rotate [-algorithm EdDSA]    sign new tokens with a new key. tokens signed with the old keys stay valid until they expire
+`
+
+func main() {
+	_ = godotenv.Load()
+	log.SetFlags(0)
+
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch command := os.Args[1] + " " + os.Args[2]; command {
+	case "votes reconcile":
+		err = reconcileVotes(ctx, os.Args[3:])
+	case "keys rotate":
+		err = rotateKeys(ctx, os.Args[3:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// reconcileVotes reports joints whose vote counts differ from the votes table and repairs them unless it is a dry run
+func reconcileVotes(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("votes reconcile", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report discrepancies without fixing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+	db, err := database.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	jointRepo := repository.NewJointRepository(db.DB)
+	voteRepo := repository.NewVoteRepository(db.DB)
+	checkinRepo := repository.NewCheckinRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	jointService := service.NewJointService(cfg, jointRepo, voteRepo, checkinRepo, auditRepo, outboxRepo)
+
+	// repairs run in batches so keep going until nothing is left to fix
+	total := 0
+	for {
+		discrepancies, err := jointService.ReconcileVoteCounts(ctx, !*dryRun)
+		if err != nil {
+			return err
+		}
+		for _, d := range discrepancies {
+			fmt.Printf("%s\tupvotes %d -> %d\tdownvotes %d -> %d\n", d.JointID, d.UpVotes, d.ActualUpVotes, d.DownVotes, d.ActualDownVotes)
+		}
+		total += len(discrepancies)
+		if *dryRun || len(discrepancies) == 0 {
+			break
+		}
+	}
+
+	if *dryRun {
+		fmt.Printf("%d joints with drifted vote counts\n", total)
+	} else {
+		fmt.Printf("%d joints repaired\n", total)
+	}
+	return nil
+}
+
+// rotateKeys retires the active signing keys and creates a new one. Running servers pick it up on their next refresh
+func rotateKeys(ctx context.Context, args []string) error {
+	cfg, err := config.New()
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("keys rotate", flag.ExitOnError)
+	algorithm := flags.String("algorithm", cfg.JWTAlgorithm, "algorithm of the new key, EdDSA or RS256")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := database.New(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	signingKeyRepo := repository.NewSigningKeyRepository(db.DB)
+	signingKeyService := service.NewSigningKeyService(cfg, signingKeyRepo)
+
+	key, err := signingKeyService.Rotate(ctx, *algorithm)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created %s signing key %s\n", key.Algorithm, key.KID)
+	return nil
+}
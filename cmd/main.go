@@ -7,6 +7,7 @@ import (
 	"chow/internal/repository"
 	"chow/internal/router"
 	"chow/internal/service"
+	"chow/internal/worker"
 	"context"
 	"fmt"
 	"log"
@@ -31,6 +32,11 @@ import (
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name Authorization
+// @description Type "ApiKey" followed by a space and a personal API key. Keys only work on the routes their scopes cover.
+
 func main() {
 	_ = godotenv.Load()
 
@@ -51,23 +57,124 @@ func main() {
 	jointRepo := repository.NewJointRepository(db.DB)
 	voteRepo := repository.NewVoteRepository(db.DB)
 	complaintRepo := repository.NewComplaintRepository(db.DB)
+	auditRepo := repository.NewAuditRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	streamRepo := repository.NewStreamRepository(db.DB)
+	savedSearchRepo := repository.NewSavedSearchRepository(db.DB)
+	checkinRepo := repository.NewCheckinRepository(db.DB)
+	voteReviewRepo := repository.NewVoteReviewRepository(db.DB)
+	reputationRepo := repository.NewReputationRepository(db.DB)
+	recommendationRepo := repository.NewRecommendationRepository(db.DB)
+	activityRepo := repository.NewActivityRepository(db.DB)
+	translationRepo := repository.NewTranslationRepository(db.DB)
+	securityRepo := repository.NewSecurityRepository(db.DB)
+	twoFactorRepo := repository.NewTwoFactorRepository(db.DB)
+	signingKeyRepo := repository.NewSigningKeyRepository(db.DB)
+	identityRepo := repository.NewIdentityRepository(db.DB)
+	apiKeyRepo := repository.NewAPIKeyRepository(db.DB)
+	accountRepo := repository.NewAccountRepository(db.DB)
+
+	// email
+	mailer := service.NewMailer(cfg)
+
+	// token signing keys. a key is created on first start
+	signingKeyService := service.NewSigningKeyService(cfg, signingKeyRepo)
+	if err := signingKeyService.Init(context.Background()); err != nil {
+		log.Fatal(err)
+	}
 
 	// services
-	authService := service.NewAuthService(cfg, userRepo)
-	jointService := service.NewJointService(cfg, jointRepo, voteRepo)
-	complaintService := service.NewComplaintService(cfg, complaintRepo)
+	authService := service.NewAuthService(cfg, userRepo, securityRepo, twoFactorRepo, identityRepo, apiKeyRepo, signingKeyService, mailer)
+	notificationService := service.NewNotificationService(cfg, notificationRepo, userRepo)
+	jointService := service.NewJointService(cfg, jointRepo, voteRepo, checkinRepo, auditRepo, outboxRepo)
+	complaintService := service.NewComplaintService(cfg, complaintRepo, jointRepo, auditRepo, outboxRepo, notificationService)
+	auditService := service.NewAuditService(cfg, auditRepo)
+	webhookService := service.NewWebhookService(cfg, webhookRepo, auditRepo)
+	streamService := service.NewStreamService(cfg, streamRepo, jointRepo)
+	savedSearchService := service.NewSavedSearchService(cfg, savedSearchRepo, notificationService, mailer)
+	checkinService := service.NewCheckinService(cfg, checkinRepo, jointRepo, voteRepo, outboxRepo)
+	voteIntegrityService := service.NewVoteIntegrityService(cfg, voteReviewRepo, jointRepo, auditRepo, outboxRepo, notificationService)
+	reputationService := service.NewReputationService(cfg, reputationRepo, userRepo, jointRepo, auditRepo, notificationService)
+	recommendationService := service.NewRecommendationService(cfg, recommendationRepo)
+	trendingService := service.NewTrendingService(cfg, activityRepo)
+	translationService := service.NewTranslationService(cfg, translationRepo, jointRepo, auditRepo)
+	accountService := service.NewAccountService(cfg, accountRepo, jointRepo, auditRepo, authService)
 
 	// handlers
 	authHandler := handler.NewAuthHandler(authService)
-	jointHandler := handler.NewJointHandler(jointService, complaintService)
+	keyHandler := handler.NewKeyHandler(signingKeyService)
+	jointHandler := handler.NewJointHandler(jointService, complaintService, reputationService, trendingService, translationService)
 	complaintHandler := handler.NewComplaintHandler(complaintService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	streamHandler := handler.NewStreamHandler(streamService)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService, translationService)
+	checkinHandler := handler.NewCheckinHandler(checkinService)
+	voteReviewHandler := handler.NewVoteReviewHandler(voteIntegrityService)
+	reputationHandler := handler.NewReputationHandler(reputationService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	recommendationHandler := handler.NewRecommendationHandler(recommendationService, translationService)
+	trendingHandler := handler.NewTrendingHandler(trendingService, translationService)
+	translationHandler := handler.NewTranslationHandler(translationService)
+
+	// rate limits are shared between instances when kept in postgres
+	var rateLimitStore service.RateLimitStore
+	switch cfg.RateLimitBackend {
+	case config.MemoryRateLimitBackend:
+		rateLimitStore = service.NewMemoryRateLimitStore()
+	case config.PostgresRateLimitBackend:
+		rateLimitStore = repository.NewRateLimitRepository(db.DB)
+	default:
+		log.Fatalf("unknown rate limit backend %q", cfg.RateLimitBackend)
+	}
+	rateLimitService := service.NewRateLimitService(cfg, rateLimitStore)
 
 	// middleware
-	middleware := handler.NewMiddleware(authService)
+	middleware := handler.NewMiddleware(authService, rateLimitService)
+
+	// domain events. subscribers are registered before the dispatcher starts
+	dispatcher := service.NewEventDispatcher(cfg, outboxRepo)
+	notificationService.RegisterSubscribers(dispatcher)
+	webhookService.RegisterSubscribers(dispatcher)
+	streamService.RegisterSubscribers(dispatcher)
+	savedSearchService.RegisterSubscribers(dispatcher)
+	voteIntegrityService.RegisterSubscribers(dispatcher)
+	reputationService.RegisterSubscribers(dispatcher)
+	trendingService.RegisterSubscribers(dispatcher)
+	dispatcher.Start()
+
+	// real-time updates published by any instance
+	streamService.Start()
+
+	// background jobs
+	scheduler := worker.NewScheduler()
+	scheduler.Add(worker.Job{Name: "purge-deleted-joints", Interval: cfg.PurgeInterval, Run: jointService.PurgeExpiredJoints})
+	scheduler.Add(worker.Job{Name: "review-hidden-joints", Interval: cfg.ComplaintReviewInterval, Run: complaintService.ReviewHiddenJoints})
+	scheduler.Add(worker.Job{Name: "prune-outbox", Interval: time.Hour, Run: dispatcher.PruneDispatchedEvents})
+	scheduler.Add(worker.Job{Name: "prune-notification-events", Interval: time.Hour, Run: notificationService.PruneProcessedEvents})
+	scheduler.Add(worker.Job{Name: "deliver-webhooks", Interval: cfg.WebhookPollInterval, Run: webhookService.DeliverPendingWebhooks})
+	scheduler.Add(worker.Job{Name: "reconcile-vote-counts", Interval: cfg.VoteReconcileInterval, Run: jointService.RepairVoteCounts})
+	scheduler.Add(worker.Job{Name: "saved-search-digests", Interval: cfg.SavedSearchDigestInterval, Run: savedSearchService.SendEmailDigests})
+	scheduler.Add(worker.Job{Name: "recompute-joint-similarities", Interval: cfg.RecommendationInterval, Run: recommendationService.RecomputeSimilarities})
+	scheduler.Add(worker.Job{Name: "prune-joint-activity", Interval: 24 * time.Hour, Run: trendingService.PruneActivity})
+	scheduler.Add(worker.Job{Name: "purge-security-records", Interval: time.Hour, Run: authService.PurgeSecurityRecords})
+	scheduler.Add(worker.Job{Name: "purge-rate-limit-buckets", Interval: 10 * time.Minute, Run: rateLimitService.PurgeIdleBuckets})
+	scheduler.Add(worker.Job{Name: "refresh-signing-keys", Interval: cfg.SigningKeyRefreshInterval, Run: signingKeyService.Refresh})
+	scheduler.Add(worker.Job{Name: "build-data-exports", Interval: cfg.DataExportPollInterval, Run: accountService.BuildPendingExports})
+	scheduler.Add(worker.Job{Name: "purge-data-exports", Interval: time.Hour, Run: accountService.PurgeExpiredExports})
+	scheduler.Add(worker.Job{Name: "delete-accounts", Interval: cfg.PurgeInterval, Run: accountService.DeleteDueAccounts})
+	scheduler.Start(context.Background())
 
 	// create server router
 	r := gin.Default()
-	router.RegisterRoutes(r, authHandler, jointHandler, complaintHandler, middleware)
+	// client IPs are only read from X-Forwarded-For when the request comes through a known proxy, since clients can set it to anything
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatal(err)
+	}
+	router.RegisterRoutes(r, authHandler, keyHandler, jointHandler, complaintHandler, auditHandler, notificationHandler, webhookHandler, streamHandler, savedSearchHandler, checkinHandler, voteReviewHandler, reputationHandler, accountHandler, recommendationHandler, trendingHandler, translationHandler, middleware)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf("localhost:%v", cfg.Port),
@@ -81,7 +188,7 @@ func main() {
 	done := make(chan struct{}, 1)
 
 	// run graceful shutdown in a separate goroutine
-	go gracefulShutdown(server, done)
+	go gracefulShutdown(server, dispatcher, streamService, scheduler, done)
 
 	log.Printf("Server starting on %s", server.Addr)
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -93,7 +200,7 @@ func main() {
 	log.Println("Graceful shutdown complete.")
 }
 
-func gracefulShutdown(apiServer *http.Server, done chan struct{}) {
+func gracefulShutdown(apiServer *http.Server, dispatcher *service.EventDispatcher, streamService *service.StreamService, scheduler *worker.Scheduler, done chan struct{}) {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
@@ -102,6 +209,9 @@ func gracefulShutdown(apiServer *http.Server, done chan struct{}) {
 	log.Println("shutting down gracefully, press Ctrl+C again to force")
 	stop()
 
+	// disconnect stream clients so that their requests do not hold up the server shutdown
+	streamService.Shutdown()
+
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -110,6 +220,16 @@ func gracefulShutdown(apiServer *http.Server, done chan struct{}) {
 		log.Printf("Server forced to shutdown with error: %v\n", err)
 	}
 
+	// wait for running background jobs to finish
+	scheduler.Stop()
+
+	// deliver events committed by the last requests before exiting
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer drainCancel()
+	if err := dispatcher.Shutdown(drainCtx); err != nil {
+		log.Printf("Event dispatcher stopped before draining the outbox: %v\n", err)
+	}
+
 	log.Println("Server exiting")
 
 	// notify the main goroutine that the shutdown is complete
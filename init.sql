@@ -59,3 +59,515 @@ CREATE TABLE IF NOT EXISTS complaints(
 	-- only one user vote per joint is allowed
 	UNIQUE(user_id, joint_id)
 );
+
+-- complaint moderation. joints can be hidden from public listings while complaints against them are reviewed
+ALTER TABLE joints ADD COLUMN IF NOT EXISTS visibility VARCHAR(50) NOT NULL DEFAULT 'visible';
+ALTER TABLE complaints ADD COLUMN IF NOT EXISTS category VARCHAR(50) NOT NULL DEFAULT 'other';
+
+CREATE INDEX IF NOT EXISTS idx_complaints_joint_status ON complaints(joint_id, status);
+
+-- audit events. rows are append-only and reference their targets without foreign keys so that history outlives the records
+CREATE TABLE IF NOT EXISTS audit_events(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	actor_id UUID,
+	action VARCHAR(100) NOT NULL,
+	target_type VARCHAR(50) NOT NULL,
+	target_id UUID NOT NULL,
+	before JSONB,
+	after JSONB,
+	ip_address VARCHAR(100),
+	request_id VARCHAR(100),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id);
+
+CREATE OR REPLACE FUNCTION prevent_audit_event_change() RETURNS TRIGGER AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+	BEFORE UPDATE OR DELETE ON audit_events
+	FOR EACH ROW EXECUTE FUNCTION prevent_audit_event_change();
+
+-- soft deleted joints are hidden from every query until restored or purged after the retention period
+ALTER TABLE joints ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE joints ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id);
+ALTER TABLE joints ADD COLUMN IF NOT EXISTS deletion_reason VARCHAR(500);
+
+CREATE INDEX IF NOT EXISTS idx_joints_deleted_at ON joints(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- transactional outbox. domain events are written in the same transaction as the state change and delivered to subscribers by a dispatcher
+CREATE TABLE IF NOT EXISTS outbox_events(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	event_type VARCHAR(100) NOT NULL,
+	aggregate_id UUID NOT NULL,
+	actor_id UUID,
+	payload JSONB NOT NULL,
+	-- names of the subscribers that have handled the event
+	delivered_to JSONB NOT NULL DEFAULT '[]',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	dispatched_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(next_attempt_at, created_at) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_dispatched_at ON outbox_events(dispatched_at) WHERE dispatched_at IS NOT NULL;
+
+-- notifications. unread notifications sharing a group key are aggregated into one
+CREATE TABLE IF NOT EXISTS notifications(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id),
+	type VARCHAR(100) NOT NULL,
+	title VARCHAR(255) NOT NULL,
+	body VARCHAR(2000) NOT NULL,
+	target_type VARCHAR(50),
+	target_id UUID,
+	group_key VARCHAR(255),
+	-- event that produced the notification, used to ignore redelivered events
+	event_id UUID,
+	count INTEGER NOT NULL DEFAULT 1,
+	read_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_event ON notifications(user_id, event_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_group ON notifications(user_id, group_key) WHERE read_at IS NULL;
+
+-- events already turned into a notification for a user so that redelivered events are not counted twice in grouped notifications
+CREATE TABLE IF NOT EXISTS notification_events(
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	event_id UUID NOT NULL,
+	processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY(user_id, event_id)
+);
+
+-- notification types are enabled unless a preference disables them
+CREATE TABLE IF NOT EXISTS notification_preferences(
+	user_id UUID NOT NULL REFERENCES users(id),
+	type VARCHAR(100) NOT NULL,
+	enabled BOOLEAN NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+	PRIMARY KEY(user_id, type)
+);
+
+-- replies exchanged between complainants and moderators
+CREATE TABLE IF NOT EXISTS complaint_replies(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	complaint_id UUID NOT NULL REFERENCES complaints(id),
+	user_id UUID NOT NULL REFERENCES users(id),
+	body VARCHAR(2000) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_complaint_replies_complaint ON complaint_replies(complaint_id, created_at);
+
+-- outbound webhooks. the secret is kept in plain text as it is needed to sign payloads
+CREATE TABLE IF NOT EXISTS webhooks(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	url VARCHAR(2000) NOT NULL,
+	secret VARCHAR(255) NOT NULL,
+	event_types JSONB NOT NULL DEFAULT '[]',
+	is_active BOOLEAN NOT NULL DEFAULT TRUE,
+	-- failed attempts since the last successful delivery. the webhook is disabled once this reaches the configured limit
+	consecutive_failures INTEGER NOT NULL DEFAULT 0,
+	disabled_at TIMESTAMPTZ,
+	created_by UUID NOT NULL REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+	event_id UUID NOT NULL,
+	event_type VARCHAR(100) NOT NULL,
+	payload JSONB NOT NULL,
+	status VARCHAR(50) NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	response_code INTEGER,
+	response_body VARCHAR(2000),
+	last_error TEXT,
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	delivered_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+	-- events are dispatched at least once so each event is only queued once per webhook
+	UNIQUE(webhook_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_pending ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at DESC);
+
+-- the kind of food a joint serves, used to filter saved searches and recommendations
+ALTER TABLE joints ADD COLUMN IF NOT EXISTS category VARCHAR(50) NOT NULL DEFAULT 'other';
+CREATE INDEX IF NOT EXISTS idx_joints_category ON joints(category);
+
+-- saved searches alert users about newly approved joints in an area. the area is either a circle or a polygon
+CREATE TABLE IF NOT EXISTS saved_searches(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id),
+	name VARCHAR(100) NOT NULL,
+	center GEOGRAPHY(POINT),
+	radius DOUBLE PRECISION,
+	area GEOGRAPHY(POLYGON),
+	categories JSONB NOT NULL DEFAULT '[]',
+	keywords JSONB NOT NULL DEFAULT '[]',
+	delivery VARCHAR(50) NOT NULL DEFAULT 'notification',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+	CHECK ((center IS NOT NULL AND radius IS NOT NULL) <> (area IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user ON saved_searches(user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_center ON saved_searches USING GIST(center);
+CREATE INDEX IF NOT EXISTS idx_saved_searches_area ON saved_searches USING GIST(area);
+
+-- joints matched by saved searches. matches of email digest searches are sent and marked as notified by the digest job
+CREATE TABLE IF NOT EXISTS saved_search_matches(
+	saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+	joint_id UUID NOT NULL REFERENCES joints(id),
+	matched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	notified_at TIMESTAMPTZ,
+
+	PRIMARY KEY(saved_search_id, joint_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_pending ON saved_search_matches(saved_search_id) WHERE notified_at IS NULL;
+
+-- check-ins verified against the joint location
+CREATE TABLE IF NOT EXISTS checkins(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id),
+	joint_id UUID NOT NULL REFERENCES joints(id),
+	location GEOGRAPHY(POINT) NOT NULL,
+	distance DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkins_user_joint ON checkins(user_id, joint_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_checkins_user ON checkins(user_id, created_at DESC);
+
+-- votes from verified visitors are flagged and weighted higher in the joint score
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT FALSE;
+-- existing joints start from their unweighted score. the backfill only runs when the column is added since a weighted score of 0 is legitimate
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'joints' AND column_name = 'score') THEN
+		ALTER TABLE joints ADD COLUMN score DOUBLE PRECISION NOT NULL DEFAULT 0;
+		UPDATE joints SET score = upvotes - downvotes;
+	END IF;
+END
+$$;
+
+-- vote integrity signals. votes are voided rather than deleted so that moderators can review them later
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS ip_address VARCHAR(45);
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS fingerprint VARCHAR(64);
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS risk_score DOUBLE PRECISION;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS risk_signals JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS voided_at TIMESTAMPTZ;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS voided_by UUID REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_votes_joint_updated ON votes(joint_id, updated_at DESC);
+
+-- vote counts are maintained from the votes table so that they cannot drift from it. voided votes are not counted
+CREATE OR REPLACE FUNCTION apply_vote_counts() RETURNS TRIGGER AS $$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		IF OLD.voided_at IS NULL THEN
+			UPDATE joints
+			SET upvotes = upvotes - (OLD.direction = 'up')::INT, downvotes = downvotes - (OLD.direction = 'down')::INT
+			WHERE id = OLD.joint_id;
+		END IF;
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		IF NEW.voided_at IS NULL THEN
+			UPDATE joints
+			SET upvotes = upvotes + (NEW.direction = 'up')::INT, downvotes = downvotes + (NEW.direction = 'down')::INT
+			WHERE id = NEW.joint_id;
+		END IF;
+	END IF;
+	RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_votes_apply_counts
+AFTER INSERT OR DELETE OR UPDATE OF direction, joint_id, voided_at ON votes
+FOR EACH ROW EXECUTE FUNCTION apply_vote_counts();
+
+-- clusters of suspicious votes queued for moderation
+CREATE TABLE IF NOT EXISTS vote_reviews(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	joint_id UUID NOT NULL REFERENCES joints(id),
+	reason VARCHAR(50) NOT NULL,
+	cluster_key VARCHAR(100) NOT NULL,
+	direction VARCHAR(10) NOT NULL,
+	status VARCHAR(20) NOT NULL DEFAULT 'open',
+	resolved_by UUID REFERENCES users(id),
+	resolved_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- a cluster only has one open review which keeps collecting votes until it is resolved
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vote_reviews_open ON vote_reviews(joint_id, reason, cluster_key, direction) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_vote_reviews_status ON vote_reviews(status, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS vote_review_votes(
+	review_id UUID NOT NULL REFERENCES vote_reviews(id) ON DELETE CASCADE,
+	vote_id UUID NOT NULL REFERENCES votes(id) ON DELETE CASCADE,
+	PRIMARY KEY(review_id, vote_id)
+);
+
+-- reputation ledger. users.reputation caches the sum of the points in a user's ledger
+ALTER TABLE users ADD COLUMN IF NOT EXISTS reputation INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_users_reputation ON users(reputation DESC);
+
+CREATE TABLE IF NOT EXISTS reputation_events(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id),
+	reason VARCHAR(50) NOT NULL,
+	points INTEGER NOT NULL,
+	joint_id UUID REFERENCES joints(id),
+	source_id UUID NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- points are awarded at most once for the same reason per source record such as a joint or complaint
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reputation_events_source ON reputation_events(source_id, user_id, reason);
+CREATE INDEX IF NOT EXISTS idx_reputation_events_user ON reputation_events(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_reputation_events_joint ON reputation_events(joint_id);
+
+CREATE TABLE IF NOT EXISTS user_badges(
+	user_id UUID NOT NULL REFERENCES users(id),
+	badge VARCHAR(50) NOT NULL,
+	awarded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY(user_id, badge)
+);
+
+-- cities used to group joints on leaderboards. a joint belongs to a city when it is within its radius
+CREATE TABLE IF NOT EXISTS cities(
+	slug VARCHAR(100) PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	center GEOGRAPHY(POINT) NOT NULL,
+	radius DOUBLE PRECISION NOT NULL
+);
+
+INSERT INTO cities(slug, name, center, radius) VALUES
+	('accra', 'Accra', ST_SetSRID(ST_MakePoint(-0.1870, 5.6037), 4326)::geography, 25000),
+	('kumasi', 'Kumasi', ST_SetSRID(ST_MakePoint(-1.6244, 6.6885), 4326)::geography, 20000),
+	('tamale', 'Tamale', ST_SetSRID(ST_MakePoint(-0.8393, 9.4008), 4326)::geography, 15000),
+	('takoradi', 'Sekondi-Takoradi', ST_SetSRID(ST_MakePoint(-1.7554, 4.9016), 4326)::geography, 15000),
+	('cape-coast', 'Cape Coast', ST_SetSRID(ST_MakePoint(-1.2466, 5.1053), 4326)::geography, 10000)
+ON CONFLICT(slug) DO NOTHING;
+
+-- how much each user likes the joints they interacted with. an up vote counts 1, a down vote -1 and a visit 0.5. voided votes are ignored
+CREATE OR REPLACE VIEW joint_preferences AS
+SELECT user_id, joint_id, SUM(rating)::DOUBLE PRECISION AS rating
+FROM (
+	SELECT user_id, joint_id, CASE WHEN direction = 'up' THEN 1.0 ELSE -1.0 END AS rating FROM votes WHERE voided_at IS NULL
+	UNION ALL
+	SELECT DISTINCT user_id, joint_id, 0.5 FROM checkins
+) interactions
+GROUP BY user_id, joint_id;
+
+-- most similar joints of each joint used for recommendations. recomputed periodically from joint preferences
+CREATE TABLE IF NOT EXISTS joint_similarities(
+	joint_id UUID NOT NULL REFERENCES joints(id) ON DELETE CASCADE,
+	similar_joint_id UUID NOT NULL REFERENCES joints(id) ON DELETE CASCADE,
+	similarity DOUBLE PRECISION NOT NULL,
+	-- number of users who interacted with both joints
+	support INTEGER NOT NULL,
+	computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY(joint_id, similar_joint_id)
+);
+
+-- hourly activity of each joint used to rank trending joints
+CREATE TABLE IF NOT EXISTS joint_activity(
+	joint_id UUID NOT NULL REFERENCES joints(id) ON DELETE CASCADE,
+	bucket TIMESTAMPTZ NOT NULL,
+	upvotes INTEGER NOT NULL DEFAULT 0,
+	downvotes INTEGER NOT NULL DEFAULT 0,
+	checkins INTEGER NOT NULL DEFAULT 0,
+	views INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY(joint_id, bucket)
+);
+
+CREATE INDEX IF NOT EXISTS idx_joint_activity_bucket ON joint_activity(bucket);
+
+-- events already aggregated into joint activity so that redelivered events are not counted twice
+CREATE TABLE IF NOT EXISTS joint_activity_events(
+	event_id UUID PRIMARY KEY,
+	processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- locale the name and description of a joint are written in
+ALTER TABLE joints ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
+
+-- names and descriptions of joints in locales other than their own. edited by moderators
+CREATE TABLE IF NOT EXISTS joint_translations(
+	joint_id UUID NOT NULL REFERENCES joints(id) ON DELETE CASCADE,
+	locale VARCHAR(10) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	description VARCHAR(5000),
+	updated_by UUID REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY(joint_id, locale)
+);
+
+-- token buckets of rate limited clients shared by every instance. losing them on a crash only resets the limits
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets(
+	key VARCHAR(255) PRIMARY KEY,
+	tokens DOUBLE PRECISION NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+
+-- failed logins by email since the last successful login. unknown emails are tracked too so that lockouts do not reveal which accounts exist
+CREATE TABLE IF NOT EXISTS login_attempts(
+	email VARCHAR(255) PRIMARY KEY,
+	failed_attempts INTEGER NOT NULL DEFAULT 0,
+	last_failed_at TIMESTAMPTZ NOT NULL,
+	locked_until TIMESTAMPTZ,
+	unlock_token_hash VARCHAR(64) UNIQUE
+);
+
+-- logins and other security events shown to account owners. failed logins to unknown emails have no user
+CREATE TABLE IF NOT EXISTS security_events(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	type VARCHAR(50) NOT NULL,
+	ip_address VARCHAR(100),
+	user_agent VARCHAR(500),
+	device_id VARCHAR(255),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_security_events_user ON security_events(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_security_events_failed_logins ON security_events(ip_address, created_at) WHERE type = 'login_failed';
+CREATE INDEX IF NOT EXISTS idx_security_events_created_at ON security_events(created_at);
+
+-- totp two-factor authentication. the secret is encrypted and only in use once enabled_at is set
+CREATE TABLE IF NOT EXISTS user_two_factor(
+	user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	secret VARCHAR(255) NOT NULL,
+	enabled_at TIMESTAMPTZ,
+	-- time step of the last accepted code so that a code cannot be used twice
+	last_used_step BIGINT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- single use codes for signing in without the authenticator app. only their hashes are stored
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	code_hash VARCHAR(64) NOT NULL,
+	used_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE(user_id, code_hash)
+);
+
+-- keys access tokens are signed with. the newest active key signs new tokens and retired keys only verify the tokens they signed until those expire
+CREATE TABLE IF NOT EXISTS signing_keys(
+	kid VARCHAR(64) PRIMARY KEY,
+	algorithm VARCHAR(10) NOT NULL,
+	-- pkcs8 private key encrypted with the encryption key
+	private_key TEXT NOT NULL,
+	-- pem encoded public key
+	public_key TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	retired_at TIMESTAMPTZ
+);
+
+-- accounts signed in with an openid connect provider. users without a password can only sign in with their identities
+ALTER TABLE users ALTER COLUMN password DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_identities(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	provider VARCHAR(50) NOT NULL,
+	-- subject of the user at the provider
+	subject VARCHAR(255) NOT NULL,
+	email VARCHAR(255),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	last_login_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	UNIQUE(provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user ON user_identities(user_id);
+
+-- sign ins started with a provider. each state is used once and only its hash is stored
+CREATE TABLE IF NOT EXISTS oidc_states(
+	state_hash VARCHAR(64) PRIMARY KEY,
+	provider VARCHAR(50) NOT NULL,
+	code_verifier VARCHAR(128) NOT NULL,
+	nonce VARCHAR(128) NOT NULL,
+	-- set when a signed in user started linking the provider to their account rather than signing in with it
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	expires_at TIMESTAMPTZ NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_states_expires_at ON oidc_states(expires_at);
+
+-- personal keys integrations authenticate with instead of a session. only the hash of a key is stored and its prefix identifies it to its owner
+CREATE TABLE IF NOT EXISTS api_keys(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name VARCHAR(100) NOT NULL,
+	prefix VARCHAR(20) NOT NULL UNIQUE,
+	key_hash VARCHAR(64) NOT NULL UNIQUE,
+	scopes JSONB NOT NULL DEFAULT '[]',
+	expires_at TIMESTAMPTZ NOT NULL,
+	last_used_at TIMESTAMPTZ,
+	last_used_ip VARCHAR(45),
+	revoked_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_user ON api_keys(user_id);
+
+-- account deletion. accounts are anonymised once the grace period after their owner asked for it ends. the row is kept so that the
+-- joints, votes and complaints of the account keep their foreign keys and vote totals stay intact
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+
+-- archives of the personal data of users, built in the background and kept until they expire
+CREATE TABLE IF NOT EXISTS data_exports(
+	id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	status VARCHAR(20) NOT NULL DEFAULT 'pending',
+	archive BYTEA,
+	size_bytes BIGINT,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT,
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	completed_at TIMESTAMPTZ,
+	expires_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_exports_user ON data_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_pending ON data_exports(next_attempt_at) WHERE status = 'pending';
+-- a user only has one export being built at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_data_exports_user_pending ON data_exports(user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports(expires_at) WHERE expires_at IS NOT NULL;
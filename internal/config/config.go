@@ -1,8 +1,10 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,18 +12,178 @@ import (
 const (
 	// 2,000 meters = 2km
 	DefaultMaxSearchRadius = 2000
+	// distinct complainants per category required to hide a joint
+	DefaultComplaintHideThresholds = "permanently_closed:5,wrong_location:5,hygiene:10"
+	// requests allowed per period for each rate limit policy
+	DefaultRateLimits = "default:300/1m,auth:10/15m,submissions:20/1h,votes:60/1m,checkins:20/1h,complaints:10/1h"
 )
 
+// DefaultJWTSecret is the development secret the other secrets default to. The server refuses to start with it in production
+const DefaultJWTSecret = "random-token"
+
+// environments
+const (
+	DevelopmentEnvironment = "development"
+	ProductionEnvironment  = "production"
+)
+
+// token signing algorithms
+const (
+	EdDSAAlgorithm = "EdDSA"
+	RS256Algorithm = "RS256"
+)
+
+// rate limit backends
+const (
+	MemoryRateLimitBackend   = "memory"
+	PostgresRateLimitBackend = "postgres"
+)
+
+// RateLimit allows Limit requests per Period. Unused requests accumulate up to Limit so that clients can burst
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// OIDCProvider is an OpenID Connect provider users can sign in with. Its endpoints are discovered from the issuer
+type OIDCProvider struct {
+	Name     string
+	Issuer   string
+	ClientID string
+	// ClientSecret is empty for public clients, which rely on PKCE alone
+	ClientSecret string
+	Scopes       []string
+}
+
 type Config struct {
-	Db               string
-	DbPassword       string
-	DbUsername       string
-	DbPort           string
-	DbHost           string
-	Port             int
+	Db         string
+	DbPassword string
+	DbUsername string
+	DbPort     string
+	DbHost     string
+	Port       int
+	// Environment is either development or production
+	Environment string
+	// JWTSecret is the default of the cursor, two-factor and encryption secrets. Tokens are signed with the keys managed by chowctl
 	JWTSecret        string
 	JWTExpiryMinutes time.Duration
-	MaxNearbyRadius  float64
+	// JWTAlgorithm is the algorithm of new signing keys, either EdDSA or RS256
+	JWTAlgorithm string
+	// JWTIssuer and JWTAudience are the iss and aud claims of issued tokens. Tokens with other values are rejected
+	JWTIssuer   string
+	JWTAudience string
+	// SigningKeyRefreshInterval is how often the signing keys are reloaded so that keys rotated from another instance are picked up
+	SigningKeyRefreshInterval time.Duration
+	// EncryptionKey encrypts the private signing keys stored in the database. It defaults to the JWT secret
+	EncryptionKey   string
+	MaxNearbyRadius float64
+	// CursorSecret signs the pagination cursors handed out to clients. It defaults to the JWT secret
+	CursorSecret string
+	// ComplaintHideThresholds maps a complaint category to the number of distinct users with open complaints in that category needed to hide a joint
+	ComplaintHideThresholds map[string]int
+	// ComplaintHideWindow is how far back complaints are considered when applying the hide thresholds
+	ComplaintHideWindow time.Duration
+	// ComplaintReviewInterval is how often the joints under review are checked again so that they are listed once their complaints leave the window
+	ComplaintReviewInterval time.Duration
+	// JointRetention is how long soft deleted joints are kept before they are purged
+	JointRetention time.Duration
+	// PurgeInterval is how often the purge job looks for expired joints
+	PurgeInterval time.Duration
+	// OutboxPollInterval is how often the event dispatcher checks the outbox for new events
+	OutboxPollInterval time.Duration
+	// OutboxMaxAttempts is the number of delivery attempts after which a failing event is abandoned
+	OutboxMaxAttempts int
+	// OutboxRetention is how long dispatched events are kept in the outbox
+	OutboxRetention time.Duration
+	// WebhookPollInterval is how often pending webhook deliveries are sent
+	WebhookPollInterval time.Duration
+	// WebhookTimeout is how long a webhook endpoint has to respond
+	WebhookTimeout time.Duration
+	// WebhookMaxAttempts is the number of attempts after which a webhook delivery is marked as failed
+	WebhookMaxAttempts int
+	// WebhookDisableAfter is the number of consecutive failed attempts after which a webhook is disabled
+	WebhookDisableAfter int
+	// SMTPHost is the mail server used to send emails. Emails are only logged when it is not set
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// MailFrom is the sender address of outgoing emails
+	MailFrom string
+	// SavedSearchDigestInterval is how often email digests of new saved search matches are sent
+	SavedSearchDigestInterval time.Duration
+	// CheckinTolerance is the maximum distance in meters between the device and the joint for a check-in to be accepted
+	CheckinTolerance float64
+	// CheckinCooldown is how long a user must wait before checking in at the same joint again
+	CheckinCooldown time.Duration
+	// VerifiedVoteWeight is the weight of votes from users who have checked in at the joint. Other votes have a weight of 1
+	VerifiedVoteWeight float64
+	// TrustedVoteWeight is the weight of votes from users whose trust level unlocks weighted votes
+	TrustedVoteWeight float64
+	// VoteReconcileInterval is how often vote counts are checked against the votes table and repaired
+	VoteReconcileInterval time.Duration
+	// VoteClusterWindow is how far back votes from the same IP address or device are grouped into a cluster
+	VoteClusterWindow time.Duration
+	// VoteClusterMinSize is the number of distinct users voting from the same IP address or device that gets a cluster reviewed
+	VoteClusterMinSize int
+	// VoteBurstWindow and VoteBurstThreshold define a burst as the number of votes in the same direction on a joint within the window
+	VoteBurstWindow    time.Duration
+	VoteBurstThreshold int
+	// VoteNewAccountAge is the age under which an account is considered new when assessing its votes
+	VoteNewAccountAge time.Duration
+	// RecommendationInterval is how often the similarities between joints used for recommendations are recomputed
+	RecommendationInterval time.Duration
+	// RecommendationNeighbours is the number of most similar joints kept for each joint
+	RecommendationNeighbours int
+	// RecommendationMinInteractions is the number of joints a user must have liked or visited before recommendations are personalised. Other users get popular joints near them
+	RecommendationMinInteractions int
+	// CategoryAffinityWeight is how much a user's preference for a category counts towards recommendations relative to similar joints
+	CategoryAffinityWeight float64
+	// TrendingBaselineWindows is the number of windows before the current one averaged into a joint's usual activity
+	TrendingBaselineWindows int
+	// JointActivityRetention is how long the hourly activity of joints is kept. It should cover the longest trending window along with its baseline
+	JointActivityRetention time.Duration
+	// AppURL is the address of the client app that links in emails point to
+	AppURL string
+	// TrustedProxies are the IPs or CIDRs of the reverse proxies whose X-Forwarded-For header is used as the client IP. No proxy is trusted by default
+	TrustedProxies []string
+	// LoginFreeAttempts is the number of failed logins to an account after which every further attempt must wait. The wait starts at LoginDelay and doubles with each failure up to LoginMaxDelay
+	LoginFreeAttempts int
+	LoginDelay        time.Duration
+	LoginMaxDelay     time.Duration
+	// LoginLockoutThreshold is the number of failed logins that locks an account for LoginLockoutDuration. The owner is emailed a link to unlock it sooner
+	LoginLockoutThreshold int
+	LoginLockoutDuration  time.Duration
+	// LoginAttemptWindow is how long failed logins count towards delays and lockouts
+	LoginAttemptWindow time.Duration
+	// LoginIPThreshold is the number of failed logins from an IP address within the attempt window after which the address cannot log in to any account
+	LoginIPThreshold int
+	// TwoFactorRequiredRoles are the roles whose privileges only apply to sessions that completed two-factor authentication
+	TwoFactorRequiredRoles []string
+	// TwoFactorKey encrypts the two-factor secrets of users and keys the hashes of their recovery codes. It defaults to the JWT secret
+	TwoFactorKey string
+	// PreAuthTokenExpiry is how long users have to enter their two-factor code after their password
+	PreAuthTokenExpiry time.Duration
+	// OIDCProviders are the providers users can sign in with. They are listed in OIDC_PROVIDERS and each is configured with OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and _SCOPES
+	OIDCProviders []OIDCProvider
+	// OIDCRedirectURL is the page of the client app that providers send users back to. The app passes the code and state on to the api
+	OIDCRedirectURL string
+	// OIDCStateExpiry is how long users have to complete a sign in started with a provider
+	OIDCStateExpiry time.Duration
+	// APIKeyMaxLifetime is the longest a personal API key can be valid for. Keys created without an expiry get it too
+	APIKeyMaxLifetime time.Duration
+	// AccountDeletionGracePeriod is how long after asking for their account to be deleted users can change their mind before it is anonymised
+	AccountDeletionGracePeriod time.Duration
+	// DataExportPollInterval is how often pending exports of personal data are built
+	DataExportPollInterval time.Duration
+	// DataExportRetention is how long an export of personal data can be downloaded once it is built
+	DataExportRetention time.Duration
+	// SecurityEventRetention is how long the logins and other security events of accounts are kept
+	SecurityEventRetention time.Duration
+	// RateLimitBackend is where the rate limits are tracked. Limits kept in memory only apply to a single instance while postgres shares them between instances
+	RateLimitBackend string
+	// RateLimits maps a rate limit policy to the requests a client is allowed per period. Routes whose policy is not listed are not limited
+	RateLimits map[string]RateLimit
 }
 
 // New returns a config object from the env and a non-nil error if the env value is not present
@@ -34,10 +196,127 @@ func New() (*Config, error) {
 	dbHost := getEnv("DB_HOST", "localhost")
 
 	// server configs
+	environment := getEnv("APP_ENV", DevelopmentEnvironment)
 	port := getEnvInt("PORT", 8000)
-	jwtSecret := getEnv("JWT_SECRET", "random-token")
+	jwtSecret := getEnv("JWT_SECRET", DefaultJWTSecret)
 	jwtExpiry := getEnvInt("JWT_EXPIRY_MINUTES", 60)
+	jwtAlgorithm := getEnv("JWT_ALGORITHM", EdDSAAlgorithm)
+	jwtIssuer := getEnv("JWT_ISSUER", "chow")
+	jwtAudience := getEnv("JWT_AUDIENCE", "chow-api")
+	signingKeyRefreshInterval := getEnvInt("SIGNING_KEY_REFRESH_SECONDS", 60)
+	encryptionKey := getEnv("ENCRYPTION_KEY", jwtSecret)
 	maxRadius := getEnvFloat("MAX_RADIUS_METERS", 2000)
+	cursorSecret := getEnv("CURSOR_SECRET", jwtSecret)
+	appURL := getEnv("APP_URL", "http://localhost:3000")
+	trustedProxies := getEnvList("TRUSTED_PROXIES", "")
+
+	// login protection configs
+	loginFreeAttempts := getEnvInt("LOGIN_FREE_ATTEMPTS", 3)
+	loginDelay := getEnvInt("LOGIN_DELAY_SECONDS", 1)
+	loginMaxDelay := getEnvInt("LOGIN_MAX_DELAY_SECONDS", 60)
+	loginLockoutThreshold := getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 10)
+	loginLockoutDuration := getEnvInt("LOGIN_LOCKOUT_MINUTES", 30)
+	loginAttemptWindow := getEnvInt("LOGIN_ATTEMPT_WINDOW_MINUTES", 60)
+	loginIPThreshold := getEnvInt("LOGIN_IP_THRESHOLD", 50)
+	securityEventRetention := getEnvInt("SECURITY_EVENT_RETENTION_DAYS", 90)
+
+	if jwtAlgorithm != EdDSAAlgorithm && jwtAlgorithm != RS256Algorithm {
+		return nil, fmt.Errorf("JWT_ALGORITHM must be %s or %s", EdDSAAlgorithm, RS256Algorithm)
+	}
+
+	// two-factor configs
+	twoFactorRequiredRoles := getEnvList("TWO_FACTOR_REQUIRED_ROLES", "admin,moderator")
+	twoFactorKey := getEnv("TWO_FACTOR_KEY", jwtSecret)
+	preAuthTokenExpiry := getEnvInt("PRE_AUTH_TOKEN_EXPIRY_MINUTES", 5)
+
+	// openid connect configs
+	oidcProviders, err := getEnvOIDCProviders("OIDC_PROVIDERS")
+	if err != nil {
+		return nil, err
+	}
+	oidcRedirectURL := getEnv("OIDC_REDIRECT_URL", appURL+"/auth/callback")
+	oidcStateExpiry := getEnvInt("OIDC_STATE_EXPIRY_MINUTES", 10)
+
+	// api key configs
+	apiKeyMaxLifetime := getEnvInt("API_KEY_MAX_LIFETIME_DAYS", 365)
+
+	// personal data configs
+	accountDeletionGracePeriod := getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 30)
+	dataExportPollInterval := getEnvInt("DATA_EXPORT_POLL_INTERVAL_SECONDS", 30)
+	dataExportRetention := getEnvInt("DATA_EXPORT_RETENTION_DAYS", 7)
+
+	// secrets left at the public development default would let anyone forge cursors and decrypt the stored keys
+	if environment == ProductionEnvironment {
+		secrets := []struct{ name, value string }{
+			{"JWT_SECRET", jwtSecret},
+			{"CURSOR_SECRET", cursorSecret},
+			{"TWO_FACTOR_KEY", twoFactorKey},
+			{"ENCRYPTION_KEY", encryptionKey},
+		}
+		for _, secret := range secrets {
+			if secret.value == DefaultJWTSecret {
+				return nil, fmt.Errorf("%s must be changed from its default in production", secret.name)
+			}
+		}
+	}
+
+	// moderation configs
+	complaintHideThresholds := getEnvIntMap("COMPLAINT_HIDE_THRESHOLDS", DefaultComplaintHideThresholds)
+	complaintHideWindow := getEnvInt("COMPLAINT_HIDE_WINDOW_HOURS", 168)
+	complaintReviewInterval := getEnvInt("COMPLAINT_REVIEW_INTERVAL_MINUTES", 15)
+
+	// retention configs
+	jointRetention := getEnvInt("JOINT_RETENTION_DAYS", 30)
+	purgeInterval := getEnvInt("PURGE_INTERVAL_MINUTES", 60)
+
+	// event configs
+	outboxPollInterval := getEnvInt("OUTBOX_POLL_INTERVAL_MS", 1000)
+	outboxMaxAttempts := getEnvInt("OUTBOX_MAX_ATTEMPTS", 10)
+	outboxRetention := getEnvInt("OUTBOX_RETENTION_DAYS", 7)
+
+	// webhook configs
+	webhookPollInterval := getEnvInt("WEBHOOK_POLL_INTERVAL_SECONDS", 5)
+	webhookTimeout := getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10)
+	webhookMaxAttempts := getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8)
+	webhookDisableAfter := getEnvInt("WEBHOOK_DISABLE_AFTER_FAILURES", 20)
+
+	// mail configs
+	smtpHost := getEnv("SMTP_HOST", "")
+	smtpPort := getEnvInt("SMTP_PORT", 587)
+	smtpUsername := getEnv("SMTP_USERNAME", "")
+	smtpPassword := getEnv("SMTP_PASSWORD", "")
+	mailFrom := getEnv("MAIL_FROM", "Chow <no-reply@chow.app>")
+
+	// alert configs
+	savedSearchDigestInterval := getEnvInt("SAVED_SEARCH_DIGEST_INTERVAL_HOURS", 24)
+
+	// check-in configs
+	checkinTolerance := getEnvFloat("CHECKIN_TOLERANCE_METERS", 100)
+	checkinCooldown := getEnvInt("CHECKIN_COOLDOWN_MINUTES", 60)
+	verifiedVoteWeight := getEnvFloat("VERIFIED_VOTE_WEIGHT", 2)
+	trustedVoteWeight := getEnvFloat("TRUSTED_VOTE_WEIGHT", 1.5)
+	voteReconcileInterval := getEnvInt("VOTE_RECONCILE_INTERVAL_HOURS", 24)
+
+	// vote integrity configs
+	voteClusterWindow := getEnvInt("VOTE_CLUSTER_WINDOW_HOURS", 72)
+	voteClusterMinSize := getEnvInt("VOTE_CLUSTER_MIN_SIZE", 3)
+	voteBurstWindow := getEnvInt("VOTE_BURST_WINDOW_MINUTES", 60)
+	voteBurstThreshold := getEnvInt("VOTE_BURST_THRESHOLD", 20)
+	voteNewAccountAge := getEnvInt("VOTE_NEW_ACCOUNT_DAYS", 7)
+
+	// recommendation configs
+	recommendationInterval := getEnvInt("RECOMMENDATION_INTERVAL_HOURS", 6)
+	recommendationNeighbours := getEnvInt("RECOMMENDATION_NEIGHBOURS", 50)
+	recommendationMinInteractions := getEnvInt("RECOMMENDATION_MIN_INTERACTIONS", 3)
+	categoryAffinityWeight := getEnvFloat("CATEGORY_AFFINITY_WEIGHT", 0.5)
+
+	// trending configs
+	trendingBaselineWindows := getEnvInt("TRENDING_BASELINE_WINDOWS", 4)
+	jointActivityRetention := getEnvInt("JOINT_ACTIVITY_RETENTION_DAYS", 180)
+
+	// rate limit configs
+	rateLimitBackend := getEnv("RATE_LIMIT_BACKEND", MemoryRateLimitBackend)
+	rateLimits := getEnvRateLimits("RATE_LIMITS", DefaultRateLimits)
 
 	return &Config{
 		Db:               db,
@@ -46,9 +325,91 @@ func New() (*Config, error) {
 		DbPort:           dbPort,
 		DbHost:           dbHost,
 		Port:             port,
+		Environment:      environment,
 		JWTSecret:        jwtSecret,
 		JWTExpiryMinutes: time.Duration(jwtExpiry) * time.Minute,
-		MaxNearbyRadius:  maxRadius,
+
+		JWTAlgorithm:              jwtAlgorithm,
+		JWTIssuer:                 jwtIssuer,
+		JWTAudience:               jwtAudience,
+		SigningKeyRefreshInterval: time.Duration(signingKeyRefreshInterval) * time.Second,
+		EncryptionKey:             encryptionKey,
+
+		MaxNearbyRadius: maxRadius,
+		CursorSecret:    cursorSecret,
+		AppURL:          appURL,
+		TrustedProxies:  trustedProxies,
+
+		LoginFreeAttempts:      loginFreeAttempts,
+		LoginDelay:             time.Duration(loginDelay) * time.Second,
+		LoginMaxDelay:          time.Duration(loginMaxDelay) * time.Second,
+		LoginLockoutThreshold:  loginLockoutThreshold,
+		LoginLockoutDuration:   time.Duration(loginLockoutDuration) * time.Minute,
+		LoginAttemptWindow:     time.Duration(loginAttemptWindow) * time.Minute,
+		LoginIPThreshold:       loginIPThreshold,
+		SecurityEventRetention: time.Duration(securityEventRetention) * 24 * time.Hour,
+
+		TwoFactorRequiredRoles: twoFactorRequiredRoles,
+		TwoFactorKey:           twoFactorKey,
+		PreAuthTokenExpiry:     time.Duration(preAuthTokenExpiry) * time.Minute,
+
+		OIDCProviders:   oidcProviders,
+		OIDCRedirectURL: oidcRedirectURL,
+		OIDCStateExpiry: time.Duration(oidcStateExpiry) * time.Minute,
+
+		APIKeyMaxLifetime: time.Duration(apiKeyMaxLifetime) * 24 * time.Hour,
+
+		AccountDeletionGracePeriod: time.Duration(accountDeletionGracePeriod) * 24 * time.Hour,
+		DataExportPollInterval:     time.Duration(dataExportPollInterval) * time.Second,
+		DataExportRetention:        time.Duration(dataExportRetention) * 24 * time.Hour,
+
+		ComplaintHideThresholds: complaintHideThresholds,
+		ComplaintHideWindow:     time.Duration(complaintHideWindow) * time.Hour,
+		ComplaintReviewInterval: time.Duration(complaintReviewInterval) * time.Minute,
+
+		JointRetention: time.Duration(jointRetention) * 24 * time.Hour,
+		PurgeInterval:  time.Duration(purgeInterval) * time.Minute,
+
+		OutboxPollInterval: time.Duration(outboxPollInterval) * time.Millisecond,
+		OutboxMaxAttempts:  outboxMaxAttempts,
+		OutboxRetention:    time.Duration(outboxRetention) * 24 * time.Hour,
+
+		WebhookPollInterval: time.Duration(webhookPollInterval) * time.Second,
+		WebhookTimeout:      time.Duration(webhookTimeout) * time.Second,
+		WebhookMaxAttempts:  webhookMaxAttempts,
+		WebhookDisableAfter: webhookDisableAfter,
+
+		SMTPHost:     smtpHost,
+		SMTPPort:     smtpPort,
+		SMTPUsername: smtpUsername,
+		SMTPPassword: smtpPassword,
+		MailFrom:     mailFrom,
+
+		SavedSearchDigestInterval: time.Duration(savedSearchDigestInterval) * time.Hour,
+
+		CheckinTolerance:   checkinTolerance,
+		CheckinCooldown:    time.Duration(checkinCooldown) * time.Minute,
+		VerifiedVoteWeight: verifiedVoteWeight,
+		TrustedVoteWeight:  trustedVoteWeight,
+
+		VoteReconcileInterval: time.Duration(voteReconcileInterval) * time.Hour,
+
+		VoteClusterWindow:  time.Duration(voteClusterWindow) * time.Hour,
+		VoteClusterMinSize: voteClusterMinSize,
+		VoteBurstWindow:    time.Duration(voteBurstWindow) * time.Minute,
+		VoteBurstThreshold: voteBurstThreshold,
+		VoteNewAccountAge:  time.Duration(voteNewAccountAge) * 24 * time.Hour,
+
+		RecommendationInterval:        time.Duration(recommendationInterval) * time.Hour,
+		RecommendationNeighbours:      recommendationNeighbours,
+		RecommendationMinInteractions: recommendationMinInteractions,
+		CategoryAffinityWeight:        categoryAffinityWeight,
+
+		TrendingBaselineWindows: trendingBaselineWindows,
+		JointActivityRetention:  time.Duration(jointActivityRetention) * 24 * time.Hour,
+
+		RateLimitBackend: rateLimitBackend,
+		RateLimits:       rateLimits,
 	}, nil
 }
 
@@ -83,3 +444,82 @@ func getEnvFloat(key string, fallback float64) float64 {
 	}
 	return floatVal
 }
+
+// getEnvList parses a comma separated list. Empty items are skipped
+func getEnvList(key string, fallback string) []string {
+	val := getEnv(key, fallback)
+
+	var result []string
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// getEnvIntMap parses a comma separated list of key:value pairs with integer values. Malformed pairs are skipped
+func getEnvIntMap(key string, fallback string) map[string]int {
+	val := getEnv(key, fallback)
+
+	result := make(map[string]int)
+	for _, pair := range strings.Split(val, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+		intVal, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		result[strings.TrimSpace(k)] = intVal
+	}
+	return result
+}
+
+// getEnvRateLimits parses a comma separated list of policy:limit/period pairs such as "auth:10/15m". Malformed pairs are skipped
+func getEnvRateLimits(key string, fallback string) map[string]RateLimit {
+	val := getEnv(key, fallback)
+
+	result := make(map[string]RateLimit)
+	for _, pair := range strings.Split(val, ",") {
+		policy, rate, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			continue
+		}
+		limit, period, ok := strings.Cut(rate, "/")
+		if !ok {
+			continue
+		}
+		limitVal, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || limitVal <= 0 {
+			continue
+		}
+		periodVal, err := time.ParseDuration(strings.TrimSpace(period))
+		if err != nil || periodVal <= 0 {
+			continue
+		}
+		result[strings.TrimSpace(policy)] = RateLimit{Limit: limitVal, Period: periodVal}
+	}
+	return result
+}
+
+// getEnvOIDCProviders reads the providers named in a comma separated list. Each provider needs an issuer and client ID under OIDC_<NAME>_ISSUER and OIDC_<NAME>_CLIENT_ID
+func getEnvOIDCProviders(key string) ([]OIDCProvider, error) {
+	var providers []OIDCProvider
+	for _, name := range getEnvList(key, "") {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProvider{
+			Name:         strings.ToLower(name),
+			Issuer:       strings.TrimSuffix(getEnv(prefix+"ISSUER", ""), "/"),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID must be set for the %s provider", prefix, prefix, name)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// RequestDataExport godoc
+// @Summary Request data export
+// @Description Start building an archive of the personal data of the authenticated user: their profile, joints and photos, votes, complaints and replies, reviews, check-ins, reputation, saved searches, notifications and account security records.
+// @Description The archive is built in the background and the user is emailed when it can be downloaded
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} model.SuccessResponse{data=model.DataExport} "Data export requested successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 409 {object} model.ErrorResponse "Data export already in progress"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/export [post]
+func (h *AccountHandler) RequestDataExport(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.accountService.RequestDataExport(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to request data export")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.SuccessResponse{Message: translate(c, "Data export requested successfully"), Data: export})
+}
+
+// GetDataExports godoc
+// @Summary Get data exports
+// @Description Get the data exports of the authenticated user, newest first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.DataExport} "Data exports retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/export [get]
+func (h *AccountHandler) GetDataExports(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	exports, err := h.accountService.GetDataExports(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve data exports")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Data exports retrieved successfully"), Data: exports})
+}
+
+// GetDataExport godoc
+// @Summary Get data export
+// @Description Get the status of a data export of the authenticated user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Data export ID"
+// @Success 200 {object} model.SuccessResponse{data=model.DataExport} "Data export retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Data export not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/export/{id} [get]
+func (h *AccountHandler) GetDataExport(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate data export ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.accountService.GetDataExport(c.Request.Context(), user.ID, param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve data export")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Data export retrieved successfully"), Data: export})
+}
+
+// DownloadDataExport godoc
+// @Summary Download data export
+// @Description Download the zip archive of a completed data export of the authenticated user. The archive holds a JSON file for each kind of data and can be downloaded until the export expires
+// @Tags users
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path string true "Data export ID"
+// @Success 200 {file} file "Zip archive of the user's data"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Data export not found or expired"
+// @Failure 409 {object} model.ErrorResponse "Data export not ready"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/export/{id}/download [get]
+func (h *AccountHandler) DownloadDataExport(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate data export ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	archive, err := h.accountService.DownloadDataExport(c.Request.Context(), user.ID, param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to download data export")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="chow-data-%s.zip"`, archive.CreatedAt.Format("2006-01-02")))
+	c.Data(http.StatusOK, "application/zip", archive.Archive)
+}
+
+// DeleteAccount godoc
+// @Summary Delete account
+// @Description Schedule the account of the authenticated user to be deleted once the grace period ends. Users confirm with a code when they have two-factor authentication, otherwise with their password.
+// @Description When it is deleted, the joints, votes and complaints of the account are kept without the user's name and the rest of their personal data is removed. The deletion can be cancelled until then
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.DeleteAccountReq true "Identity confirmation"
+// @Success 202 {object} model.SuccessResponse{data=model.DeleteAccountRes} "Account deletion scheduled"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated or invalid credentials"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 423 {object} model.ErrorResponse "Account locked"
+// @Failure 429 {object} model.ErrorResponse "Too many attempts"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	var req model.DeleteAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.accountService.DeleteAccount(c.Request.Context(), user.ID, req)
+	if err != nil {
+		respondError(c, err, "Failed to delete account")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.SuccessResponse{Message: translate(c, "Account deletion scheduled"), Data: res})
+}
+
+// CancelAccountDeletion godoc
+// @Summary Cancel account deletion
+// @Description Keep the account of the authenticated user that was scheduled for deletion
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse "Account deletion cancelled"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 409 {object} model.ErrorResponse "Account not scheduled for deletion"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/deletion [delete]
+func (h *AccountHandler) CancelAccountDeletion(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.accountService.CancelAccountDeletion(c.Request.Context(), user.ID); err != nil {
+		respondError(c, err, "Failed to cancel account deletion")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Account deletion cancelled")})
+}
+
+// getAuthUser retrieves the authenticated user or returns an error if the user is not authenticated
+func (h *AccountHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	return &user, true
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Create a personal API key for an integration to authenticate as the authenticated user with the "ApiKey" authorization scheme. The key is only returned once.
+// @Description Keys with the joints:read scope can read joints, with votes:write can vote and with admin can use the admin routes as well as every other scope. Only admins and moderators can create admin keys, and those whose role requires two-factor authentication must have it enabled
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CreateAPIKeyReq true "Key details"
+// @Success 201 {object} model.SuccessResponse{data=model.CreateAPIKeyRes} "API key created successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid expiry"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "Admin scope not allowed"
+// @Failure 409 {object} model.ErrorResponse "API key limit reached or two-factor authentication not enabled"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/api-keys [post]
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	var req model.CreateAPIKeyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.authService.CreateAPIKey(c.Request.Context(), *user, req)
+	if err != nil {
+		respondError(c, err, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "API key created successfully"), Data: res})
+}
+
+// GetAPIKeys godoc
+// @Summary Get API keys
+// @Description Get the personal API keys of the authenticated user, including revoked and expired ones
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.APIKey} "API keys retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/api-keys [get]
+func (h *AuthHandler) GetAPIKeys(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.authService.GetAPIKeys(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "API keys retrieved successfully"), Data: keys})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Stop a personal API key of the authenticated user from being used
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} model.SuccessResponse "API key revoked successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "API key not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/api-keys/{id} [delete]
+func (h *AuthHandler) RevokeAPIKey(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate API key ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(c.Request.Context(), user.ID, param.GetID()); err != nil {
+		respondError(c, err, "Failed to revoke API key")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "API key revoked successfully")})
+}
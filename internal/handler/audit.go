@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// GetAuditEvents godoc
+// @Summary Get audit events
+// @Description Get audit events matching the filters, newest first. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request query model.AuditEventQuery false "Filters and cursor"
+// @Success 200 {object} model.SuccessResponse{data=model.AuditEventPage} "Audit events retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/audit-events [get]
+func (h *AuditHandler) GetAuditEvents(c *gin.Context) {
+	var query model.AuditEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	page, pagination, err := h.auditService.GetAuditEvents(c.Request.Context(), query.GetFilter(), query.PaginationQuery)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve audit events")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Audit events retrieved successfully"), Data: page, Pagination: pagination})
+}
+
+// ExportAuditEvents godoc
+// @Summary Export audit events
+// @Description Stream all audit events matching the filters as JSON lines, oldest first. Admins only Endpoint
+// @Tags admin
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request query model.AuditEventQuery false "Filters"
+// @Success 200 {string} string "One audit event per line"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Router /admin/audit-events/export [get]
+func (h *AuditHandler) ExportAuditEvents(c *gin.Context) {
+	var query model.AuditEventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Header("Content-Disposition", `attachment; filename="audit-events.jsonl"`)
+	c.Status(http.StatusOK)
+
+	// the response is streamed so errors after this point can only be logged
+	encoder := json.NewEncoder(c.Writer)
+	err := h.auditService.ExportAuditEvents(c.Request.Context(), query.GetFilter(), func(event *model.AuditEvent) error {
+		return encoder.Encode(event)
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// getAuthAdmin retrieves the authenticated admin or returns an error if the user is not an admin
+func (h *AuditHandler) getAuthAdmin(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	if _, ok := GetCurrentUser(c); !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	user, ok := GetCurrentAdmin(c)
+	if !ok {
+		respondError(c, forbidden("User is not an admin"), "")
+		return nil, false
+	}
+	return &user, true
+}
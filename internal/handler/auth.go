@@ -3,8 +3,6 @@ package handler
 import (
 	"chow/internal/model"
 	"chow/internal/service"
-	"errors"
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,71 +20,132 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and return access token
+// @Description Authenticate user and return access token. Users with two-factor authentication get a pre-auth token to exchange for the access token at /auth/2fa/verify instead
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body model.LoginUserReq true "Login credentials"
-// @Success 200 {object} model.SuccessResponse{data=model.LoginUserRes} "Login successful"
+// @Success 200 {object} model.SuccessResponse{data=model.LoginUserRes} "Login successful or two-factor code required"
 // @Failure 401 {object} model.ErrorResponse "Invalid credentials"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 423 {object} model.ErrorResponse "Account temporarily locked after too many failed logins"
+// @Failure 429 {object} model.ErrorResponse "Too many requests or failed logins"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	// validate data
 	var req model.LoginUserReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate data", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate data")
 		return
 	}
 
 	// login user
-	user, accessToken, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
+	res, err := h.authService.Login(c.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidCredentials) {
-			c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: err.Error()})
-			return
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to login"})
+		respondError(c, err, "Failed to login")
 		return
 	}
 
-	res := model.LoginUserRes{User: *user, AccessToken: accessToken}
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Login successful", Data: res})
+	message := "Login successful"
+	if res.TwoFactorRequired {
+		message = "Two-factor code required"
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, message), Data: res})
 }
 
 // Register godoc
 // @Summary Register new user
-// @Description Create a new user account
+// @Description Create a new user account. The response is the same whether or not the email is already registered, and the owner of the email is told what happened by email
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body model.RegisterUserReq true "User registration details"
-// @Success 201 {object} model.SuccessResponse{data=model.User} "Account registration successful"
-// @Failure 401 {object} model.ErrorResponse "User already exists"
+// @Success 202 {object} model.SuccessResponse "Registration received"
+// @Failure 409 {object} model.ErrorResponse "Username already taken"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	// validate data
 	var req model.RegisterUserReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate data", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate data")
 		return
 	}
 
 	// register user
-	user, err := h.authService.Register(c.Request.Context(), &model.User{Email: req.Email, Username: req.Username, Password: req.Password})
+	if err := h.authService.Register(c.Request.Context(), &model.User{Email: req.Email, Username: req.Username, Password: req.Password}); err != nil {
+		respondError(c, err, "Failed to register account")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.SuccessResponse{Message: translate(c, "Registration received, check your email for next steps")})
+}
+
+// UnlockAccount godoc
+// @Summary Unlock account
+// @Description Lift the lockout of an account with the token emailed to its owner when it was locked
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.UnlockAccountReq true "Unlock token"
+// @Success 200 {object} model.SuccessResponse "Account unlocked successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid or expired unlock token"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/unlock [post]
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	var req model.UnlockAccountReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	if err := h.authService.UnlockAccount(c.Request.Context(), req.Token); err != nil {
+		respondError(c, err, "Failed to unlock account")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Account unlocked successfully")})
+}
+
+// GetSecurityEvents godoc
+// @Summary Get security events
+// @Description Get the recent logins, lockouts and other security events of the authenticated user's account with the IP address and device they came from, newest first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.SecurityEvent} "Security events retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/security-events [get]
+func (h *AuthHandler) GetSecurityEvents(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	events, page, err := h.authService.GetSecurityEvents(c.Request.Context(), user.ID, pagination)
 	if err != nil {
-		if errors.Is(err, service.ErrAlreadyExist) {
-			c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: err.Error()})
-			return
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to register account"})
+		respondError(c, err, "Failed to retrieve security events")
 		return
 	}
 
-	c.JSON(http.StatusCreated, model.SuccessResponse{Message: "Account registration successful", Data: user})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Security events retrieved successfully"), Data: events, Pagination: page})
 }
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckinHandler struct {
+	checkinService *service.CheckinService
+}
+
+func NewCheckinHandler(checkinService *service.CheckinService) *CheckinHandler {
+	return &CheckinHandler{
+		checkinService: checkinService,
+	}
+}
+
+// CreateCheckin godoc
+// @Summary Check in at a joint
+// @Description Record a visit to a joint using the coordinates of the device. The coordinates must be within the configured tolerance of the joint. Votes of users who have checked in are flagged as verified and weighted higher
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Joint ID"
+// @Param request body model.CreateCheckinReq true "Device coordinates"
+// @Success 201 {object} model.SuccessResponse{data=model.Checkin} "Checked in successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "Too far from the joint"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Checked in recently or too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/checkins [post]
+func (h *CheckinHandler) CreateCheckin(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate joint ID")
+		return
+	}
+
+	var req model.CreateCheckinReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	checkin, err := h.checkinService.CreateCheckin(c.Request.Context(), user.ID, param.GetID(), model.Coordinate{Latitude: req.Latitude, Longitude: req.Longitude})
+	if err != nil {
+		respondError(c, err, "Failed to check in")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Checked in successfully"), Data: checkin})
+}
+
+// GetVisitHistory godoc
+// @Summary Get visit history
+// @Description Get the check-ins of the authenticated user, newest first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.Visit} "Visit history retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/checkins [get]
+func (h *CheckinHandler) GetVisitHistory(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	visits, page, err := h.checkinService.GetVisitHistory(c.Request.Context(), user.ID, pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve visit history")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Visit history retrieved successfully"), Data: visits, Pagination: page})
+}
+
+// getAuthUser retrieves the authenticated user or return an unauthorized error if user is not present
+func (h *CheckinHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	return &user, true
+}
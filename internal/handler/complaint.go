@@ -3,7 +3,6 @@ package handler
 import (
 	"chow/internal/model"
 	"chow/internal/service"
-	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -28,17 +27,19 @@ func NewComplaintHandler(complaintService *service.ComplaintService) *ComplaintH
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
 // @Success 200 {object} model.SuccessResponse{data=[]model.Complaint} "Complaints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /complaints [get]
 func (h *ComplaintHandler) GetAllComplaints(c *gin.Context) {
 	var pagination model.PaginationQuery
 	if err := c.ShouldBind(&pagination); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate pagination params", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate pagination params")
 		return
 	}
-	offset, limit := pagination.GetOffsetAndLimit()
 
 	// verify info from auth context
 	_, ok := h.getAuthAdminOrModerator(c)
@@ -46,14 +47,13 @@ func (h *ComplaintHandler) GetAllComplaints(c *gin.Context) {
 		return
 	}
 
-	complaints, err := h.complaintService.GetAllComplaints(c.Request.Context(), offset, limit)
+	complaints, page, err := h.complaintService.GetAllComplaints(c.Request.Context(), pagination)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to retrieve complaints"})
+		respondError(c, err, "Failed to retrieve complaints")
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Complaints retrieved successfully", Data: complaints})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Complaints retrieved successfully"), Data: complaints, Pagination: page})
 }
 
 // GetComplaint godoc
@@ -72,7 +72,7 @@ func (h *ComplaintHandler) GetAllComplaints(c *gin.Context) {
 func (h *ComplaintHandler) GetComplaint(c *gin.Context) {
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate complaint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate complaint ID")
 		return
 	}
 
@@ -83,12 +83,11 @@ func (h *ComplaintHandler) GetComplaint(c *gin.Context) {
 
 	complaint, err := h.complaintService.GetComplaintByID(c.Request.Context(), param.GetID())
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to retrieve complaint"})
+		respondError(c, err, "Failed to retrieve complaint")
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Complaint retrieved successfully", Data: complaint})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Complaint retrieved successfully"), Data: complaint})
 }
 
 // GetUserComplaints godoc
@@ -100,7 +99,10 @@ func (h *ComplaintHandler) GetComplaint(c *gin.Context) {
 // @Security BearerAuth
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
 // @Success 200 {object} model.SuccessResponse{data=[]model.Complaint} "User complaints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
 // @Failure 401 {object} model.ErrorResponse "User not authenticated"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
 // @Failure 500 {object} model.ErrorResponse "Server error"
@@ -108,10 +110,7 @@ func (h *ComplaintHandler) GetComplaint(c *gin.Context) {
 func (h *ComplaintHandler) GetUserComplaints(c *gin.Context) {
 	var pagination model.PaginationQuery
 	if err := c.ShouldBind(&pagination); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{
-			Message: "Failed to validate pagination params",
-			Detail:  err.Error(),
-		})
+		respondValidationError(c, err, "Failed to validate pagination params")
 		return
 	}
 
@@ -121,19 +120,16 @@ func (h *ComplaintHandler) GetUserComplaints(c *gin.Context) {
 		return
 	}
 
-	offset, limit := pagination.GetOffsetAndLimit()
-	complaints, err := h.complaintService.GetUserComplaints(c.Request.Context(), user.ID, offset, limit)
+	complaints, page, err := h.complaintService.GetUserComplaints(c.Request.Context(), user.ID, pagination)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Message: "Failed to retrieve user complaints",
-		})
+		respondError(c, err, "Failed to retrieve user complaints")
 		return
 	}
 
 	c.JSON(http.StatusOK, model.SuccessResponse{
-		Message: "User complaints retrieved successfully",
-		Data:    complaints,
+		Message:    translate(c, "User complaints retrieved successfully"),
+		Data:       complaints,
+		Pagination: page,
 	})
 }
 
@@ -155,7 +151,7 @@ func (h *ComplaintHandler) GetUserComplaints(c *gin.Context) {
 func (h *ComplaintHandler) ResolveComplaint(c *gin.Context) {
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate complaint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate complaint ID")
 		return
 	}
 
@@ -166,12 +162,162 @@ func (h *ComplaintHandler) ResolveComplaint(c *gin.Context) {
 
 	complaint, err := h.complaintService.UpdateComplaintStatusByID(c.Request.Context(), param.GetID(), model.ResolvedComplaint)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to resolve complaint"})
+		respondError(c, err, "Failed to resolve complaint")
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Complaint updated successfully", Data: complaint})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Complaint updated successfully"), Data: complaint})
+}
+
+// RejectComplaint godoc
+// @Summary Reject complaint
+// @Description Rejects a specific complaint as invalid. Admins-only Endpoint
+// @Tags complaints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Complaint ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Complaint} "Complaint updated successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Complaint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /complaints/{id}/reject [patch]
+func (h *ComplaintHandler) RejectComplaint(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate complaint ID")
+		return
+	}
+
+	// verify info from auth context
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
+		return
+	}
+
+	complaint, err := h.complaintService.UpdateComplaintStatusByID(c.Request.Context(), param.GetID(), model.RejectedComplaint)
+	if err != nil {
+		respondError(c, err, "Failed to reject complaint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Complaint updated successfully"), Data: complaint})
+}
+
+// CreateComplaintReply godoc
+// @Summary Reply to complaint
+// @Description Add a reply to a complaint. Only the complainant and moderators can reply
+// @Tags complaints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Complaint ID"
+// @Param request body model.CreateComplaintReplyReq true "Reply details"
+// @Success 201 {object} model.SuccessResponse{data=model.ComplaintReply} "Reply added successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Complaint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /complaints/{id}/replies [post]
+func (h *ComplaintHandler) CreateComplaintReply(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate complaint ID")
+		return
+	}
+
+	var req model.CreateComplaintReplyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	complaint, ok := h.getParticipatingComplaint(c, param, user)
+	if !ok {
+		return
+	}
+
+	reply, err := h.complaintService.CreateComplaintReply(c.Request.Context(), complaint, &model.ComplaintReply{UserID: user.ID, Body: req.Body})
+	if err != nil {
+		respondError(c, err, "Failed to add reply")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Reply added successfully"), Data: reply})
+}
+
+// GetComplaintReplies godoc
+// @Summary Get complaint replies
+// @Description Get the replies on a complaint in the order they were made. Only the complainant and moderators can view replies
+// @Tags complaints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Complaint ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.ComplaintReply} "Replies retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Complaint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /complaints/{id}/replies [get]
+func (h *ComplaintHandler) GetComplaintReplies(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate complaint ID")
+		return
+	}
+
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := h.getParticipatingComplaint(c, param, user); !ok {
+		return
+	}
+
+	replies, page, err := h.complaintService.GetComplaintReplies(c.Request.Context(), param.GetID(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve replies")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Replies retrieved successfully"), Data: replies, Pagination: page})
+}
+
+// getParticipatingComplaint retrieves a complaint the user can take part in, which is their own complaint or any complaint for moderators
+func (h *ComplaintHandler) getParticipatingComplaint(c *gin.Context, param model.IDParam, user *model.AuthenticatedUser) (*model.Complaint, bool) {
+	complaint, err := h.complaintService.GetComplaintByID(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve complaint")
+		return nil, false
+	}
+
+	if complaint.UserID != user.ID && user.Role != model.Admin && user.Role != model.Moderator {
+		respondError(c, forbidden("User not authorized to access this complaint"), "")
+		return nil, false
+	}
+	return complaint, true
 }
 
 // getAuthUser retrieves the authenticated user or return an unauthorized error if user is not present
@@ -179,7 +325,7 @@ func (h *ComplaintHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser
 	// get user info from auth context
 	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: "User not authenticated"})
+		respondError(c, errUnauthenticated, "")
 		return nil, false
 	}
 	return &user, true
@@ -188,13 +334,13 @@ func (h *ComplaintHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser
 // getAuthAdmin retrieves the authenticated user or return an unauthorized error if user is not present
 func (h *ComplaintHandler) getAuthAdminOrModerator(c *gin.Context) (*model.AuthenticatedUser, bool) {
 	// get admin info from auth context
-	user, ok := GetCurrentAdmin(c)
+	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: "User is not authorized"})
+		respondError(c, unauthenticated("User is not authorized"), "")
 		return nil, false
 	}
 	if user.Role != model.Moderator && user.Role != model.Admin {
-		c.JSON(http.StatusForbidden, model.ErrorResponse{Message: "User is not an admin"})
+		respondError(c, forbidden("User is not an admin"), "")
 		return nil, false
 	}
 	return &user, true
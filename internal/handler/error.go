@@ -0,0 +1,309 @@
+package handler
+
+import (
+	"chow/internal/i18n"
+	"chow/internal/model"
+	"chow/internal/service"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// apiError is an error detected by the handlers themselves, with a fixed status and code
+type apiError struct {
+	status  int
+	code    model.ErrorCode
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+var (
+	errUnauthenticated = &apiError{status: http.StatusUnauthorized, code: model.UnauthenticatedCode, message: "User not authenticated"}
+	errForbidden       = &apiError{status: http.StatusForbidden, code: model.ForbiddenCode, message: "User not authorized"}
+	errRouteNotFound   = &apiError{status: http.StatusNotFound, code: model.RouteNotFoundCode, message: "Route not found"}
+)
+
+// unauthenticated returns an authentication error with a custom message
+func unauthenticated(message string) error {
+	return &apiError{status: http.StatusUnauthorized, code: model.UnauthenticatedCode, message: message}
+}
+
+// forbidden returns an authorization error with a custom message
+func forbidden(message string) error {
+	return &apiError{status: http.StatusForbidden, code: model.ForbiddenCode, message: message}
+}
+
+// errorCatalogue maps the service errors to their HTTP status and error code. Errors are matched in order with errors.Is
+var errorCatalogue = []struct {
+	err    error
+	status int
+	code   model.ErrorCode
+}{
+	{service.ErrInvalidCursor, http.StatusBadRequest, model.InvalidCursorCode},
+	{service.ErrMaxSearchRadiusExceeded, http.StatusBadRequest, model.RadiusTooLargeCode},
+	{service.ErrRateLimited, http.StatusTooManyRequests, model.RateLimitedCode},
+	{service.ErrInvalidCredentials, http.StatusUnauthorized, model.InvalidCredentialsCode},
+	{service.ErrLoginThrottled, http.StatusTooManyRequests, model.LoginThrottledCode},
+	{service.ErrAccountLocked, http.StatusLocked, model.AccountLockedCode},
+	{service.ErrInvalidUnlockToken, http.StatusBadRequest, model.InvalidUnlockTokenCode},
+	{service.ErrInvalidTwoFactorCode, http.StatusUnauthorized, model.InvalidTwoFactorCodeCode},
+	{service.ErrTwoFactorNotSetUp, http.StatusConflict, model.TwoFactorNotSetUpCode},
+	{service.ErrTwoFactorAlreadyEnabled, http.StatusConflict, model.TwoFactorAlreadyEnabledCode},
+	{service.ErrTwoFactorNotEnabled, http.StatusConflict, model.TwoFactorNotEnabledCode},
+	{service.ErrExpiredToken, http.StatusUnauthorized, model.TokenExpiredCode},
+	{service.ErrInvalidToken, http.StatusUnauthorized, model.InvalidTokenCode},
+	{service.ErrUserNotFound, http.StatusNotFound, model.UserNotFoundCode},
+	{service.ErrUsernameTaken, http.StatusConflict, model.UsernameTakenCode},
+	{service.ErrOIDCProviderNotFound, http.StatusNotFound, model.OIDCProviderNotFoundCode},
+	{service.ErrOIDCProviderDown, http.StatusBadGateway, model.OIDCProviderUnavailableCode},
+	{service.ErrInvalidOIDCState, http.StatusBadRequest, model.InvalidOIDCStateCode},
+	{service.ErrOIDCLoginFailed, http.StatusUnauthorized, model.OIDCLoginFailedCode},
+	{service.ErrOIDCEmailNotVerified, http.StatusForbidden, model.OIDCEmailNotVerifiedCode},
+	{service.ErrOIDCAccountExists, http.StatusConflict, model.OIDCAccountExistsCode},
+	{service.ErrIdentityNotFound, http.StatusNotFound, model.IdentityNotFoundCode},
+	{service.ErrIdentityLinked, http.StatusConflict, model.IdentityLinkedCode},
+	{service.ErrLastSignInMethod, http.StatusConflict, model.LastSignInMethodCode},
+	{service.ErrInvalidAPIKey, http.StatusUnauthorized, model.InvalidAPIKeyCode},
+	{service.ErrExpiredAPIKey, http.StatusUnauthorized, model.APIKeyExpiredCode},
+	{service.ErrInsufficientScope, http.StatusForbidden, model.InsufficientScopeCode},
+	{service.ErrAPIKeyNotFound, http.StatusNotFound, model.APIKeyNotFoundCode},
+	{service.ErrAPIKeyLimitReached, http.StatusConflict, model.APIKeyLimitReachedCode},
+	{service.ErrInvalidAPIKeyExpiry, http.StatusBadRequest, model.InvalidAPIKeyExpiryCode},
+	{service.ErrAdminScopeNotAllowed, http.StatusForbidden, model.AdminScopeNotAllowedCode},
+	{service.ErrDataExportNotFound, http.StatusNotFound, model.DataExportNotFoundCode},
+	{service.ErrDataExportInProgress, http.StatusConflict, model.DataExportInProgressCode},
+	{service.ErrDataExportNotReady, http.StatusConflict, model.DataExportNotReadyCode},
+	{service.ErrAccountDeletionNotScheduled, http.StatusConflict, model.DeletionNotScheduledCode},
+	{service.ErrNotEligibleForModerator, http.StatusConflict, model.NotEligibleForModeratorCode},
+	{service.ErrJointNotFound, http.StatusNotFound, model.JointNotFoundCode},
+	{service.ErrJointAlreadyExist, http.StatusConflict, model.JointAlreadyExistsCode},
+	{service.ErrJointAlreadyApproved, http.StatusConflict, model.JointAlreadyApprovedCode},
+	{service.ErrJointCannotDelete, http.StatusConflict, model.JointHasDependentsCode},
+	{service.ErrVoteNotFound, http.StatusNotFound, model.VoteNotFoundCode},
+	{service.ErrVoteVoided, http.StatusConflict, model.VoteVoidedCode},
+	{service.ErrCheckinTooFar, http.StatusForbidden, model.CheckinTooFarCode},
+	{service.ErrCheckinTooSoon, http.StatusTooManyRequests, model.CheckinTooSoonCode},
+	{service.ErrComplaintNotFound, http.StatusNotFound, model.ComplaintNotFoundCode},
+	{service.ErrComplaintAlreadyExist, http.StatusConflict, model.ComplaintAlreadyExistsCode},
+	{service.ErrNotificationNotFound, http.StatusNotFound, model.NotificationNotFoundCode},
+	{service.ErrSavedSearchNotFound, http.StatusNotFound, model.SavedSearchNotFoundCode},
+	{service.ErrSavedSearchLimitReached, http.StatusConflict, model.SavedSearchLimitReachedCode},
+	{service.ErrCityNotFound, http.StatusNotFound, model.CityNotFoundCode},
+	{service.ErrVoteReviewNotFound, http.StatusNotFound, model.VoteReviewNotFoundCode},
+	{service.ErrVoteReviewResolved, http.StatusConflict, model.VoteReviewResolvedCode},
+	{service.ErrWebhookNotFound, http.StatusNotFound, model.WebhookNotFoundCode},
+	{service.ErrWebhookDeliveryNotFound, http.StatusNotFound, model.WebhookDeliveryNotFoundCode},
+	{service.ErrTranslationNotFound, http.StatusNotFound, model.TranslationNotFoundCode},
+	{service.ErrDefaultLocaleTranslation, http.StatusConflict, model.DefaultLocaleTranslationCode},
+}
+
+// respondError aborts the request with the given error. The error middleware renders it from the catalogue, or as an internal error with the fallback message when it is not catalogued
+func respondError(c *gin.Context, err error, message string) {
+	_ = c.Error(err).SetMeta(message)
+	c.Abort()
+}
+
+// respondValidationError aborts the request with an error returned while binding the request data
+func respondValidationError(c *gin.Context, err error, message string) {
+	_ = c.Error(err).SetType(gin.ErrorTypeBind).SetMeta(message)
+	c.Abort()
+}
+
+// RouteNotFound responds to requests that match no route
+func RouteNotFound(c *gin.Context) {
+	respondError(c, errRouteNotFound, "")
+}
+
+// ErrorMiddleware renders the last error recorded by a handler. Responses are RFC 7807 problem details when the client accepts them, and the standard error response otherwise
+func ErrorMiddleware() gin.HandlerFunc {
+	// report validation errors with the names clients send the fields with
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+	}
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		last := c.Errors.Last()
+		if last == nil || c.Writer.Written() {
+			return
+		}
+
+		status, res := resolveError(c, last)
+		if strings.Contains(c.GetHeader("Accept"), model.ProblemMediaType) {
+			c.Header("Content-Type", model.ProblemMediaType)
+			c.JSON(status, model.ProblemDetails{
+				Type:     "urn:chow:error:" + string(res.Code),
+				Title:    http.StatusText(status),
+				Status:   status,
+				Detail:   res.Message,
+				Instance: c.Request.URL.Path,
+				Code:     res.Code,
+				Fields:   res.Fields,
+			})
+			return
+		}
+		c.JSON(status, res)
+	}
+}
+
+// resolveError maps a handler error to its status and response
+func resolveError(c *gin.Context, ginErr *gin.Error) (int, model.ErrorResponse) {
+	err := ginErr.Err
+	message, _ := ginErr.Meta.(string)
+	locale := requestLocale(c)
+
+	if ginErr.IsType(gin.ErrorTypeBind) {
+		res := model.ErrorResponse{Code: model.ValidationFailedCode, Message: i18n.Translate(locale, message)}
+		if fields := validationFields(locale, err); len(fields) > 0 {
+			res.Fields = fields
+		} else {
+			res.Detail = err.Error()
+		}
+		return http.StatusUnprocessableEntity, res
+	}
+
+	var handlerErr *apiError
+	if errors.As(err, &handlerErr) {
+		return handlerErr.status, model.ErrorResponse{Code: handlerErr.code, Message: i18n.Translate(locale, handlerErr.message)}
+	}
+
+	// tell clients when a rate limited action can be retried
+	var cooldownErr *service.CheckinCooldownError
+	if errors.As(err, &cooldownErr) {
+		c.Header("Retry-After", headerSeconds(cooldownErr.RetryAfter))
+	}
+	var rateLimitErr *service.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		c.Header("Retry-After", headerSeconds(rateLimitErr.RetryAfter))
+	}
+	var loginErr *service.LoginThrottledError
+	if errors.As(err, &loginErr) {
+		c.Header("Retry-After", headerSeconds(loginErr.RetryAfter))
+	}
+
+	// catalogued errors are described by their code so details added to wrapped errors are left out
+	for _, entry := range errorCatalogue {
+		if errors.Is(err, entry.err) {
+			return entry.status, model.ErrorResponse{Code: entry.code, Message: i18n.Translate(locale, entry.err.Error())}
+		}
+	}
+
+	log.Println(err)
+	if message == "" {
+		message = "Internal server error"
+	}
+	return http.StatusInternalServerError, model.ErrorResponse{Code: model.InternalErrorCode, Message: i18n.Translate(locale, message)}
+}
+
+// headerSeconds formats a duration as the whole number of seconds used by headers, rounded up so that clients do not retry too early
+func headerSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// validationFields translates binding errors into the rules broken by each field
+func validationFields(locale string, err error) map[string][]model.FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make(map[string][]model.FieldError, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			name := fieldPath(fieldErr)
+			fields[name] = append(fields[name], model.FieldError{Rule: fieldErr.Tag(), Message: ruleMessage(locale, fieldErr)})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return map[string][]model.FieldError{
+			typeErr.Field: {{Rule: "type", Message: i18n.Sprintf(locale, "must be of type %s", typeErr.Type)}},
+		}
+	}
+	return nil
+}
+
+// fieldPath returns the path of the field in the request without the name of the request struct
+func fieldPath(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return fieldErr.Field()
+}
+
+// fieldName reports struct fields by their json, form or uri names
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// ruleMessage describes a broken validation rule in the locale
+func ruleMessage(locale string, fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+
+	// lengths are compared for strings and lists, values otherwise
+	unit := ""
+	switch fieldErr.Kind() {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fieldErr.Tag() {
+	case "required":
+		return i18n.Translate(locale, "is required")
+	case "required_with":
+		return i18n.Sprintf(locale, "is required with %s", param)
+	case "required_without":
+		return i18n.Sprintf(locale, "is required without %s", param)
+	case "excluded_with":
+		return i18n.Sprintf(locale, "must not be set with %s", param)
+	case "uuid":
+		return i18n.Translate(locale, "must be a valid UUID")
+	case "email":
+		return i18n.Translate(locale, "must be a valid email address")
+	case "url":
+		return i18n.Translate(locale, "must be a valid URL")
+	case "latitude":
+		return i18n.Translate(locale, "must be a latitude between -90 and 90")
+	case "longitude":
+		return i18n.Translate(locale, "must be a longitude between -180 and 180")
+	case "oneof":
+		return i18n.Sprintf(locale, "must be one of: %s", strings.Join(strings.Fields(param), ", "))
+	case "numeric":
+		return i18n.Translate(locale, "must be a number")
+	case "len":
+		return i18n.Sprintf(locale, "must be exactly %s"+unit, param)
+	case "min", "gte":
+		return i18n.Sprintf(locale, "must be at least %s"+unit, param)
+	case "max", "lte":
+		return i18n.Sprintf(locale, "must be at most %s"+unit, param)
+	case "gt":
+		return i18n.Sprintf(locale, "must be more than %s"+unit, param)
+	case "lt":
+		return i18n.Sprintf(locale, "must be less than %s"+unit, param)
+	}
+	return i18n.Sprintf(locale, "failed the %s rule", fieldErr.Tag())
+}
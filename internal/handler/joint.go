@@ -3,7 +3,6 @@ package handler
 import (
 	"chow/internal/model"
 	"chow/internal/service"
-	"errors"
 	"log"
 	"net/http"
 
@@ -11,14 +10,20 @@ import (
 )
 
 type JointHandler struct {
-	jointService     *service.JointService
-	complaintService *service.ComplaintService
+	jointService       *service.JointService
+	complaintService   *service.ComplaintService
+	reputationService  *service.ReputationService
+	trendingService    *service.TrendingService
+	translationService *service.TranslationService
 }
 
-func NewJointHandler(jointService *service.JointService, complaintService *service.ComplaintService) *JointHandler {
+func NewJointHandler(jointService *service.JointService, complaintService *service.ComplaintService, reputationService *service.ReputationService, trendingService *service.TrendingService, translationService *service.TranslationService) *JointHandler {
 	return &JointHandler{
-		jointService:     jointService,
-		complaintService: complaintService,
+		jointService:       jointService,
+		complaintService:   complaintService,
+		reputationService:  reputationService,
+		trendingService:    trendingService,
+		translationService: translationService,
 	}
 }
 
@@ -34,36 +39,32 @@ func NewJointHandler(jointService *service.JointService, complaintService *servi
 // @Failure 401 {object} model.ErrorResponse "User not authenticated"
 // @Failure 409 {object} model.ErrorResponse "Joint already exists"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints [post]
 func (h *JointHandler) CreateJoint(c *gin.Context) {
 	// validate data
 	var req model.CreateJointReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate data", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate data")
 		return
 	}
 	// get user info from auth context
 	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "User not authenticated"})
+		respondError(c, errUnauthenticated, "")
 		return
 	}
 
 	// save file if uploaded
 
-	joint, err := h.jointService.CreateJoint(c.Request.Context(), &model.Joint{Name: req.Name, Latitude: req.Latitude, Longitude: req.Longitude, Description: req.Description, IsApproved: false, CreatorID: user.ID, PhotoURL: nil})
+	joint, err := h.jointService.CreateJoint(c.Request.Context(), &model.Joint{Name: req.Name, Latitude: req.Latitude, Longitude: req.Longitude, Description: req.Description, Category: model.JointCategory(req.Category), IsApproved: false, CreatorID: user.ID, PhotoURL: nil, DefaultLocale: req.Locale})
 	if err != nil {
-		if errors.Is(err, service.ErrAlreadyExist) {
-			c.JSON(http.StatusConflict, model.ErrorResponse{Message: err.Error()})
-			return
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to add new joint"})
+		respondError(c, err, "Failed to add new joint")
 		return
 	}
 
-	c.JSON(http.StatusCreated, model.SuccessResponse{Message: "Joint added successfully", Data: joint})
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Joint added successfully"), Data: joint})
 }
 
 // SearchJoints godoc
@@ -75,27 +76,30 @@ func (h *JointHandler) CreateJoint(c *gin.Context) {
 // @Param q query string true "Search query"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
 // @Success 200 {object} model.SuccessResponse{data=[]model.Joint} "Joints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints/search [get]
 func (h *JointHandler) SearchJoints(c *gin.Context) {
 	var pagination model.PaginationQuery
 	if err := c.ShouldBind(&pagination); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate pagination params", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate pagination params")
 		return
 	}
 	q := c.Query("q")
-	offset, limit := pagination.GetOffsetAndLimit()
 
-	joints, err := h.jointService.SearchForJointByNameOrDescription(c.Request.Context(), q, offset, limit)
+	joints, page, err := h.jointService.SearchForJointByNameOrDescription(c.Request.Context(), q, pagination)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to search joints"})
+		respondError(c, err, "Failed to search joints")
 		return
 	}
+	h.personalizeJoints(c, joints...)
+	localizeJoints(c, h.translationService, joints...)
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Joints retrieved successfully", Data: joints})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joints retrieved successfully"), Data: joints, Pagination: page})
 }
 
 // GetNearByJoints godoc
@@ -107,7 +111,10 @@ func (h *JointHandler) SearchJoints(c *gin.Context) {
 // @Param request query model.NearbyJointsQuery true "Search parameters"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
 // @Success 200 {object} model.SuccessResponse{data=[]model.Joint} "Nearby Joints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints/nearby [get]
@@ -117,22 +124,19 @@ func (h *JointHandler) GetNearByJoints(c *gin.Context) {
 		model.NearbyJointsQuery
 	}
 	if err := c.ShouldBind(&query); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate pagination params", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate pagination params")
 		return
 	}
-	offset, limit := query.GetOffsetAndLimit()
 
-	joints, err := h.jointService.GetNearbyJoints(c.Request.Context(), model.Coordinate{Latitude: query.Latitude, Longitude: query.Longitude}, query.Radius, offset, limit)
+	joints, page, err := h.jointService.GetNearbyJoints(c.Request.Context(), model.Coordinate{Latitude: query.Latitude, Longitude: query.Longitude}, query.Radius, query.PaginationQuery)
 	if err != nil {
-		if errors.Is(err, service.ErrMaxSearchRadiusExceeded) {
-			c.JSON(http.StatusBadRequest, model.ErrorResponse{Message: err.Error()})
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to retrieve nearby joints"})
+		respondError(c, err, "Failed to retrieve nearby joints")
 		return
 	}
+	h.personalizeJoints(c, joints...)
+	localizeJoints(c, h.translationService, joints...)
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Nearby Joints retrieved successfully", Data: joints})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Nearby Joints retrieved successfully"), Data: joints, Pagination: page})
 }
 
 // VoteJoint godoc
@@ -142,33 +146,37 @@ func (h *JointHandler) GetNearByJoints(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param id path string true "Joint ID"
 // @Param request body model.VoteJointReq true "Vote direction"
 // @Success 200 {object} model.SuccessResponse{data=model.Joint} "Vote recorded successfully"
 // @Success 200 {object} model.SuccessResponse "Vote already recorded"
 // @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "API key scope insufficient"
 // @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 409 {object} model.ErrorResponse "Vote was voided by moderators"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints/{id}/vote [post]
 func (h *JointHandler) VoteJoint(c *gin.Context) {
 	// validate params and data
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate joint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate joint ID")
 		return
 	}
 
 	var vote model.VoteJointReq
 	if err := c.ShouldBindJSON(&vote); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Invalid vote direction", Detail: err.Error()})
+		respondValidationError(c, err, "Invalid vote direction")
 		return
 	}
 
 	// get current user details
 	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: "User not authenticated"})
+		respondError(c, errUnauthenticated, "")
 		return
 	}
 
@@ -179,22 +187,145 @@ func (h *JointHandler) VoteJoint(c *gin.Context) {
 	})
 
 	if err != nil {
-		if errors.Is(err, service.ErrJointNotFound) {
-			c.JSON(http.StatusNotFound, model.ErrorResponse{Message: "Joint not found"})
-			return
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to process vote"})
+		respondError(c, err, "Failed to process vote")
 		return
 	}
 
 	// if joint is null at this point, the user tried voting in the same direction again so a success response is simply returned
 	if joint == nil {
-		c.JSON(http.StatusOK, model.SuccessResponse{Message: "Vote already recorded"})
+		c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Vote already recorded")})
+		return
+	}
+
+	h.personalizeJoints(c, joint)
+	localizeJoints(c, h.translationService, joint)
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Vote recorded successfully"), Data: joint})
+}
+
+// RetractVote godoc
+// @Summary Retract vote on a joint
+// @Description Remove the authenticated user's vote on a food joint
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Joint ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Joint} "Vote retracted successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "API key scope insufficient"
+// @Failure 404 {object} model.ErrorResponse "Vote not found"
+// @Failure 409 {object} model.ErrorResponse "Vote was voided by moderators"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/vote [delete]
+func (h *JointHandler) RetractVote(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate joint ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	joint, err := h.jointService.RetractVote(c.Request.Context(), user.ID, param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retract vote")
+		return
+	}
+	h.personalizeJoints(c, joint)
+	localizeJoints(c, h.translationService, joint)
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Vote retracted successfully"), Data: joint})
+}
+
+// GetJointVoters godoc
+// @Summary Get joint voters
+// @Description Get the users who have voted on a joint, most recent first. Only available to admins and moderators
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Joint ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.JointVoter} "Joint voters retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/voters [get]
+func (h *JointHandler) GetJointVoters(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate joint ID")
+		return
+	}
+
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Vote recorded successfully", Data: joint})
+	voters, page, err := h.jointService.GetJointVoters(c.Request.Context(), param.GetID(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve joint voters")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint voters retrieved successfully"), Data: voters, Pagination: page})
+}
+
+// GetUserVotes godoc
+// @Summary Get user votes
+// @Description Get the votes cast by the authenticated user, most recently updated first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.UserVote} "Votes retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/votes [get]
+func (h *JointHandler) GetUserVotes(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	votes, page, err := h.jointService.GetUserVotes(c.Request.Context(), user.ID, pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve votes")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Votes retrieved successfully"), Data: votes, Pagination: page})
 }
 
 // GetAllJoints godoc
@@ -205,26 +336,29 @@ func (h *JointHandler) VoteJoint(c *gin.Context) {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
 // @Success 200 {object} model.SuccessResponse{data=[]model.Joint} "Joints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints [get]
 func (h *JointHandler) GetAllJoints(c *gin.Context) {
 	var pagination model.PaginationQuery
 	if err := c.ShouldBind(&pagination); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate pagination params", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate pagination params")
 		return
 	}
-	offset, limit := pagination.GetOffsetAndLimit()
 
-	joints, err := h.jointService.GetAllJoints(c.Request.Context(), offset, limit)
+	joints, page, err := h.jointService.GetAllJoints(c.Request.Context(), pagination)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to retrieve joints"})
+		respondError(c, err, "Failed to retrieve joints")
 		return
 	}
+	h.personalizeJoints(c, joints...)
+	localizeJoints(c, h.translationService, joints...)
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Joints retrieved successfully", Data: joints})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joints retrieved successfully"), Data: joints, Pagination: page})
 }
 
 // GetJoint godoc
@@ -244,23 +378,31 @@ func (h *JointHandler) GetAllJoints(c *gin.Context) {
 func (h *JointHandler) GetJoint(c *gin.Context) {
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate joint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate joint ID")
 		return
 	}
 
 	joint, err := h.jointService.GetJointByID(c.Request.Context(), param.GetID())
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to retrieve joint"})
+		respondError(c, err, "Failed to retrieve joint")
 		return
 	}
+	h.personalizeJoints(c, joint)
+	localizeJoints(c, h.translationService, joint)
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Joint retrieved successfully", Data: joint})
+	// views of listed joints count towards trending
+	if joint.IsApproved && joint.Visibility == model.VisibleJoint {
+		if err := h.trendingService.RecordView(c.Request.Context(), joint.ID); err != nil {
+			log.Println(err)
+		}
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint retrieved successfully"), Data: joint})
 }
 
 // UpdateJoint godoc
 // @Summary Update joint
-// @Description Update an existing joint's details
+// @Description Update an existing joint's details. Edits of approved joints by creators whose trust level does not unlock auto approval send the joint back to the approval queue
 // @Tags joints
 // @Accept json
 // @Produce json
@@ -277,96 +419,313 @@ func (h *JointHandler) GetJoint(c *gin.Context) {
 func (h *JointHandler) UpdateJoint(c *gin.Context) {
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate joint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate joint ID")
 		return
 	}
 
 	var req model.CreateJointReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate data", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate data")
 		return
 	}
 
 	// get current user. updates are restricted to only creator of joint and admin
 	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: "User not authenticated"})
+		respondError(c, errUnauthenticated, "")
 		return
 	}
 
 	// get existing joint to verify ownership
 	existingJoint, err := h.jointService.GetJointByID(c.Request.Context(), param.GetID())
 	if err != nil {
-		if errors.Is(err, service.ErrJointNotFound) {
-			c.JSON(http.StatusNotFound, model.ErrorResponse{Message: "Joint not found"})
-			return
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to update joint"})
+		respondError(c, err, "Failed to update joint")
 		return
 	}
 
 	// verify owner details
 	if existingJoint.CreatorID != user.ID && user.Role != model.Admin {
-		c.JSON(http.StatusForbidden, model.ErrorResponse{Message: "User not authorized to update this joint"})
+		respondError(c, forbidden("User not authorized to update this joint"), "")
 		return
 	}
 
-	// update only name, location details and description
+	// the category is kept when not provided
+	category := existingJoint.Category
+	if req.Category != "" {
+		category = model.JointCategory(req.Category)
+	}
+
+	// edits of approved joints go back to the approval queue unless the editor is trusted enough to have them applied directly
+	isApproved := existingJoint.IsApproved
+	if isApproved && user.Role == model.AppUser {
+		level, err := h.reputationService.GetTrustLevel(c.Request.Context(), user.ID)
+		if err != nil {
+			respondError(c, err, "Failed to update joint")
+			return
+		}
+		isApproved = level.Can(model.AutoApproveEditsAbility)
+	}
+
+	// update only name, location details, description and category
 	joint, err := h.jointService.UpdateJointByID(c.Request.Context(), param.GetID(), &model.Joint{
 		Name:        req.Name,
 		Latitude:    req.Latitude,
 		Longitude:   req.Longitude,
 		Description: req.Description,
-		IsApproved:  existingJoint.IsApproved,
+		Category:    category,
+		IsApproved:  isApproved,
 		CreatorID:   existingJoint.CreatorID,
 		PhotoURL:    existingJoint.PhotoURL,
 	})
 
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to update joint"})
+		respondError(c, err, "Failed to update joint")
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Joint updated successfully", Data: joint})
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint updated successfully"), Data: joint})
 }
 
 // DeleteJoint godoc
 // @Summary Delete joint
-// @Description Delete an existing joint (admin only)
+// @Description Move an existing joint to the trash (admin only). Deleted joints can be restored until they are purged
 // @Tags joints
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Joint ID"
+// @Param request body model.DeleteJointReq false "Deletion reason"
 // @Success 200 {object} model.SuccessResponse "Joint deleted successfully"
 // @Failure 401 {object} model.ErrorResponse "User not authenticated"
 // @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints/{id} [delete]
 func (h *JointHandler) DeleteJoint(c *gin.Context) {
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Invalid joint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Invalid joint ID")
 		return
 	}
 
+	// the deletion reason is optional so an empty body is allowed
+	var req model.DeleteJointReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, err, "Failed to validate data")
+			return
+		}
+	}
+
 	// get admin info
-	_, ok := GetCurrentAdmin(c)
+	admin, ok := GetCurrentAdmin(c)
 	if !ok {
-		c.JSON(http.StatusForbidden, model.ErrorResponse{Message: "User not authorized to perform this action"})
+		respondError(c, forbidden("User not authorized to perform this action"), "")
 		return
 	}
 
-	if err := h.jointService.DeleteJointByID(c.Request.Context(), param.GetID()); err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to delete joint"})
+	if err := h.jointService.DeleteJointByID(c.Request.Context(), param.GetID(), admin.ID, req.Reason); err != nil {
+		respondError(c, err, "Failed to delete joint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint deleted successfully")})
+}
+
+// GetDeletedJoints godoc
+// @Summary Get deleted joints
+// @Description Get the joints in the trash, most recently deleted first (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.Joint} "Deleted joints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/joints/trash [get]
+func (h *JointHandler) GetDeletedJoints(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	if _, ok := GetCurrentAdmin(c); !ok {
+		respondError(c, forbidden("User not authorized to perform this action"), "")
+		return
+	}
+
+	joints, page, err := h.jointService.GetDeletedJoints(c.Request.Context(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve deleted joints")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Deleted joints retrieved successfully"), Data: joints, Pagination: page})
+}
+
+// RestoreJoint godoc
+// @Summary Restore joint
+// @Description Restore a joint from the trash (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Joint ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Joint} "Joint restored successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/joints/trash/{id}/restore [patch]
+func (h *JointHandler) RestoreJoint(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Invalid joint ID")
+		return
+	}
+
+	if _, ok := GetCurrentAdmin(c); !ok {
+		respondError(c, forbidden("User not authorized to perform this action"), "")
+		return
+	}
+
+	joint, err := h.jointService.RestoreJoint(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to restore joint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint restored successfully"), Data: joint})
+}
+
+// PurgeJoint godoc
+// @Summary Purge joint
+// @Description Permanently remove a joint in the trash along with its votes and complaints (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Joint ID"
+// @Success 200 {object} model.SuccessResponse "Joint purged successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 409 {object} model.ErrorResponse "Joint cannot be deleted"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/joints/trash/{id} [delete]
+func (h *JointHandler) PurgeJoint(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Invalid joint ID")
+		return
+	}
+
+	if _, ok := GetCurrentAdmin(c); !ok {
+		respondError(c, forbidden("User not authorized to perform this action"), "")
+		return
+	}
+
+	if err := h.jointService.PurgeJoint(c.Request.Context(), param.GetID()); err != nil {
+		respondError(c, err, "Failed to purge joint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint purged successfully")})
+}
+
+// ApproveJoint godoc
+// @Summary Approve joint
+// @Description Approve a submitted joint so that it is publicly listed (admin only)
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Joint ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Joint} "Joint approved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/approve [patch]
+func (h *JointHandler) ApproveJoint(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Invalid joint ID")
 		return
 	}
 
-	c.JSON(http.StatusOK, model.SuccessResponse{Message: "Joint deleted successfully"})
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
+		return
+	}
+
+	joint, err := h.jointService.ApproveJoint(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to approve joint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint approved successfully"), Data: joint})
+}
+
+// RejectJoint godoc
+// @Summary Reject joint
+// @Description Decline a submitted joint and move it to the trash (admin only)
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Joint ID"
+// @Param request body model.RejectJointReq false "Rejection reason"
+// @Success 200 {object} model.SuccessResponse "Joint rejected successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 409 {object} model.ErrorResponse "Joint already approved"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/reject [patch]
+func (h *JointHandler) RejectJoint(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Invalid joint ID")
+		return
+	}
+
+	// the rejection reason is optional so an empty body is allowed
+	var req model.RejectJointReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, err, "Failed to validate data")
+			return
+		}
+	}
+
+	user, ok := h.getAuthAdminOrModerator(c)
+	if !ok {
+		return
+	}
+
+	if err := h.jointService.RejectJoint(c.Request.Context(), param.GetID(), user.ID, req.Reason); err != nil {
+		respondError(c, err, "Failed to reject joint")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Joint rejected successfully")})
 }
 
 // CreateJointComplaint godoc
@@ -380,19 +739,22 @@ func (h *JointHandler) DeleteJoint(c *gin.Context) {
 // @Param request body model.CreateComplaintReq true "Complaint details"
 // @Success 201 {object} model.SuccessResponse{data=model.Complaint} "Complaint added successfully"
 // @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 409 {object} model.ErrorResponse "Complaint already exists"
 // @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
 // @Failure 500 {object} model.ErrorResponse "Server error"
 // @Router /joints/{id}/complaints [post]
 func (h *JointHandler) CreateJointComplaint(c *gin.Context) {
 	// validate data
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate joint ID", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate joint ID")
 		return
 	}
 	var req model.CreateComplaintReq
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{Message: "Failed to validate data", Detail: err.Error()})
+		respondValidationError(c, err, "Failed to validate data")
 		return
 	}
 	// get user info from auth context
@@ -401,18 +763,13 @@ func (h *JointHandler) CreateJointComplaint(c *gin.Context) {
 		return
 	}
 
-	complaint, err := h.complaintService.CreateComplaint(c.Request.Context(), &model.Complaint{Reason: req.Reason, Status: model.OpenComplaint, UserID: user.ID, JointID: param.GetID()})
+	complaint, err := h.complaintService.CreateComplaint(c.Request.Context(), &model.Complaint{Reason: req.Reason, Category: req.Category, Status: model.OpenComplaint, UserID: user.ID, JointID: param.GetID()})
 	if err != nil {
-		if errors.Is(err, service.ErrAlreadyExist) {
-			c.JSON(http.StatusConflict, model.ErrorResponse{Message: err.Error()})
-			return
-		}
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Message: "Failed to add new complaint"})
+		respondError(c, err, "Failed to add new complaint")
 		return
 	}
 
-	c.JSON(http.StatusCreated, model.SuccessResponse{Message: "Complaint added successfully", Data: complaint})
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Complaint added successfully"), Data: complaint})
 }
 
 // GetJointComplaints godoc
@@ -425,7 +782,10 @@ func (h *JointHandler) CreateJointComplaint(c *gin.Context) {
 // @Param id path string true "Joint ID"
 // @Param page query int false "Page number" default(1)
 // @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
 // @Success 200 {object} model.SuccessResponse{data=[]model.Complaint} "Joint complaints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
 // @Failure 401 {object} model.ErrorResponse "User not authenticated"
 // @Failure 403 {object} model.ErrorResponse "User not authorized"
 // @Failure 404 {object} model.ErrorResponse "Joint not found"
@@ -435,19 +795,13 @@ func (h *JointHandler) CreateJointComplaint(c *gin.Context) {
 func (h *JointHandler) GetJointComplaints(c *gin.Context) {
 	var param model.IDParam
 	if err := c.ShouldBindUri(&param); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{
-			Message: "Failed to validate joint ID",
-			Detail:  err.Error(),
-		})
+		respondValidationError(c, err, "Failed to validate joint ID")
 		return
 	}
 
 	var pagination model.PaginationQuery
 	if err := c.ShouldBind(&pagination); err != nil {
-		c.JSON(http.StatusUnprocessableEntity, model.ErrorResponse{
-			Message: "Failed to validate pagination params",
-			Detail:  err.Error(),
-		})
+		respondValidationError(c, err, "Failed to validate pagination params")
 		return
 	}
 
@@ -455,19 +809,16 @@ func (h *JointHandler) GetJointComplaints(c *gin.Context) {
 		return
 	}
 
-	offset, limit := pagination.GetOffsetAndLimit()
-	complaints, err := h.complaintService.GetJointComplaints(c.Request.Context(), param.GetID(), offset, limit)
+	complaints, page, err := h.complaintService.GetJointComplaints(c.Request.Context(), param.GetID(), pagination)
 	if err != nil {
-		log.Println(err)
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{
-			Message: "Failed to retrieve joint complaints",
-		})
+		respondError(c, err, "Failed to retrieve joint complaints")
 		return
 	}
 
 	c.JSON(http.StatusOK, model.SuccessResponse{
-		Message: "Joint complaints retrieved successfully",
-		Data:    complaints,
+		Message:    translate(c, "Joint complaints retrieved successfully"),
+		Data:       complaints,
+		Pagination: page,
 	})
 }
 
@@ -476,7 +827,7 @@ func (h *JointHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bo
 	// get user info from auth context
 	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: "User not authenticated"})
+		respondError(c, errUnauthenticated, "")
 		return nil, false
 	}
 	return &user, true
@@ -485,14 +836,25 @@ func (h *JointHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bo
 // getAuthAdmin retrieves the authenticated user or return an unauthorized error if user is not present
 func (h *JointHandler) getAuthAdminOrModerator(c *gin.Context) (*model.AuthenticatedUser, bool) {
 	// get admin info from auth context
-	user, ok := GetCurrentAdmin(c)
+	user, ok := GetCurrentUser(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Message: "User is not authorized"})
+		respondError(c, unauthenticated("User is not authorized"), "")
 		return nil, false
 	}
 	if user.Role != model.Moderator && user.Role != model.Admin {
-		c.JSON(http.StatusForbidden, model.ErrorResponse{Message: "User is not an admin or moderator"})
+		respondError(c, forbidden("User is not an admin or moderator"), "")
 		return nil, false
 	}
 	return &user, true
 }
+
+// personalizeJoints adds the details specific to the authenticated user, if any, to the joints. The joints are still returned when this fails
+func (h *JointHandler) personalizeJoints(c *gin.Context, joints ...*model.Joint) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		return
+	}
+	if err := h.jointService.PersonalizeJoints(c.Request.Context(), user.ID, joints...); err != nil {
+		log.Println(err)
+	}
+}
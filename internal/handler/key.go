@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type KeyHandler struct {
+	signingKeyService *service.SigningKeyService
+}
+
+func NewKeyHandler(signingKeyService *service.SigningKeyService) *KeyHandler {
+	return &KeyHandler{
+		signingKeyService: signingKeyService,
+	}
+}
+
+// GetJWKS serves the public keys that verify access tokens as a JSON Web Key Set. It is served at /.well-known/jwks.json outside the api so it is not in the swagger docs and
+// the set is not wrapped in the api response. Verifiers that see an unknown kid should fetch it again since keys are rotated
+func (h *KeyHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.signingKeyService.JWKS())
+}
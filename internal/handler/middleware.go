@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"chow/internal/i18n"
 	"chow/internal/model"
 	"chow/internal/service"
-	"net/http"
+	"fmt"
+	"log"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -11,11 +14,12 @@ import (
 )
 
 type Middleware struct {
-	AuthService *service.AuthService
+	AuthService      *service.AuthService
+	RateLimitService *service.RateLimitService
 }
 
-func NewMiddleware(authService *service.AuthService) *Middleware {
-	return &Middleware{AuthService: authService}
+func NewMiddleware(authService *service.AuthService, rateLimitService *service.RateLimitService) *Middleware {
+	return &Middleware{AuthService: authService, RateLimitService: rateLimitService}
 }
 
 // Key type for context values
@@ -26,64 +30,202 @@ const (
 	UserKey contextKey = "user"
 )
 
-// AuthMiddleware checks JWT tokens and adds user info to the request context
-func (m *Middleware) AuthMiddleware() gin.HandlerFunc {
+// RequestIDHeader carries the request ID used to correlate logs and audit events
+const RequestIDHeader = "X-Request-ID"
+
+// DeviceIDHeader carries an identifier of the client device used to detect votes from the same device
+const DeviceIDHeader = "X-Device-ID"
+
+// RequestInfoMiddleware assigns a request ID and attaches the request origin and accepted locales to the request context
+func (m *Middleware) RequestInfoMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// reuse the request ID from upstream proxies when present
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 100 {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		info := model.RequestInfo{
+			IPAddress: c.ClientIP(),
+			RequestID: requestID,
+			UserAgent: c.Request.UserAgent(),
+			DeviceID:  c.GetHeader(DeviceIDHeader),
+			Locales:   i18n.Negotiate(c.GetHeader("Accept-Language")),
+		}
+		c.Header("Content-Language", i18n.Preferred(info.Locales))
+		c.Header("Vary", "Accept-Language")
+		ctx := service.WithRequestInfo(c.Request.Context(), info)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// AuthMiddleware checks JWT tokens or personal API keys and adds user info to the request context. API keys are only accepted on routes given the scopes that cover them and must have one of the scopes
+func (m *Middleware) AuthMiddleware(scopes ...model.APIKeyScope) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// extract token from Authorization header
 		authHeader := c.Request.Header.Get("Authorization")
 		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: "Authorization header required"})
+			respondError(c, unauthenticated("Authorization header required"), "")
 			return
 		}
 
-		// check Bearer token format
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: "Invalid authorization format"})
+		if !m.authenticate(c, authHeader, scopes) {
 			return
 		}
 
-		tokenString := parts[1]
+		// process next handler
+		c.Next()
+	}
+}
 
-		// validate the token
-		claims, err := m.AuthService.ValidateToken(tokenString)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: err.Error()})
+// OptionalAuthMiddleware adds user info to the request context when a JWT token or API key is provided so that public routes can personalise their responses. Invalid credentials are still rejected
+func (m *Middleware) OptionalAuthMiddleware(scopes ...model.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.Request.Header.Get("Authorization")
+		if authHeader != "" && !m.authenticate(c, authHeader, scopes) {
 			return
 		}
 
-		// Extract user ID, role, and username from claims
-		userIDStr, ok := claims["sub"].(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: "Invalid user ID in token"})
-			return
+		c.Next()
+	}
+}
+
+// authenticate validates the Authorization header and adds the user info to the request context. The request is aborted when the header is invalid
+func (m *Middleware) authenticate(c *gin.Context, authHeader string, scopes []model.APIKeyScope) bool {
+	// check Bearer token or ApiKey format
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || (parts[0] != "Bearer" && parts[0] != "ApiKey") {
+		respondError(c, unauthenticated("Invalid authorization format"), "")
+		return false
+	}
+
+	if parts[0] == "ApiKey" {
+		return m.authenticateAPIKey(c, parts[1], scopes)
+	}
+
+	tokenString := parts[1]
+
+	// validate the token
+	claims, err := m.AuthService.ValidateToken(c.Request.Context(), tokenString)
+	if err != nil {
+		respondError(c, err, "Failed to validate token")
+		return false
+	}
+
+	// Extract user ID, role, and username from claims
+	userIDStr, ok := claims["sub"].(string)
+	if !ok {
+		respondError(c, unauthenticated("Invalid user ID in token"), "")
+		return false
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		respondError(c, service.ErrInvalidToken, "")
+		return false
+	}
+	role, ok := claims["role"].(string)
+	if !ok {
+		respondError(c, unauthenticated("Invalid role in token"), "")
+		return false
+	}
+	username, ok := claims["username"].(string)
+	if !ok {
+		respondError(c, unauthenticated("Invalid username in token"), "")
+		return false
+	}
+
+	twoFactor, _ := claims["mfa"].(bool)
+
+	// privileged roles that require two-factor authentication act as regular users until the session completes it
+	userRole := model.UserRole(role)
+	if !twoFactor && m.AuthService.RequiresTwoFactor(userRole) {
+		userRole = model.AppUser
+	}
+
+	setCurrentUser(c, model.AuthenticatedUser{ID: userID, Username: username, Role: userRole, TwoFactor: twoFactor})
+	return true
+}
+
+// authenticateAPIKey validates a personal API key against the scopes of the route and adds its owner to the request context. Routes without scopes, such as managing the keys themselves, need a session
+func (m *Middleware) authenticateAPIKey(c *gin.Context, key string, scopes []model.APIKeyScope) bool {
+	user, err := m.AuthService.AuthenticateAPIKey(c.Request.Context(), key)
+	if err != nil {
+		respondError(c, err, "Failed to validate API key")
+		return false
+	}
+
+	allowed := false
+	for _, scope := range scopes {
+		if user.APIKey.HasScope(scope) {
+			allowed = true
+			break
 		}
-		userID, err := uuid.Parse(userIDStr)
+	}
+	if !allowed {
+		respondError(c, service.ErrInsufficientScope, "")
+		return false
+	}
+
+	setCurrentUser(c, *user)
+	return true
+}
+
+// setCurrentUser adds the user info to the request context and records the user as the actor of any change made by the request
+func setCurrentUser(c *gin.Context, user model.AuthenticatedUser) {
+	c.Set(string(UserKey), user)
+
+	info := service.RequestInfoFromContext(c.Request.Context())
+	info.ActorID = &user.ID
+	c.Request = c.Request.WithContext(service.WithRequestInfo(c.Request.Context(), info))
+}
+
+// RateLimitMiddleware limits the requests each client makes under the policy. Authenticated users are limited by user ID and other clients by IP address, so it runs after the auth middleware on protected routes
+func (m *Middleware) RateLimitMiddleware(policy model.RateLimitPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client := "ip:" + c.ClientIP()
+		if user, ok := GetCurrentUser(c); ok {
+			client = "user:" + user.ID.String()
+		}
+
+		result, err := m.RateLimitService.Take(c.Request.Context(), policy, client)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: service.ErrInvalidToken.Error()})
+			// serve the request rather than fail every request while the limits cannot be tracked
+			log.Printf("failed to apply %s rate limit: %v", policy, err)
+			c.Next()
 			return
 		}
-		role, ok := claims["role"].(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: "Invalid role in token"})
+		if result == nil {
+			c.Next()
 			return
 		}
-		username, ok := claims["username"].(string)
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{Message: "Invalid username in token"})
+
+		// quota of the policy as described by the IETF RateLimit header fields
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", headerSeconds(result.Reset))
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, int(result.Period.Seconds())))
+		if !result.Allowed {
+			respondError(c, &service.RateLimitError{RetryAfter: result.RetryAfter}, "")
 			return
 		}
 
-		user := model.AuthenticatedUser{ID: userID, Username: username, Role: model.UserRole(role)}
-
-		// Add user info to request context
-		c.Set(string(UserKey), user)
-
-		// process next handler
 		c.Next()
 	}
 }
 
+// requestLocale returns the locale responses to the request are translated to
+func requestLocale(c *gin.Context) string {
+	return i18n.Preferred(service.RequestInfoFromContext(c.Request.Context()).Locales)
+}
+
+// translate returns the message in the locale of the request
+func translate(c *gin.Context, message string) string {
+	return i18n.Translate(requestLocale(c), message)
+}
+
 // GetCurrentUser retrieves the current user info from the request context
 func GetCurrentUser(c *gin.Context) (model.AuthenticatedUser, bool) {
 	user, ok := c.Get(string(UserKey))
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the notifications of the authenticated user, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request query model.NotificationQuery false "Cursor and filters"
+// @Success 200 {object} model.SuccessResponse{data=model.NotificationPage} "Notifications retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	var query model.NotificationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	page, pagination, err := h.notificationService.GetNotifications(c.Request.Context(), user.ID, query.UnreadOnly, query.PaginationQuery)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve notifications")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Notifications retrieved successfully"), Data: page, Pagination: pagination})
+}
+
+// GetUnreadCount godoc
+// @Summary Get unread notification count
+// @Description Get the number of unread notifications of the authenticated user
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=model.UnreadNotificationCount} "Unread count retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.notificationService.GetUnreadCount(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve unread count")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Unread count retrieved successfully"), Data: model.UnreadNotificationCount{Unread: count}})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Description Mark a notification of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Notification} "Notification marked as read"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Notification not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /notifications/{id}/read [patch]
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate notification ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	notification, err := h.notificationService.MarkNotificationRead(c.Request.Context(), user.ID, param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to mark notification as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Notification marked as read"), Data: notification})
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the authenticated user as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse "Notifications marked as read"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.notificationService.MarkAllNotificationsRead(c.Request.Context(), user.ID); err != nil {
+		respondError(c, err, "Failed to mark notifications as read")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Notifications marked as read")})
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Get the preference of the authenticated user for every notification type
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.NotificationPreference} "Notification preferences retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.notificationService.GetPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Notification preferences retrieved successfully"), Data: preferences})
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Enable or disable notification types for the authenticated user
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.UpdateNotificationPreferencesReq true "Notification preferences"
+// @Success 200 {object} model.SuccessResponse{data=[]model.NotificationPreference} "Notification preferences updated successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	var req model.UpdateNotificationPreferencesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(c.Request.Context(), user.ID, req.Preferences)
+	if err != nil {
+		respondError(c, err, "Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Notification preferences updated successfully"), Data: preferences})
+}
+
+// getAuthUser retrieves the authenticated user or return an unauthorized error if user is not present
+func (h *NotificationHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	return &user, true
+}
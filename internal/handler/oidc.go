@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOIDCProviders godoc
+// @Summary Get sign in providers
+// @Description Get the OpenID Connect providers users can sign in with instead of a password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse{data=[]model.OIDCProvider} "Sign in providers retrieved successfully"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/oidc/providers [get]
+func (h *AuthHandler) GetOIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Sign in providers retrieved successfully"), Data: h.authService.GetOIDCProviders()})
+}
+
+// StartOIDCLogin godoc
+// @Summary Start sign in with a provider
+// @Description Start signing in with an OpenID Connect provider. The client sends the user to the authorization URL and the provider sends them back to the app with a code and state to complete the sign in at /auth/oidc/callback.
+// @Description The returned state should be kept by the client to check that it matches the one sent back
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} model.SuccessResponse{data=model.OIDCAuthorizationRes} "Sign in started"
+// @Failure 404 {object} model.ErrorResponse "Provider not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Failure 502 {object} model.ErrorResponse "Provider unavailable"
+// @Router /auth/oidc/{provider}/authorize [post]
+func (h *AuthHandler) StartOIDCLogin(c *gin.Context) {
+	var param model.OIDCProviderParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate provider")
+		return
+	}
+
+	res, err := h.authService.StartOIDCLogin(c.Request.Context(), param.Provider)
+	if err != nil {
+		respondError(c, err, "Failed to start sign in")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Sign in started"), Data: res})
+}
+
+// CompleteOIDCLogin godoc
+// @Summary Complete sign in with a provider
+// @Description Exchange the code and state a provider sent the user back with for an access token. When the provider has verified the email and no account has it, a new account without a password is created.
+// @Description Identities are never linked to an existing account by email; its owner signs in and links the provider at /users/me/identities/{provider}/authorize. Users with two-factor authentication get a pre-auth token to exchange at /auth/2fa/verify instead
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.OIDCCallbackReq true "Code and state from the provider"
+// @Success 200 {object} model.SuccessResponse{data=model.LoginUserRes} "Login successful or two-factor code required"
+// @Failure 400 {object} model.ErrorResponse "Sign in is invalid or has expired"
+// @Failure 401 {object} model.ErrorResponse "Sign in with the provider failed"
+// @Failure 403 {object} model.ErrorResponse "Email not verified by the provider"
+// @Failure 409 {object} model.ErrorResponse "Account with the email must link the provider after signing in"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/oidc/callback [post]
+func (h *AuthHandler) CompleteOIDCLogin(c *gin.Context) {
+	var req model.OIDCCallbackReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	res, err := h.authService.CompleteOIDCLogin(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Failed to login")
+		return
+	}
+
+	message := "Login successful"
+	if res.TwoFactorRequired {
+		message = "Two-factor code required"
+	}
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, message), Data: res})
+}
+
+// GetIdentities godoc
+// @Summary Get linked identities
+// @Description Get the OpenID Connect identities the authenticated user can sign in with
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.UserIdentity} "Identities retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/identities [get]
+func (h *AuthHandler) GetIdentities(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	identities, err := h.authService.GetIdentities(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve identities")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Identities retrieved successfully"), Data: identities})
+}
+
+// StartOIDCLink godoc
+// @Summary Start linking a provider
+// @Description Start linking an OpenID Connect provider to the account of the authenticated user. The client sends the user to the authorization URL and the provider sends them back to the app with a code and state to complete the link at /users/me/identities/callback.
+// @Description The email at the provider does not have to match the account
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} model.SuccessResponse{data=model.OIDCAuthorizationRes} "Identity linking started"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Provider not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Failure 502 {object} model.ErrorResponse "Provider unavailable"
+// @Router /users/me/identities/{provider}/authorize [post]
+func (h *AuthHandler) StartOIDCLink(c *gin.Context) {
+	var param model.OIDCProviderParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate provider")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.authService.StartOIDCLink(c.Request.Context(), user.ID, param.Provider)
+	if err != nil {
+		respondError(c, err, "Failed to start identity linking")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Identity linking started"), Data: res})
+}
+
+// CompleteOIDCLink godoc
+// @Summary Complete linking a provider
+// @Description Exchange the code and state a provider sent the user back with for their identity and link it to the account of the authenticated user. The link must have been started by the same user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.OIDCCallbackReq true "Code and state from the provider"
+// @Success 201 {object} model.SuccessResponse{data=model.UserIdentity} "Identity linked successfully"
+// @Failure 400 {object} model.ErrorResponse "Link is invalid or has expired"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated or sign in with the provider failed"
+// @Failure 409 {object} model.ErrorResponse "Identity already linked to another account"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/identities/callback [post]
+func (h *AuthHandler) CompleteOIDCLink(c *gin.Context) {
+	var req model.OIDCCallbackReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	identity, err := h.authService.CompleteOIDCLink(c.Request.Context(), user.ID, req)
+	if err != nil {
+		respondError(c, err, "Failed to link identity")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Identity linked successfully"), Data: identity})
+}
+
+// UnlinkIdentity godoc
+// @Summary Unlink identity
+// @Description Remove an identity from the authenticated user. The last identity of an account without a password cannot be removed
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Identity ID"
+// @Success 200 {object} model.SuccessResponse "Identity unlinked successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Identity not found"
+// @Failure 409 {object} model.ErrorResponse "Identity is the only way to sign in"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/identities/{id} [delete]
+func (h *AuthHandler) UnlinkIdentity(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate identity ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.UnlinkIdentity(c.Request.Context(), user.ID, param.GetID()); err != nil {
+		respondError(c, err, "Failed to unlink identity")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Identity unlinked successfully")})
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecommendationHandler struct {
+	recommendationService *service.RecommendationService
+	translationService    *service.TranslationService
+}
+
+func NewRecommendationHandler(recommendationService *service.RecommendationService, translationService *service.TranslationService) *RecommendationHandler {
+	return &RecommendationHandler{
+		recommendationService: recommendationService,
+		translationService:    translationService,
+	}
+}
+
+// GetRecommendedJoints godoc
+// @Summary Get recommended joints
+// @Description Recommend joints within the radius that the user has neither voted on nor visited, based on joints liked by users with similar votes and check-ins and the categories the user likes. Users without enough history get popular joints near them. Each joint comes with a short explanation of why it was recommended
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request query model.NearbyJointsQuery true "Search parameters"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.RecommendedJoint} "Recommended joints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Maximum search radius exceeded or invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "API key scope insufficient"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/recommended [get]
+func (h *RecommendationHandler) GetRecommendedJoints(c *gin.Context) {
+	var query struct {
+		model.PaginationQuery
+		model.NearbyJointsQuery
+	}
+	if err := c.ShouldBind(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return
+	}
+
+	coord := model.Coordinate{Latitude: query.Latitude, Longitude: query.Longitude}
+	joints, page, err := h.recommendationService.GetRecommendedJoints(c.Request.Context(), user.ID, coord, query.Radius, query.PaginationQuery)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve recommended joints")
+		return
+	}
+	localized := make([]*model.Joint, 0, len(joints))
+	for _, joint := range joints {
+		localized = append(localized, &joint.Joint)
+	}
+	localizeJoints(c, h.translationService, localized...)
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Recommended joints retrieved successfully"), Data: joints, Pagination: page})
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReputationHandler struct {
+	reputationService *service.ReputationService
+}
+
+func NewReputationHandler(reputationService *service.ReputationService) *ReputationHandler {
+	return &ReputationHandler{
+		reputationService: reputationService,
+	}
+}
+
+// GetUserProfile godoc
+// @Summary Get user profile
+// @Description Get the public profile of a user with their reputation, trust level, unlocked abilities and badges
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} model.SuccessResponse{data=model.UserProfile} "User profile retrieved successfully"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/{id}/profile [get]
+func (h *ReputationHandler) GetUserProfile(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate user ID")
+		return
+	}
+
+	profile, err := h.reputationService.GetProfile(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve user profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "User profile retrieved successfully"), Data: profile})
+}
+
+// GetReputationLedger godoc
+// @Summary Get reputation ledger
+// @Description Get the reputation points earned and lost by the authenticated user, newest first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.ReputationEntry} "Reputation ledger retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/reputation [get]
+func (h *ReputationHandler) GetReputationLedger(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return
+	}
+
+	entries, page, err := h.reputationService.GetLedger(c.Request.Context(), user.ID, pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve reputation ledger")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Reputation ledger retrieved successfully"), Data: entries, Pagination: page})
+}
+
+// GetLeaderboard godoc
+// @Summary Get leaderboard
+// @Description Rank contributors by reputation, or by the points earned for joints in a city when one is given
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Param city query string false "City slug"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.LeaderboardEntry} "Leaderboard retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 404 {object} model.ErrorResponse "City not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /leaderboard [get]
+func (h *ReputationHandler) GetLeaderboard(c *gin.Context) {
+	var query struct {
+		model.PaginationQuery
+		model.LeaderboardQuery
+	}
+	if err := c.ShouldBind(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	entries, page, err := h.reputationService.GetLeaderboard(c.Request.Context(), query.City, query.PaginationQuery)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Leaderboard retrieved successfully"), Data: entries, Pagination: page})
+}
+
+// GetLeaderboardCities godoc
+// @Summary Get leaderboard cities
+// @Description Get the cities leaderboards are available for. A joint belongs to a city when it is within its radius
+// @Tags leaderboard
+// @Accept json
+// @Produce json
+// @Success 200 {object} model.SuccessResponse{data=[]model.City} "Cities retrieved successfully"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /leaderboard/cities [get]
+func (h *ReputationHandler) GetLeaderboardCities(c *gin.Context) {
+	cities, err := h.reputationService.GetCities(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve cities")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Cities retrieved successfully"), Data: cities})
+}
+
+// GetModeratorCandidates godoc
+// @Summary Get moderator candidates
+// @Description Get the regular users whose trust level allows them to be nominated as moderators, highest reputation first (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.LeaderboardEntry} "Moderator candidates retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/moderator-candidates [get]
+func (h *ReputationHandler) GetModeratorCandidates(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	candidates, page, err := h.reputationService.GetModeratorCandidates(c.Request.Context(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve moderator candidates")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Moderator candidates retrieved successfully"), Data: candidates, Pagination: page})
+}
+
+// PromoteToModerator godoc
+// @Summary Promote user to moderator
+// @Description Make a regular user whose trust level allows moderator nomination a moderator (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} model.SuccessResponse{data=model.User} "User promoted successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "User not found"
+// @Failure 409 {object} model.ErrorResponse "User not eligible"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/users/{id}/promote [patch]
+func (h *ReputationHandler) PromoteToModerator(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate user ID")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	user, err := h.reputationService.PromoteToModerator(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to promote user")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "User promoted successfully"), Data: user})
+}
+
+// getAuthAdmin retrieves the authenticated admin or returns an error if the user is not an admin
+func (h *ReputationHandler) getAuthAdmin(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	if user.Role != model.Admin {
+		respondError(c, forbidden("User is not an admin"), "")
+		return nil, false
+	}
+	return &user, true
+}
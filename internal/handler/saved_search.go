@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedSearchHandler struct {
+	savedSearchService *service.SavedSearchService
+	translationService *service.TranslationService
+}
+
+func NewSavedSearchHandler(savedSearchService *service.SavedSearchService, translationService *service.TranslationService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+		translationService: translationService,
+	}
+}
+
+// CreateSavedSearch godoc
+// @Summary Create saved search
+// @Description Save a search to be alerted when new joints matching it are approved. The area is either a point with a radius in meters or a polygon. Alerts are sent as notifications or in a periodic email digest
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.CreateSavedSearchReq true "Search details"
+// @Success 201 {object} model.SuccessResponse{data=model.SavedSearch} "Saved search created successfully"
+// @Failure 400 {object} model.ErrorResponse "Radius too large"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 409 {object} model.ErrorResponse "Saved search limit reached"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 429 {object} model.ErrorResponse "Too many requests"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/saved-searches [post]
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req model.CreateSavedSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	data := &model.SavedSearch{
+		UserID:     user.ID,
+		Name:       req.Name,
+		Radius:     req.Radius,
+		Categories: make([]model.JointCategory, 0, len(req.Categories)),
+		Keywords:   make([]string, 0, len(req.Keywords)),
+		Delivery:   model.SavedSearchDelivery(req.Delivery),
+	}
+	if req.Radius != nil {
+		data.Center = &model.Coordinate{Latitude: *req.Latitude, Longitude: *req.Longitude}
+	}
+	for _, point := range req.Polygon {
+		data.Polygon = append(data.Polygon, model.Coordinate{Latitude: point.Latitude, Longitude: point.Longitude})
+	}
+	for _, category := range req.Categories {
+		data.Categories = append(data.Categories, model.JointCategory(category))
+	}
+	data.Keywords = append(data.Keywords, req.Keywords...)
+
+	search, err := h.savedSearchService.CreateSavedSearch(c.Request.Context(), data)
+	if err != nil {
+		respondError(c, err, "Failed to create saved search")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Saved search created successfully"), Data: search})
+}
+
+// GetSavedSearches godoc
+// @Summary Get saved searches
+// @Description Get the saved searches of the authenticated user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=[]model.SavedSearch} "Saved searches retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/saved-searches [get]
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	searches, err := h.savedSearchService.GetSavedSearches(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve saved searches")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Saved searches retrieved successfully"), Data: searches})
+}
+
+// GetSavedSearchMatches godoc
+// @Summary Get saved search matches
+// @Description Get the joints matched by a saved search of the authenticated user, newest first
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Saved search ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.SavedSearchMatch} "Matches retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Saved search not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/saved-searches/{id}/matches [get]
+func (h *SavedSearchHandler) GetSavedSearchMatches(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate saved search ID")
+		return
+	}
+
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	matches, page, err := h.savedSearchService.GetSavedSearchMatches(c.Request.Context(), user.ID, param.GetID(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve matches")
+		return
+	}
+	joints := make([]*model.Joint, 0, len(matches))
+	for _, match := range matches {
+		if match.Joint != nil {
+			joints = append(joints, match.Joint)
+		}
+	}
+	localizeJoints(c, h.translationService, joints...)
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Matches retrieved successfully"), Data: matches, Pagination: page})
+}
+
+// DeleteSavedSearch godoc
+// @Summary Delete saved search
+// @Description Delete a saved search of the authenticated user. No further alerts are sent for it
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Saved search ID"
+// @Success 200 {object} model.SuccessResponse "Saved search deleted successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 404 {object} model.ErrorResponse "Saved search not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /users/me/saved-searches/{id} [delete]
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate saved search ID")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.savedSearchService.DeleteSavedSearch(c.Request.Context(), user.ID, param.GetID()); err != nil {
+		respondError(c, err, "Failed to delete saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Saved search deleted successfully")})
+}
+
+// getAuthUser retrieves the authenticated user or return an unauthorized error if user is not present
+func (h *SavedSearchHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	return &user, true
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/net/websocket"
+)
+
+// streamHeartbeatInterval keeps idle event streams from being closed by proxies
+const streamHeartbeatInterval = 25 * time.Second
+
+type StreamHandler struct {
+	streamService *service.StreamService
+}
+
+func NewStreamHandler(streamService *service.StreamService) *StreamHandler {
+	return &StreamHandler{
+		streamService: streamService,
+	}
+}
+
+// Stream godoc
+// @Summary Stream joint updates
+// @Description Receive real-time vote changes, newly approved joints and status changes for the given joints or within an area. Updates are sent as server-sent events, or as JSON messages when the request is upgraded to a websocket
+// @Tags stream
+// @Produce text/event-stream
+// @Param request query model.StreamQuery false "Joints and area to subscribe to"
+// @Success 200 {object} model.StreamMessage "Stream of updates"
+// @Failure 400 {object} model.ErrorResponse "Radius too large"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Router /stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	var query model.StreamQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	filter := model.StreamFilter{}
+	for _, id := range query.JointIDs {
+		filter.JointIDs = append(filter.JointIDs, uuid.MustParse(id))
+	}
+	if query.Radius != nil {
+		filter.Area = &model.StreamArea{
+			Center: model.Coordinate{Latitude: *query.Latitude, Longitude: *query.Longitude},
+			Radius: *query.Radius,
+		}
+	}
+	if len(filter.JointIDs) == 0 && filter.Area == nil {
+		respondError(c, &apiError{status: http.StatusUnprocessableEntity, code: model.ValidationFailedCode, message: "Subscribe to at least one joint or an area"}, "")
+		return
+	}
+
+	sub, err := h.streamService.Subscribe(filter)
+	if err != nil {
+		respondError(c, err, "Failed to subscribe to updates")
+		return
+	}
+	defer h.streamService.Unsubscribe(sub)
+
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		h.serveWebSocket(c, sub)
+		return
+	}
+	h.serveEvents(c, sub)
+}
+
+// serveEvents sends updates as server-sent events until the client disconnects or the subscription ends
+func (h *StreamHandler) serveEvents(c *gin.Context, sub *service.StreamSubscription) {
+	// the stream outlives the server write timeout
+	rc := http.NewResponseController(c.Writer)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Println(err)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+
+		case message, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(message)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", message.ID, message.Type, data); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// serveWebSocket sends updates as JSON websocket messages until the client disconnects or the subscription ends
+func (h *StreamHandler) serveWebSocket(c *gin.Context, sub *service.StreamSubscription) {
+	server := websocket.Server{
+		// origins are not restricted, in line with the CORS policy
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			// the connection outlives the server read and write timeouts
+			if err := ws.SetDeadline(time.Time{}); err != nil {
+				log.Println(err)
+			}
+
+			// clients only send control frames so reading is just used to detect disconnects
+			closed := make(chan struct{})
+			go func() {
+				_, _ = io.Copy(io.Discard, ws)
+				close(closed)
+			}()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case message, ok := <-sub.Messages():
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(ws, message); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
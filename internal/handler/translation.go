@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TranslationHandler struct {
+	translationService *service.TranslationService
+}
+
+func NewTranslationHandler(translationService *service.TranslationService) *TranslationHandler {
+	return &TranslationHandler{
+		translationService: translationService,
+	}
+}
+
+// GetJointTranslations godoc
+// @Summary Get joint translations
+// @Description Get the names and descriptions of a joint in the locales other than the one it was created in
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Param id path string true "Joint ID"
+// @Success 200 {object} model.SuccessResponse{data=[]model.JointTranslation} "Translations retrieved successfully"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/translations [get]
+func (h *TranslationHandler) GetJointTranslations(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate joint ID")
+		return
+	}
+
+	translations, err := h.translationService.GetJointTranslations(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve translations")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Translations retrieved successfully"), Data: translations})
+}
+
+// SaveJointTranslation godoc
+// @Summary Save joint translation
+// @Description Add or replace the name and description of a joint in a locale. Content in the locale the joint was created in is edited on the joint itself. Admins and moderators only
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Joint ID"
+// @Param locale path string true "Locale" Enums(en, fr, tw)
+// @Param request body model.SaveJointTranslationReq true "Translated joint details"
+// @Success 200 {object} model.SuccessResponse{data=model.JointTranslation} "Translation saved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Joint not found"
+// @Failure 409 {object} model.ErrorResponse "Locale is the joint's default locale"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/translations/{locale} [put]
+func (h *TranslationHandler) SaveJointTranslation(c *gin.Context) {
+	var param model.TranslationParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate translation params")
+		return
+	}
+
+	var req model.SaveJointTranslationReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthAdminOrModerator(c)
+	if !ok {
+		return
+	}
+
+	translation, err := h.translationService.SaveJointTranslation(c.Request.Context(), param.GetID(), param.Locale, user.ID, req)
+	if err != nil {
+		respondError(c, err, "Failed to save translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Translation saved successfully"), Data: translation})
+}
+
+// DeleteJointTranslation godoc
+// @Summary Delete joint translation
+// @Description Remove the name and description of a joint in a locale. Admins and moderators only
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Joint ID"
+// @Param locale path string true "Locale" Enums(en, fr, tw)
+// @Success 200 {object} model.SuccessResponse "Translation deleted successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Translation not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/{id}/translations/{locale} [delete]
+func (h *TranslationHandler) DeleteJointTranslation(c *gin.Context) {
+	var param model.TranslationParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate translation params")
+		return
+	}
+
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
+		return
+	}
+
+	if err := h.translationService.DeleteJointTranslation(c.Request.Context(), param.GetID(), param.Locale); err != nil {
+		respondError(c, err, "Failed to delete translation")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Translation deleted successfully")})
+}
+
+// getAuthAdminOrModerator retrieves the authenticated user or returns an error if the user is not an admin or moderator
+func (h *TranslationHandler) getAuthAdminOrModerator(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	if user.Role != model.Moderator && user.Role != model.Admin {
+		respondError(c, forbidden("User is not an admin or moderator"), "")
+		return nil, false
+	}
+	return &user, true
+}
+
+// localizeJoints translates the joints to the locales accepted by the request. The joints are still returned untranslated when this fails
+func localizeJoints(c *gin.Context, translationService *service.TranslationService, joints ...*model.Joint) {
+	locales := service.RequestInfoFromContext(c.Request.Context()).Locales
+	if err := translationService.LocalizeJoints(c.Request.Context(), locales, joints...); err != nil {
+		log.Println(err)
+	}
+}
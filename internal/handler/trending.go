@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrendingHandler struct {
+	trendingService    *service.TrendingService
+	translationService *service.TranslationService
+}
+
+func NewTrendingHandler(trendingService *service.TrendingService, translationService *service.TranslationService) *TrendingHandler {
+	return &TrendingHandler{
+		trendingService:    trendingService,
+		translationService: translationService,
+	}
+}
+
+// GetTrendingJoints godoc
+// @Summary Get trending joints
+// @Description Rank joints within the radius by how far their votes, check-ins and views within the window are above their usual activity
+// @Tags joints
+// @Accept json
+// @Produce json
+// @Param request query model.TrendingJointsQuery true "Search parameters"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.TrendingJoint} "Trending joints retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Maximum search radius exceeded or invalid cursor"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /joints/trending [get]
+func (h *TrendingHandler) GetTrendingJoints(c *gin.Context) {
+	var query struct {
+		model.PaginationQuery
+		model.TrendingJointsQuery
+	}
+	if err := c.ShouldBind(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	coord := model.Coordinate{Latitude: query.Latitude, Longitude: query.Longitude}
+	joints, page, err := h.trendingService.GetTrendingJoints(c.Request.Context(), coord, query.Radius, model.TrendingWindow(query.Window), query.PaginationQuery)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve trending joints")
+		return
+	}
+	localized := make([]*model.Joint, 0, len(joints))
+	for _, joint := range joints {
+		localized = append(localized, &joint.Joint)
+	}
+	localizeJoints(c, h.translationService, localized...)
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Trending joints retrieved successfully"), Data: joints, Pagination: page})
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyTwoFactor godoc
+// @Summary Verify two-factor code
+// @Description Complete the login of a user with two-factor authentication by exchanging the pre-auth token from /auth/login and a code from their authenticator app, or a recovery code, for an access token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyTwoFactorReq true "Pre-auth token and code"
+// @Success 200 {object} model.SuccessResponse{data=model.LoginUserRes} "Login successful"
+// @Failure 401 {object} model.ErrorResponse "Invalid or expired pre-auth token or invalid code"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 423 {object} model.ErrorResponse "Account temporarily locked after too many failed logins"
+// @Failure 429 {object} model.ErrorResponse "Too many requests or failed logins"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req model.VerifyTwoFactorReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	res, err := h.authService.VerifyTwoFactor(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "Failed to login")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Login successful"), Data: res})
+}
+
+// GetTwoFactorStatus godoc
+// @Summary Get two-factor status
+// @Description Get whether the authenticated user has enabled two-factor authentication and how many recovery codes they have left
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=model.TwoFactorStatus} "Two-factor status retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/2fa [get]
+func (h *AuthHandler) GetTwoFactorStatus(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.authService.GetTwoFactorStatus(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve two-factor status")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Two-factor status retrieved successfully"), Data: status})
+}
+
+// SetupTwoFactor godoc
+// @Summary Set up two-factor authentication
+// @Description Generate a TOTP secret and the provisioning URI to show as a QR code for authenticator apps. Two-factor authentication is only turned on once a code is confirmed at /auth/2fa/enable
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} model.SuccessResponse{data=model.TwoFactorSetupRes} "Two-factor setup started"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 409 {object} model.ErrorResponse "Two-factor authentication already enabled"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	setup, err := h.authService.SetupTwoFactor(c.Request.Context(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to set up two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Two-factor setup started"), Data: setup})
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable two-factor authentication
+// @Description Turn on two-factor authentication with a code from the authenticator app set up at /auth/2fa/setup. The recovery codes are only shown in this response. Privileged roles need a new login for their privileges to apply
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.TwoFactorCodeReq true "Code from the authenticator app"
+// @Success 200 {object} model.SuccessResponse{data=model.RecoveryCodesRes} "Two-factor authentication enabled successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated or invalid code"
+// @Failure 409 {object} model.ErrorResponse "Two-factor authentication not set up or already enabled"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/2fa/enable [post]
+func (h *AuthHandler) EnableTwoFactor(c *gin.Context) {
+	var req model.TwoFactorCodeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.authService.EnableTwoFactor(c.Request.Context(), user.ID, req.Code)
+	if err != nil {
+		respondError(c, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Two-factor authentication enabled successfully"), Data: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication. The user must confirm their password and a code from their authenticator app or a recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ReauthenticateReq true "Password and code"
+// @Success 200 {object} model.SuccessResponse "Two-factor authentication disabled successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated, invalid credentials or invalid code"
+// @Failure 409 {object} model.ErrorResponse "Two-factor authentication not enabled"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 423 {object} model.ErrorResponse "Account temporarily locked after too many failed logins"
+// @Failure 429 {object} model.ErrorResponse "Too many requests or failed logins"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req model.ReauthenticateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(c.Request.Context(), user.ID, req); err != nil {
+		respondError(c, err, "Failed to disable two-factor authentication")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Two-factor authentication disabled successfully")})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate recovery codes
+// @Description Replace the recovery codes of the authenticated user. The user must confirm their password and a code from their authenticator app or a recovery code. The new codes are only shown in this response
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body model.ReauthenticateReq true "Password and code"
+// @Success 200 {object} model.SuccessResponse{data=model.RecoveryCodesRes} "Recovery codes regenerated successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated, invalid credentials or invalid code"
+// @Failure 409 {object} model.ErrorResponse "Two-factor authentication not enabled"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 423 {object} model.ErrorResponse "Account temporarily locked after too many failed logins"
+// @Failure 429 {object} model.ErrorResponse "Too many requests or failed logins"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /auth/2fa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req model.ReauthenticateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthUser(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), user.ID, req)
+	if err != nil {
+		respondError(c, err, "Failed to regenerate recovery codes")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Recovery codes regenerated successfully"), Data: codes})
+}
+
+// getAuthUser retrieves the authenticated user or return an unauthorized error if user is not present
+func (h *AuthHandler) getAuthUser(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	return &user, true
+}
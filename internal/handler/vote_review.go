@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type VoteReviewHandler struct {
+	voteIntegrityService *service.VoteIntegrityService
+}
+
+func NewVoteReviewHandler(voteIntegrityService *service.VoteIntegrityService) *VoteReviewHandler {
+	return &VoteReviewHandler{
+		voteIntegrityService: voteIntegrityService,
+	}
+}
+
+// GetVoteReviews godoc
+// @Summary Get vote reviews
+// @Description Get the clusters of suspicious votes queued for moderation, most recently updated first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param status query string false "Review status" Enums(open, voided, dismissed)
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.VoteReview} "Vote reviews retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/vote-reviews [get]
+func (h *VoteReviewHandler) GetVoteReviews(c *gin.Context) {
+	var query struct {
+		model.PaginationQuery
+		model.VoteReviewQuery
+	}
+	if err := c.ShouldBind(&query); err != nil {
+		respondValidationError(c, err, "Failed to validate query params")
+		return
+	}
+
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
+		return
+	}
+
+	reviews, page, err := h.voteIntegrityService.GetVoteReviews(c.Request.Context(), model.VoteReviewStatus(query.Status), query.PaginationQuery)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve vote reviews")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Vote reviews retrieved successfully"), Data: reviews, Pagination: page})
+}
+
+// GetVoteReview godoc
+// @Summary Get one vote review
+// @Description Get a single vote review by ID
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Vote review ID"
+// @Success 200 {object} model.SuccessResponse{data=model.VoteReview} "Vote review retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Vote review not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/vote-reviews/{id} [get]
+func (h *VoteReviewHandler) GetVoteReview(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate vote review ID")
+		return
+	}
+
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
+		return
+	}
+
+	review, err := h.voteIntegrityService.GetVoteReviewByID(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve vote review")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Vote review retrieved successfully"), Data: review})
+}
+
+// GetVoteReviewVotes godoc
+// @Summary Get vote review votes
+// @Description Get the votes in a vote review along with the signals that made them suspicious, riskiest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Vote review ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.ReviewedVote} "Votes retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Vote review not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/vote-reviews/{id}/votes [get]
+func (h *VoteReviewHandler) GetVoteReviewVotes(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate vote review ID")
+		return
+	}
+
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	if _, ok := h.getAuthAdminOrModerator(c); !ok {
+		return
+	}
+
+	votes, page, err := h.voteIntegrityService.GetVoteReviewVotes(c.Request.Context(), param.GetID(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve votes")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Votes retrieved successfully"), Data: votes, Pagination: page})
+}
+
+// VoidVotes godoc
+// @Summary Void votes
+// @Description Void the votes of an open vote review, or the given subset of them, and resolve the review. Voided votes no longer count towards the joint's vote counts and score
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Vote review ID"
+// @Param request body model.VoidVotesReq false "Votes to void"
+// @Success 200 {object} model.SuccessResponse{data=model.VoteReview} "Votes voided successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Vote review not found"
+// @Failure 409 {object} model.ErrorResponse "Vote review already resolved"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/vote-reviews/{id}/void [post]
+func (h *VoteReviewHandler) VoidVotes(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate vote review ID")
+		return
+	}
+
+	var req model.VoidVotesReq
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondValidationError(c, err, "Failed to validate data")
+			return
+		}
+	}
+
+	user, ok := h.getAuthAdminOrModerator(c)
+	if !ok {
+		return
+	}
+
+	voteIDs := make([]uuid.UUID, 0, len(req.VoteIDs))
+	for _, id := range req.VoteIDs {
+		voteIDs = append(voteIDs, uuid.MustParse(id))
+	}
+
+	review, err := h.voteIntegrityService.VoidVotes(c.Request.Context(), param.GetID(), voteIDs, user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to void votes")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Votes voided successfully"), Data: review})
+}
+
+// DismissVoteReview godoc
+// @Summary Dismiss vote review
+// @Description Resolve an open vote review without voiding any of its votes
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Vote review ID"
+// @Success 200 {object} model.SuccessResponse{data=model.VoteReview} "Vote review dismissed successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Vote review not found"
+// @Failure 409 {object} model.ErrorResponse "Vote review already resolved"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/vote-reviews/{id}/dismiss [patch]
+func (h *VoteReviewHandler) DismissVoteReview(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate vote review ID")
+		return
+	}
+
+	user, ok := h.getAuthAdminOrModerator(c)
+	if !ok {
+		return
+	}
+
+	review, err := h.voteIntegrityService.DismissVoteReview(c.Request.Context(), param.GetID(), user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to dismiss vote review")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Vote review dismissed successfully"), Data: review})
+}
+
+// getAuthAdminOrModerator retrieves the authenticated admin or moderator or returns an error response if the user is not one
+func (h *VoteReviewHandler) getAuthAdminOrModerator(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	user, ok := GetCurrentUser(c)
+	if !ok {
+		respondError(c, unauthenticated("User is not authorized"), "")
+		return nil, false
+	}
+	if user.Role != model.Moderator && user.Role != model.Admin {
+		respondError(c, forbidden("User is not an admin or moderator"), "")
+		return nil, false
+	}
+	return &user, true
+}
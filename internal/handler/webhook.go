@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"chow/internal/model"
+	"chow/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Create webhook
+// @Description Subscribe an endpoint to joint events. Payloads are signed with HMAC-SHA256 in the X-Chow-Signature header as t=<unix timestamp>,v1=<hex signature of "<timestamp>.<body>">. The secret is only returned in this response. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body model.CreateWebhookReq true "Webhook details"
+// @Success 201 {object} model.SuccessResponse{data=model.CreatedWebhook} "Webhook created successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req model.CreateWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	user, ok := h.getAuthAdmin(c)
+	if !ok {
+		return
+	}
+
+	data := &model.Webhook{
+		URL:        req.URL,
+		EventTypes: toEventTypes(req.EventTypes),
+		CreatedBy:  user.ID,
+	}
+	if req.Secret != nil {
+		data.Secret = *req.Secret
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.Request.Context(), data)
+	if err != nil {
+		respondError(c, err, "Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, model.SuccessResponse{Message: translate(c, "Webhook created successfully"), Data: webhook})
+}
+
+// GetWebhooks godoc
+// @Summary Get webhooks
+// @Description Get all webhooks, newest first. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.Webhook} "Webhooks retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	webhooks, page, err := h.webhookService.GetWebhooks(c.Request.Context(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhooks")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Webhooks retrieved successfully"), Data: webhooks, Pagination: page})
+}
+
+// GetWebhook godoc
+// @Summary Get webhook
+// @Description Get a webhook by its ID. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} model.SuccessResponse{data=model.Webhook} "Webhook retrieved successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Webhook not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate webhook ID")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhookByID(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Webhook retrieved successfully"), Data: webhook})
+}
+
+// UpdateWebhook godoc
+// @Summary Update webhook
+// @Description Change the endpoint, secret or subscribed events of a webhook, or re-enable a webhook disabled after repeated failures. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param request body model.UpdateWebhookReq true "Webhook details"
+// @Success 200 {object} model.SuccessResponse{data=model.Webhook} "Webhook updated successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Webhook not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks/{id} [patch]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate webhook ID")
+		return
+	}
+
+	var req model.UpdateWebhookReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondValidationError(c, err, "Failed to validate data")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	existing, err := h.webhookService.GetWebhookByID(c.Request.Context(), param.GetID())
+	if err != nil {
+		respondError(c, err, "Failed to update webhook")
+		return
+	}
+
+	// only the provided fields are changed
+	data := *existing
+	if req.URL != nil {
+		data.URL = *req.URL
+	}
+	if req.Secret != nil {
+		data.Secret = *req.Secret
+	}
+	if req.EventTypes != nil {
+		data.EventTypes = toEventTypes(req.EventTypes)
+	}
+	if req.IsActive != nil {
+		data.IsActive = *req.IsActive
+	}
+
+	webhook, err := h.webhookService.UpdateWebhookByID(c.Request.Context(), param.GetID(), &data)
+	if err != nil {
+		respondError(c, err, "Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Webhook updated successfully"), Data: webhook})
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook
+// @Description Remove a webhook along with its delivery log. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} model.SuccessResponse "Webhook deleted successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Webhook not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate webhook ID")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhookByID(c.Request.Context(), param.GetID()); err != nil {
+		respondError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Webhook deleted successfully")})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get webhook deliveries
+// @Description Get the delivery log of a webhook with the response code of the last attempt, newest first. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param page query int false "Page number" default(1)
+// @Param pageSize query int false "Page size" default(10)
+// @Param cursor query string false "Cursor of the next page returned with the previous page"
+// @Param includeTotal query bool false "Count the records across all pages"
+// @Success 200 {object} model.SuccessResponse{data=[]model.WebhookDelivery} "Webhook deliveries retrieved successfully"
+// @Failure 400 {object} model.ErrorResponse "Invalid cursor"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Webhook not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetWebhookDeliveries(c *gin.Context) {
+	var param model.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate webhook ID")
+		return
+	}
+
+	var pagination model.PaginationQuery
+	if err := c.ShouldBind(&pagination); err != nil {
+		respondValidationError(c, err, "Failed to validate pagination params")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	deliveries, page, err := h.webhookService.GetWebhookDeliveries(c.Request.Context(), param.GetID(), pagination)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SuccessResponse{Message: translate(c, "Webhook deliveries retrieved successfully"), Data: deliveries, Pagination: page})
+}
+
+// RedeliverWebhookDelivery godoc
+// @Summary Redeliver webhook delivery
+// @Description Queue a delivery to be sent again with a fresh attempt count. Admins only Endpoint
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Param deliveryId path string true "Delivery ID"
+// @Success 202 {object} model.SuccessResponse{data=model.WebhookDelivery} "Delivery queued successfully"
+// @Failure 401 {object} model.ErrorResponse "User not authenticated"
+// @Failure 403 {object} model.ErrorResponse "User not authorized"
+// @Failure 404 {object} model.ErrorResponse "Delivery not found"
+// @Failure 422 {object} model.ErrorResponse "Validation error"
+// @Failure 500 {object} model.ErrorResponse "Server error"
+// @Router /admin/webhooks/{id}/deliveries/{deliveryId}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhookDelivery(c *gin.Context) {
+	var param model.WebhookDeliveryParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		respondValidationError(c, err, "Failed to validate delivery ID")
+		return
+	}
+
+	if _, ok := h.getAuthAdmin(c); !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.RedeliverWebhookDelivery(c.Request.Context(), param.GetID(), param.GetDeliveryID())
+	if err != nil {
+		respondError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, model.SuccessResponse{Message: translate(c, "Delivery queued successfully"), Data: delivery})
+}
+
+// getAuthAdmin retrieves the authenticated admin or returns an error if the user is not an admin
+func (h *WebhookHandler) getAuthAdmin(c *gin.Context) (*model.AuthenticatedUser, bool) {
+	if _, ok := GetCurrentUser(c); !ok {
+		respondError(c, errUnauthenticated, "")
+		return nil, false
+	}
+	user, ok := GetCurrentAdmin(c)
+	if !ok {
+		respondError(c, forbidden("User is not an admin"), "")
+		return nil, false
+	}
+	return &user, true
+}
+
+// toEventTypes converts validated event type names to event types
+func toEventTypes(names []string) []model.EventType {
+	eventTypes := make([]model.EventType, 0, len(names))
+	for _, name := range names {
+		eventTypes = append(eventTypes, model.EventType(name))
+	}
+	return eventTypes
+}
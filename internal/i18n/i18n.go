@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Locales supported by the API
+const (
+	English = "en"
+	French  = "fr"
+	Twi     = "tw"
+)
+
+// DefaultLocale is used when the client accepts none of the supported locales. Messages are written in it
+const DefaultLocale = English
+
+// SupportedLocales are the locales messages and joint content can be translated to
+var SupportedLocales = []string{English, French, Twi}
+
+// catalogueFiles hold the translations of the messages, keyed by the English message. English needs no catalogue
+//
+//go:embed locales/*.json
+var catalogueFiles embed.FS
+
+var (
+	catalogues = loadCatalogues()
+	matcher    = language.NewMatcher([]language.Tag{language.English, language.French, language.Make(Twi)})
+)
+
+func loadCatalogues() map[string]map[string]string {
+	entries, err := catalogueFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogues := make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		data, err := catalogueFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Errorf("invalid message catalogue %s: %w", entry.Name(), err))
+		}
+		catalogues[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	return catalogues
+}
+
+// IsSupported reports whether the locale is supported
+func IsSupported(locale string) bool {
+	return slices.Contains(SupportedLocales, locale)
+}
+
+// Negotiate returns the supported locales accepted by an Accept-Language header, most preferred first. Regional variants and related languages match their supported locale, so fr-CA matches French and Akan matches Twi
+func Negotiate(acceptLanguage string) []string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil {
+		return nil
+	}
+
+	var locales []string
+	for _, tag := range tags {
+		_, index, confidence := matcher.Match(tag)
+		if confidence < language.High {
+			continue
+		}
+		locale := SupportedLocales[index]
+		if !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// Preferred returns the most preferred of the accepted locales, or the default locale when none was accepted
+func Preferred(locales []string) string {
+	if len(locales) == 0 {
+		return DefaultLocale
+	}
+	return locales[0]
+}
+
+// Translate returns the message in the locale. Messages missing from the catalogue are returned untranslated
+func Translate(locale, message string) string {
+	if translated, ok := catalogues[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats the translation of the format in the locale
+func Sprintf(locale, format string, args ...any) string {
+	return fmt.Sprintf(Translate(locale, format), args...)
+}
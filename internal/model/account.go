@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataExportStatus is the progress of an archive of a user's personal data
+type DataExportStatus string
+
+const (
+	DataExportPending   DataExportStatus = "pending"
+	DataExportCompleted DataExportStatus = "completed"
+	DataExportFailed    DataExportStatus = "failed"
+)
+
+// DataExport is an archive of the personal data of a user. It is built by a background job and can be downloaded until it expires
+type DataExport struct {
+	ID          uuid.UUID        `json:"id"`
+	UserID      uuid.UUID        `json:"userId"`
+	Status      DataExportStatus `json:"status"`
+	SizeBytes   *int64           `json:"sizeBytes"`
+	Attempts    int              `json:"-"`
+	LastError   *string          `json:"-"`
+	CompletedAt *time.Time       `json:"completedAt"`
+	ExpiresAt   *time.Time       `json:"expiresAt"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+// DataExportArchive is the file of a completed export
+type DataExportArchive struct {
+	Archive   []byte
+	CreatedAt time.Time
+}
+
+// PersonalDataSection is a file of a data export holding one kind of the user's data as JSON
+type PersonalDataSection struct {
+	Name string
+	Data json.RawMessage
+}
+
+// DeleteAccountReq confirms the identity of a user asking for their account to be deleted. The password is left out by users without one and the code by users without two-factor authentication
+type DeleteAccountReq struct {
+	Password     string `json:"password"`
+	Code         string `json:"code" binding:"excluded_with=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recoveryCode" binding:"omitempty,max=20"`
+}
+
+// DeleteAccountRes tells the user when their account will be anonymised. It can be cancelled until then
+type DeleteAccountRes struct {
+	DeletionScheduledAt time.Time `json:"deletionScheduledAt"`
+}
@@ -1,6 +1,8 @@
 package model
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+)
 
 const (
 	DefaultPageSize = 10
@@ -8,18 +10,27 @@ const (
 )
 
 type SuccessResponse struct {
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
+	Message    string      `json:"message"`
+	Data       any         `json:"data,omitempty"`
+	Pagination *Pagination `json:"pagination,omitempty"`
 }
 
 type ErrorResponse struct {
-	Message string `json:"message"`
-	Detail  any    `json:"detail,omitempty"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Detail  any       `json:"detail,omitempty"`
+	// Fields maps the request fields that failed validation to the rules they broke
+	Fields map[string][]FieldError `json:"fields,omitempty"`
 }
 
+// PaginationQuery selects a page of a list. Lists are paged with the cursor returned with the previous page, while page numbers are still accepted for compatibility
 type PaginationQuery struct {
 	Page     int `form:"page" binding:"omitempty,gte=1"`
 	PageSize int `form:"pageSize" binding:"omitempty,gte=1,lte=100"`
+	// Cursor continues the list after the page it was returned with. The page number is ignored when it is set
+	Cursor string `form:"cursor"`
+	// IncludeTotal counts the records across all pages
+	IncludeTotal bool `form:"includeTotal"`
 }
 
 func (p *PaginationQuery) GetOffsetAndLimit() (int, int) {
@@ -35,6 +46,45 @@ func (p *PaginationQuery) GetOffsetAndLimit() (int, int) {
 	return offset, p.PageSize
 }
 
+// Page selects the records of a list to read, either after the sort key of the last record of the previous page or at an offset
+type Page struct {
+	// After holds the sort key of the last record of the previous page. Offset is ignored when it is set
+	After  []string
+	Offset int
+	// Limit is the number of records to read. It is one more than the page size so that the presence of a next page is known
+	Limit        int
+	IncludeTotal bool
+}
+
+// Paged holds the records read for a page. Total is only counted when the page asks for it
+type Paged[T any] struct {
+	Items []T
+	Total *int
+}
+
+// Pagination describes the page of records in a response
+type Pagination struct {
+	// NextCursor continues the list after this page. It is only set when there are more records
+	NextCursor *string `json:"nextCursor"`
+	HasMore    bool    `json:"hasMore"`
+	// Total is the number of records across all pages. It is only set when requested with includeTotal
+	Total    *int `json:"total,omitempty"`
+	PageSize int  `json:"pageSize"`
+	// Page is the page number when the list is paged by page numbers
+	Page int `json:"page,omitempty"`
+}
+
+// TargetType is the kind of record an audit event or notification refers to
+type TargetType string
+
+const (
+	JointTarget      TargetType = "joint"
+	ComplaintTarget  TargetType = "complaint"
+	UserTarget       TargetType = "user"
+	WebhookTarget    TargetType = "webhook"
+	VoteReviewTarget TargetType = "vote_review"
+)
+
 // params with only ID
 type IDParam struct {
 	ID string `uri:"id" binding:"required,uuid"`
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyScope is an area of the api a personal API key can be used for
+type APIKeyScope string
+
+const (
+	JointsReadScope APIKeyScope = "joints:read"
+	VotesWriteScope APIKeyScope = "votes:write"
+	// AdminScope lets a key act with the admin or moderator privileges of its owner. It also grants every other scope
+	AdminScope APIKeyScope = "admin"
+)
+
+// APIKey is a personal key an integration authenticates as its owner with. The key itself is only shown when it is created and its prefix identifies it afterwards
+type APIKey struct {
+	ID         uuid.UUID     `json:"id"`
+	UserID     uuid.UUID     `json:"userId"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	KeyHash    string        `json:"-"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	ExpiresAt  time.Time     `json:"expiresAt"`
+	LastUsedAt *time.Time    `json:"lastUsedAt"`
+	LastUsedIP *string       `json:"lastUsedIp"`
+	RevokedAt  *time.Time    `json:"revokedAt"`
+	CreatedAt  time.Time     `json:"createdAt"`
+}
+
+// HasScope reports whether the key grants the scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope || granted == AdminScope {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateAPIKeyReq struct {
+	Name   string   `json:"name" binding:"required,max=100"`
+	Scopes []string `json:"scopes" binding:"required,min=1,max=3,unique,dive,oneof=joints:read votes:write admin"`
+	// ExpiresAt defaults to the longest lifetime allowed
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// CreateAPIKeyRes holds a newly created key. The key cannot be retrieved again
+type CreateAPIKeyRes struct {
+	APIKey *APIKey `json:"apiKey"`
+	Key    string  `json:"key"`
+}
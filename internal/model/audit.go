@@ -0,0 +1,104 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction identifies a privileged or content-changing action
+type AuditAction string
+
+const (
+	JointCreatedAction            AuditAction = "joint.created"
+	JointUpdatedAction            AuditAction = "joint.updated"
+	JointApprovedAction           AuditAction = "joint.approved"
+	JointDeletedAction            AuditAction = "joint.deleted"
+	JointRestoredAction           AuditAction = "joint.restored"
+	JointPurgedAction             AuditAction = "joint.purged"
+	JointRejectedAction           AuditAction = "joint.rejected"
+	JointVisibilityChangedAction  AuditAction = "joint.visibility_changed"
+	JointVotesReconciledAction    AuditAction = "joint.votes_reconciled"
+	JointTranslationSavedAction   AuditAction = "joint.translation_saved"
+	JointTranslationDeletedAction AuditAction = "joint.translation_deleted"
+	ComplaintCreatedAction        AuditAction = "complaint.created"
+	ComplaintResolvedAction       AuditAction = "complaint.resolved"
+	ComplaintRejectedAction       AuditAction = "complaint.rejected"
+	WebhookCreatedAction          AuditAction = "webhook.created"
+	WebhookUpdatedAction          AuditAction = "webhook.updated"
+	WebhookDeletedAction          AuditAction = "webhook.deleted"
+	WebhookRedeliveredAction      AuditAction = "webhook.redelivered"
+	VotesVoidedAction             AuditAction = "votes.voided"
+	VoteReviewDismissedAction     AuditAction = "vote_review.dismissed"
+	UserPromotedAction            AuditAction = "user.promoted"
+	UserAnonymisedAction          AuditAction = "user.anonymised"
+)
+
+// AuditEvent is an append-only record of a change made to the system. Before and After hold the JSON snapshots of the target with sensitive fields removed
+type AuditEvent struct {
+	ID         uuid.UUID       `json:"id"`
+	ActorID    *uuid.UUID      `json:"actorId"`
+	Action     AuditAction     `json:"action"`
+	TargetType TargetType      `json:"targetType"`
+	TargetID   uuid.UUID       `json:"targetId"`
+	Before     json.RawMessage `json:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" swaggertype:"object"`
+	IPAddress  *string         `json:"ipAddress"`
+	RequestID  *string         `json:"requestId"`
+	CreatedAt  time.Time       `json:"createdAt"`
+}
+
+// AuditEventFilter narrows down the audit events returned by a query. Empty fields are ignored
+type AuditEventFilter struct {
+	ActorID    *uuid.UUID
+	Action     AuditAction
+	TargetType TargetType
+	TargetID   *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+}
+
+type AuditEventQuery struct {
+	PaginationQuery
+	ActorID    string     `form:"actorId" binding:"omitempty,uuid"`
+	Action     string     `form:"action"`
+	TargetType string     `form:"targetType" binding:"omitempty,oneof=joint complaint user webhook vote_review"`
+	TargetID   string     `form:"targetId" binding:"omitempty,uuid"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// GetFilter converts the query params into a filter. IDs are expected to be validated already
+func (q *AuditEventQuery) GetFilter() AuditEventFilter {
+	filter := AuditEventFilter{
+		Action:     AuditAction(q.Action),
+		TargetType: TargetType(q.TargetType),
+		From:       q.From,
+		To:         q.To,
+	}
+	if id, err := uuid.Parse(q.ActorID); err == nil {
+		filter.ActorID = &id
+	}
+	if id, err := uuid.Parse(q.TargetID); err == nil {
+		filter.TargetID = &id
+	}
+	return filter
+}
+
+type AuditEventPage struct {
+	Events     []*AuditEvent `json:"events"`
+	NextCursor *string       `json:"nextCursor"`
+}
+
+// RequestInfo describes where a request came from. It is attached to the request context and recorded alongside audit events
+type RequestInfo struct {
+	ActorID   *uuid.UUID
+	IPAddress string
+	RequestID string
+	UserAgent string
+	// DeviceID is the identifier sent by clients that can identify the device they run on
+	DeviceID string
+	// Locales are the supported locales accepted by the client, most preferred first
+	Locales []string
+}
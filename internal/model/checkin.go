@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Checkin is a visit to a joint verified against the location of the user's device
+type Checkin struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	JointID   uuid.UUID `json:"jointId"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	// Distance is how far from the joint the check-in was made in meters
+	Distance  float64   `json:"distance"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Visit is a check-in in a user's visit history along with the joint visited
+type Visit struct {
+	Checkin
+	JointName string `json:"jointName"`
+}
+
+type CreateCheckinReq struct {
+	Longitude float64 `json:"longitude" binding:"required,longitude"`
+	Latitude  float64 `json:"latitude" binding:"required,latitude"`
+}
@@ -11,18 +11,31 @@ type ComplaintStatus string
 const (
 	OpenComplaint     ComplaintStatus = "open"
 	ResolvedComplaint ComplaintStatus = "resolved"
+	RejectedComplaint ComplaintStatus = "rejected"
+)
+
+// ComplaintCategory groups complaints so that moderation rules can be applied per kind of issue
+type ComplaintCategory string
+
+const (
+	PermanentlyClosedComplaint ComplaintCategory = "permanently_closed"
+	WrongLocationComplaint     ComplaintCategory = "wrong_location"
+	HygieneComplaint           ComplaintCategory = "hygiene"
+	OtherComplaint             ComplaintCategory = "other"
 )
 
 type Complaint struct {
-	ID        uuid.UUID       `json:"id"`
-	JointID   uuid.UUID       `json:"jointId"`
-	UserID    uuid.UUID       `json:"userId"`
-	Reason    string          `json:"reason"`
-	Status    ComplaintStatus `json:"status"`
-	CreatedAt time.Time       `json:"createdAt"`
-	UpdatedAt time.Time       `json:"updatedAt"`
+	ID        uuid.UUID         `json:"id"`
+	JointID   uuid.UUID         `json:"jointId"`
+	UserID    uuid.UUID         `json:"userId"`
+	Reason    string            `json:"reason"`
+	Category  ComplaintCategory `json:"category"`
+	Status    ComplaintStatus   `json:"status"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
 }
 
 type CreateComplaintReq struct {
-	Reason string `json:"reason" binding:"required,gt=5"`
+	Reason   string            `json:"reason" binding:"required,gt=5"`
+	Category ComplaintCategory `json:"category" binding:"omitempty,oneof=permanently_closed wrong_location hygiene other"`
 }
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ComplaintReply is a message exchanged between the complainant and moderators on a complaint
+type ComplaintReply struct {
+	ID          uuid.UUID `json:"id"`
+	ComplaintID uuid.UUID `json:"complaintId"`
+	UserID      uuid.UUID `json:"userId"`
+	Body        string    `json:"body"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type CreateComplaintReplyReq struct {
+	Body string `json:"body" binding:"required,gte=1,lte=2000"`
+}
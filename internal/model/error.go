@@ -0,0 +1,88 @@
+package model
+
+// ErrorCode identifies an error in API responses. Unlike messages, codes are stable and safe for clients to match on
+type ErrorCode string
+
+const (
+	ValidationFailedCode         ErrorCode = "VALIDATION_FAILED"
+	UnauthenticatedCode          ErrorCode = "UNAUTHENTICATED"
+	ForbiddenCode                ErrorCode = "FORBIDDEN"
+	RouteNotFoundCode            ErrorCode = "ROUTE_NOT_FOUND"
+	InternalErrorCode            ErrorCode = "INTERNAL_ERROR"
+	InvalidCursorCode            ErrorCode = "INVALID_CURSOR"
+	RadiusTooLargeCode           ErrorCode = "RADIUS_TOO_LARGE"
+	RateLimitedCode              ErrorCode = "RATE_LIMITED"
+	InvalidCredentialsCode       ErrorCode = "INVALID_CREDENTIALS"
+	LoginThrottledCode           ErrorCode = "LOGIN_THROTTLED"
+	AccountLockedCode            ErrorCode = "ACCOUNT_LOCKED"
+	InvalidUnlockTokenCode       ErrorCode = "INVALID_UNLOCK_TOKEN"
+	InvalidTwoFactorCodeCode     ErrorCode = "INVALID_TWO_FACTOR_CODE"
+	TwoFactorNotSetUpCode        ErrorCode = "TWO_FACTOR_NOT_SET_UP"
+	TwoFactorAlreadyEnabledCode  ErrorCode = "TWO_FACTOR_ALREADY_ENABLED"
+	TwoFactorNotEnabledCode      ErrorCode = "TWO_FACTOR_NOT_ENABLED"
+	InvalidTokenCode             ErrorCode = "INVALID_TOKEN"
+	TokenExpiredCode             ErrorCode = "TOKEN_EXPIRED"
+	UserNotFoundCode             ErrorCode = "USER_NOT_FOUND"
+	UsernameTakenCode            ErrorCode = "USERNAME_TAKEN"
+	OIDCProviderNotFoundCode     ErrorCode = "OIDC_PROVIDER_NOT_FOUND"
+	OIDCProviderUnavailableCode  ErrorCode = "OIDC_PROVIDER_UNAVAILABLE"
+	InvalidOIDCStateCode         ErrorCode = "INVALID_OIDC_STATE"
+	OIDCLoginFailedCode          ErrorCode = "OIDC_LOGIN_FAILED"
+	OIDCEmailNotVerifiedCode     ErrorCode = "OIDC_EMAIL_NOT_VERIFIED"
+	OIDCAccountExistsCode        ErrorCode = "OIDC_ACCOUNT_EXISTS"
+	IdentityNotFoundCode         ErrorCode = "IDENTITY_NOT_FOUND"
+	IdentityLinkedCode           ErrorCode = "IDENTITY_LINKED"
+	LastSignInMethodCode         ErrorCode = "LAST_SIGN_IN_METHOD"
+	InvalidAPIKeyCode            ErrorCode = "INVALID_API_KEY"
+	APIKeyExpiredCode            ErrorCode = "API_KEY_EXPIRED"
+	InsufficientScopeCode        ErrorCode = "INSUFFICIENT_SCOPE"
+	APIKeyNotFoundCode           ErrorCode = "API_KEY_NOT_FOUND"
+	APIKeyLimitReachedCode       ErrorCode = "API_KEY_LIMIT_REACHED"
+	InvalidAPIKeyExpiryCode      ErrorCode = "INVALID_API_KEY_EXPIRY"
+	AdminScopeNotAllowedCode     ErrorCode = "ADMIN_SCOPE_NOT_ALLOWED"
+	DataExportNotFoundCode       ErrorCode = "DATA_EXPORT_NOT_FOUND"
+	DataExportInProgressCode     ErrorCode = "DATA_EXPORT_IN_PROGRESS"
+	DataExportNotReadyCode       ErrorCode = "DATA_EXPORT_NOT_READY"
+	DeletionNotScheduledCode     ErrorCode = "DELETION_NOT_SCHEDULED"
+	NotEligibleForModeratorCode  ErrorCode = "NOT_ELIGIBLE_FOR_MODERATOR"
+	JointNotFoundCode            ErrorCode = "JOINT_NOT_FOUND"
+	JointAlreadyExistsCode       ErrorCode = "JOINT_ALREADY_EXISTS"
+	JointAlreadyApprovedCode     ErrorCode = "JOINT_ALREADY_APPROVED"
+	JointHasDependentsCode       ErrorCode = "JOINT_HAS_DEPENDENTS"
+	VoteNotFoundCode             ErrorCode = "VOTE_NOT_FOUND"
+	VoteVoidedCode               ErrorCode = "VOTE_VOIDED"
+	CheckinTooFarCode            ErrorCode = "CHECKIN_TOO_FAR"
+	CheckinTooSoonCode           ErrorCode = "CHECKIN_TOO_SOON"
+	ComplaintNotFoundCode        ErrorCode = "COMPLAINT_NOT_FOUND"
+	ComplaintAlreadyExistsCode   ErrorCode = "COMPLAINT_ALREADY_EXISTS"
+	NotificationNotFoundCode     ErrorCode = "NOTIFICATION_NOT_FOUND"
+	SavedSearchNotFoundCode      ErrorCode = "SAVED_SEARCH_NOT_FOUND"
+	SavedSearchLimitReachedCode  ErrorCode = "SAVED_SEARCH_LIMIT_REACHED"
+	CityNotFoundCode             ErrorCode = "CITY_NOT_FOUND"
+	VoteReviewNotFoundCode       ErrorCode = "VOTE_REVIEW_NOT_FOUND"
+	VoteReviewResolvedCode       ErrorCode = "VOTE_REVIEW_RESOLVED"
+	WebhookNotFoundCode          ErrorCode = "WEBHOOK_NOT_FOUND"
+	WebhookDeliveryNotFoundCode  ErrorCode = "WEBHOOK_DELIVERY_NOT_FOUND"
+	TranslationNotFoundCode      ErrorCode = "TRANSLATION_NOT_FOUND"
+	DefaultLocaleTranslationCode ErrorCode = "DEFAULT_LOCALE_TRANSLATION"
+)
+
+// FieldError describes a validation rule a request field broke
+type FieldError struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ProblemMediaType is the media type of RFC 7807 problem details. Clients opt into them by accepting it
+const ProblemMediaType = "application/problem+json"
+
+// ProblemDetails is the RFC 7807 representation of an error response
+type ProblemDetails struct {
+	Type     string                  `json:"type"`
+	Title    string                  `json:"title"`
+	Status   int                     `json:"status"`
+	Detail   string                  `json:"detail,omitempty"`
+	Instance string                  `json:"instance,omitempty"`
+	Code     ErrorCode               `json:"code"`
+	Fields   map[string][]FieldError `json:"fields,omitempty"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType identifies a domain event
+type EventType string
+
+const (
+	JointCreatedEvent           EventType = "joint.created"
+	JointUpdatedEvent           EventType = "joint.updated"
+	JointApprovedEvent          EventType = "joint.approved"
+	JointDeletedEvent           EventType = "joint.deleted"
+	JointRestoredEvent          EventType = "joint.restored"
+	JointRejectedEvent          EventType = "joint.rejected"
+	JointVisibilityChangedEvent EventType = "joint.visibility_changed"
+	VoteCastEvent               EventType = "vote.cast"
+	VoteRetractedEvent          EventType = "vote.retracted"
+	VotesVoidedEvent            EventType = "votes.voided"
+	CheckinCreatedEvent         EventType = "checkin.created"
+	ComplaintFiledEvent         EventType = "complaint.filed"
+	ComplaintResolvedEvent      EventType = "complaint.resolved"
+	ComplaintRejectedEvent      EventType = "complaint.rejected"
+	ComplaintRepliedEvent       EventType = "complaint.replied"
+)
+
+// Event is a domain event describing a state change. Payload holds the JSON snapshot of the changed record
+type Event struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        EventType       `json:"type"`
+	AggregateID uuid.UUID       `json:"aggregateId"`
+	ActorID     *uuid.UUID      `json:"actorId"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// OutboxEvent is an event waiting in the outbox along with its delivery state
+type OutboxEvent struct {
+	Event
+	Attempts int
+	// DeliveredTo lists the subscribers that have already handled the event
+	DeliveredTo []string
+}
+
+// VoteCastPayload is the payload of vote cast events
+type VoteCastPayload struct {
+	Vote *Vote `json:"vote"`
+	// PreviousDirection is set when an existing vote was flipped
+	PreviousDirection *VoteDirection `json:"previousDirection"`
+	UpVotes           int            `json:"upvotes"`
+	DownVotes         int            `json:"downvotes"`
+}
+
+// VoteRetractedPayload is the payload of vote retracted events
+type VoteRetractedPayload struct {
+	Vote      *Vote `json:"vote"`
+	UpVotes   int   `json:"upvotes"`
+	DownVotes int   `json:"downvotes"`
+}
+
+// JointRejectedPayload is the payload of joint rejected events
+type JointRejectedPayload struct {
+	Joint  *Joint  `json:"joint"`
+	Reason *string `json:"reason"`
+}
+
+// ComplaintRepliedPayload is the payload of complaint replied events
+type ComplaintRepliedPayload struct {
+	Reply     *ComplaintReply `json:"reply"`
+	Complaint *Complaint      `json:"complaint"`
+}
@@ -6,20 +6,59 @@ import (
 	"github.com/google/uuid"
 )
 
+// JointVisibility controls whether an approved joint is listed publicly. It is independent of approval so that a joint can be temporarily hidden while complaints against it are reviewed
+type JointVisibility string
+
+const (
+	VisibleJoint     JointVisibility = "visible"
+	UnderReviewJoint JointVisibility = "under_review"
+)
+
+// JointCategory is the kind of food a joint serves
+type JointCategory string
+
+const (
+	ChopBarJoint       JointCategory = "chop_bar"
+	StreetFoodJoint    JointCategory = "street_food"
+	FastFoodJoint      JointCategory = "fast_food"
+	GrillJoint         JointCategory = "grill"
+	BakeryJoint        JointCategory = "bakery"
+	CafeJoint          JointCategory = "cafe"
+	RestaurantJoint    JointCategory = "restaurant"
+	DrinksJoint        JointCategory = "drinks"
+	OtherJointCategory JointCategory = "other"
+)
+
 type Joint struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
-	Latitude    float64   `json:"latitude"`
-	Longitude   float64   `json:"longitude"`
-	Distance    *float64  `json:"distance,omitempty"`
-	Description *string   `json:"description"`
-	IsApproved  bool      `json:"isApproved"`
-	CreatorID   uuid.UUID `json:"creatorId"`
-	PhotoURL    *string   `json:"photoUrl"`
-	UpVotes     int       `json:"upvotes"`
-	DownVotes   int       `json:"downvotes"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          uuid.UUID       `json:"id"`
+	Name        string          `json:"name"`
+	Latitude    float64         `json:"latitude"`
+	Longitude   float64         `json:"longitude"`
+	Distance    *float64        `json:"distance,omitempty"`
+	Description *string         `json:"description"`
+	IsApproved  bool            `json:"isApproved"`
+	Visibility  JointVisibility `json:"visibility"`
+	Category    JointCategory   `json:"category"`
+	CreatorID   uuid.UUID       `json:"creatorId"`
+	PhotoURL    *string         `json:"photoUrl"`
+	UpVotes     int             `json:"upvotes"`
+	DownVotes   int             `json:"downvotes"`
+	// Score is the net vote count with votes of verified visitors weighted higher
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// deletion details are only populated for soft deleted joints
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
+	DeletedBy      *uuid.UUID `json:"deletedBy,omitempty"`
+	DeletionReason *string    `json:"deletionReason,omitempty"`
+	// personalised details are only populated for authenticated users
+	Visited *bool `json:"visited,omitempty"`
+	// UserVote is the direction of the user's vote on the joint, if any
+	UserVote *VoteDirection `json:"userVote,omitempty"`
+	// localised details are only populated for joints read by clients. Locale is the locale of the name and description, DefaultLocale the locale the joint was created in, and Locales every locale the joint is available in
+	Locale        string   `json:"locale,omitempty"`
+	DefaultLocale string   `json:"defaultLocale,omitempty"`
+	Locales       []string `json:"locales,omitempty"`
 }
 
 type CreateJointReq struct {
@@ -27,6 +66,9 @@ type CreateJointReq struct {
 	Longitude   float64 `json:"longitude" binding:"required,longitude"`
 	Latitude    float64 `json:"latitude" binding:"required,latitude"`
 	Description *string `json:"description"`
+	Category    string  `json:"category" binding:"omitempty,oneof=chop_bar street_food fast_food grill bakery cafe restaurant drinks other"`
+	// Locale is the locale the name and description are written in. It is only set when the joint is created
+	Locale string `json:"locale" binding:"omitempty,oneof=en fr tw" default:"en"`
 }
 
 type NearbyJointsQuery struct {
@@ -34,3 +76,11 @@ type NearbyJointsQuery struct {
 	Longitude float64 `form:"longitude" binding:"required,longitude"`
 	Latitude  float64 `form:"latitude" binding:"required,latitude"`
 }
+
+type DeleteJointReq struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type RejectJointReq struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
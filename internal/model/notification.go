@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType identifies the kind of notification. Users can opt out of each type
+type NotificationType string
+
+const (
+	JointApprovedNotification          NotificationType = "joint_approved"
+	JointRejectedNotification          NotificationType = "joint_rejected"
+	JointVisibilityChangedNotification NotificationType = "joint_visibility_changed"
+	ComplaintStatusNotification        NotificationType = "complaint_status_changed"
+	ComplaintReplyNotification         NotificationType = "complaint_reply"
+	PendingComplaintsNotification      NotificationType = "pending_complaints"
+	SavedSearchMatchNotification       NotificationType = "saved_search_match"
+	VoteReviewNotification             NotificationType = "vote_review_opened"
+	BadgeAwardedNotification           NotificationType = "badge_awarded"
+)
+
+// NotificationTypes lists every notification type
+var NotificationTypes = []NotificationType{
+	JointApprovedNotification,
+	JointRejectedNotification,
+	JointVisibilityChangedNotification,
+	ComplaintStatusNotification,
+	ComplaintReplyNotification,
+	PendingComplaintsNotification,
+	SavedSearchMatchNotification,
+	VoteReviewNotification,
+	BadgeAwardedNotification,
+}
+
+// Notification is a message in a user's inbox. Notifications sharing a group key are aggregated into a single unread notification whose count is incremented
+type Notification struct {
+	ID         uuid.UUID        `json:"id"`
+	UserID     uuid.UUID        `json:"userId"`
+	Type       NotificationType `json:"type"`
+	Title      string           `json:"title"`
+	Body       string           `json:"body"`
+	TargetType *TargetType      `json:"targetType"`
+	TargetID   *uuid.UUID       `json:"targetId"`
+	GroupKey   *string          `json:"-"`
+	EventID    *uuid.UUID       `json:"-"`
+	Count      int              `json:"count"`
+	ReadAt     *time.Time       `json:"readAt"`
+	CreatedAt  time.Time        `json:"createdAt"`
+	UpdatedAt  time.Time        `json:"updatedAt"`
+}
+
+type NotificationPage struct {
+	Notifications []*Notification `json:"notifications"`
+	NextCursor    *string         `json:"nextCursor"`
+}
+
+type NotificationQuery struct {
+	PaginationQuery
+	UnreadOnly bool `form:"unreadOnly"`
+}
+
+type UnreadNotificationCount struct {
+	Unread int `json:"unread"`
+}
+
+type NotificationPreference struct {
+	Type    NotificationType `json:"type" binding:"required,oneof=joint_approved joint_rejected joint_visibility_changed complaint_status_changed complaint_reply pending_complaints saved_search_match vote_review_opened badge_awarded"`
+	Enabled bool             `json:"enabled"`
+}
+
+type UpdateNotificationPreferencesReq struct {
+	Preferences []NotificationPreference `json:"preferences" binding:"required,dive"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserIdentity links a user to their account at an OpenID Connect provider
+type UserIdentity struct {
+	ID       uuid.UUID `json:"id"`
+	UserID   uuid.UUID `json:"userId"`
+	Provider string    `json:"provider"`
+	// Subject is the ID of the user at the provider
+	Subject     string    `json:"-"`
+	Email       *string   `json:"email"`
+	CreatedAt   time.Time `json:"createdAt"`
+	LastLoginAt time.Time `json:"lastLoginAt"`
+}
+
+// OIDCState is a sign in started with a provider. It is used once to complete the sign in when the provider sends the user back
+type OIDCState struct {
+	StateHash    string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	// UserID is the user linking the provider to their account, or nil for a sign in
+	UserID    *uuid.UUID
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// OIDCProvider is a provider users can sign in with
+type OIDCProvider struct {
+	Name string `json:"name"`
+}
+
+type OIDCProviderParam struct {
+	Provider string `uri:"provider" binding:"required"`
+}
+
+type OIDCAuthorizationRes struct {
+	// AuthorizationURL is the page of the provider to send the user to
+	AuthorizationURL string `json:"authorizationUrl"`
+	// State is sent back with the code by the provider. Clients should check that it matches before completing the sign in
+	State string `json:"state"`
+}
+
+type OIDCCallbackReq struct {
+	State string `json:"state" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// RateLimitPolicy names a group of routes that share a rate limit. The limit of each policy is configured with RATE_LIMITS
+type RateLimitPolicy string
+
+const (
+	DefaultRateLimit    RateLimitPolicy = "default"
+	AuthRateLimit       RateLimitPolicy = "auth"
+	SubmissionRateLimit RateLimitPolicy = "submissions"
+	VoteRateLimit       RateLimitPolicy = "votes"
+	CheckinRateLimit    RateLimitPolicy = "checkins"
+	ComplaintRateLimit  RateLimitPolicy = "complaints"
+)
+
+// TokenBucket is the quota of a client under a rate limit policy. Tokens are refilled continuously and each request takes one
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// RateLimitResult describes the quota of a client after a request
+type RateLimitResult struct {
+	Allowed bool
+	Limit   int
+	Period  time.Duration
+	// Remaining is the number of requests the client can make right away
+	Remaining int
+	// Reset is how long until the full quota is available again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed when this one was not
+	RetryAfter time.Duration
+}
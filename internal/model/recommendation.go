@@ -0,0 +1,31 @@
+package model
+
+import "github.com/google/uuid"
+
+// RecommendationReason is why a joint was recommended to a user
+type RecommendationReason string
+
+const (
+	// the joint is similar to joints the user liked
+	SimilarJointRecommendation RecommendationReason = "similar_joint"
+	// the joint serves a category of food the user likes
+	CategoryRecommendation RecommendationReason = "category"
+	// the joint is popular near the user. used for users without enough history
+	PopularRecommendation RecommendationReason = "popular"
+)
+
+// RecommendedJoint is a joint recommended to a user along with why it was recommended
+type RecommendedJoint struct {
+	Joint
+	Reason RecommendationReason `json:"reason"`
+	// Explanation is a short human readable explanation of the recommendation
+	Explanation string `json:"explanation"`
+	// BecauseOf is the joint liked by the user that the recommended joint is most similar to
+	BecauseOfID   *uuid.UUID `json:"becauseOfId,omitempty"`
+	BecauseOfName *string    `json:"becauseOfName,omitempty"`
+	// scores the recommendation was ranked by
+	Similarity       float64 `json:"-"`
+	CategoryAffinity float64 `json:"-"`
+	// Relevance combines the scores of a personalised recommendation. It is zero for popular joints
+	Relevance float64 `json:"-"`
+}
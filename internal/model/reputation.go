@@ -0,0 +1,178 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReputationReason is why reputation points were awarded or deducted
+type ReputationReason string
+
+const (
+	JointApprovedReputation   ReputationReason = "joint_approved"
+	JointRejectedReputation   ReputationReason = "joint_rejected"
+	ComplaintUpheldReputation ReputationReason = "complaint_upheld"
+)
+
+// ReputationPoints are the points awarded or deducted for each reason
+var ReputationPoints = map[ReputationReason]int{
+	JointApprovedReputation:   10,
+	JointRejectedReputation:   -5,
+	ComplaintUpheldReputation: 5,
+}
+
+// ReputationEntry is an entry in a user's reputation ledger
+type ReputationEntry struct {
+	ID      uuid.UUID        `json:"id"`
+	UserID  uuid.UUID        `json:"userId"`
+	Reason  ReputationReason `json:"reason"`
+	Points  int              `json:"points"`
+	JointID *uuid.UUID       `json:"jointId"`
+	// SourceID is the record the points were awarded for, such as a joint or complaint. Points are awarded at most once per source and reason
+	SourceID  uuid.UUID `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TrustLevel is derived from a user's reputation and unlocks abilities as it increases
+type TrustLevel int
+
+const (
+	NewTrustLevel TrustLevel = iota
+	BasicTrustLevel
+	MemberTrustLevel
+	TrustedTrustLevel
+	LeaderTrustLevel
+)
+
+// TrustLevelThresholds is the reputation needed to reach each trust level
+var TrustLevelThresholds = []int{
+	NewTrustLevel:     0,
+	BasicTrustLevel:   10,
+	MemberTrustLevel:  50,
+	TrustedTrustLevel: 150,
+	LeaderTrustLevel:  400,
+}
+
+var trustLevelNames = []string{"new", "basic", "member", "trusted", "leader"}
+
+// TrustLevelFor returns the trust level of a user with the given reputation
+func TrustLevelFor(reputation int) TrustLevel {
+	level := NewTrustLevel
+	for l, threshold := range TrustLevelThresholds {
+		if reputation >= threshold {
+			level = TrustLevel(l)
+		}
+	}
+	return level
+}
+
+func (l TrustLevel) String() string {
+	return trustLevelNames[l]
+}
+
+// Ability is something a user may do once their trust level is high enough
+type Ability string
+
+const (
+	// edits to approved joints are applied without going back to the approval queue
+	AutoApproveEditsAbility Ability = "auto_approve_edits"
+	// votes are weighted higher in joint scores
+	WeightedVoteAbility Ability = "weighted_vote"
+	// the user can be promoted to moderator
+	ModeratorNominationAbility Ability = "moderator_nomination"
+)
+
+// AbilityTrustLevels is the trust level that unlocks each ability
+var AbilityTrustLevels = map[Ability]TrustLevel{
+	AutoApproveEditsAbility:    MemberTrustLevel,
+	WeightedVoteAbility:        TrustedTrustLevel,
+	ModeratorNominationAbility: LeaderTrustLevel,
+}
+
+// Can reports whether the trust level unlocks the ability
+func (l TrustLevel) Can(ability Ability) bool {
+	return l >= AbilityTrustLevels[ability]
+}
+
+// Abilities lists the abilities unlocked by the trust level
+func (l TrustLevel) Abilities() []Ability {
+	abilities := make([]Ability, 0, len(AbilityTrustLevels))
+	for _, ability := range []Ability{AutoApproveEditsAbility, WeightedVoteAbility, ModeratorNominationAbility} {
+		if l.Can(ability) {
+			abilities = append(abilities, ability)
+		}
+	}
+	return abilities
+}
+
+// Badge is an achievement shown on a user's public profile
+type Badge string
+
+const (
+	FirstJointBadge      Badge = "first_joint"
+	TenJointsBadge       Badge = "ten_joints"
+	FirstCheckinBadge    Badge = "first_checkin"
+	HundredCheckinsBadge Badge = "hundred_checkins"
+	TrustedBadge         Badge = "trusted"
+)
+
+// BadgeNames are the display names of badges
+var BadgeNames = map[Badge]string{
+	FirstJointBadge:      "First joint",
+	TenJointsBadge:       "10 joints",
+	FirstCheckinBadge:    "First check-in",
+	HundredCheckinsBadge: "100 check-ins",
+	TrustedBadge:         "Trusted contributor",
+}
+
+type UserBadge struct {
+	Badge     Badge     `json:"badge"`
+	Name      string    `json:"name"`
+	AwardedAt time.Time `json:"awardedAt"`
+}
+
+// UserProfile is the public profile of a user
+type UserProfile struct {
+	ID             uuid.UUID    `json:"id"`
+	Username       string       `json:"username"`
+	Role           UserRole     `json:"role"`
+	Reputation     int          `json:"reputation"`
+	TrustLevel     TrustLevel   `json:"trustLevel"`
+	TrustLevelName string       `json:"trustLevelName"`
+	Abilities      []Ability    `json:"abilities"`
+	Badges         []*UserBadge `json:"badges"`
+	ApprovedJoints int          `json:"approvedJoints"`
+	Checkins       int          `json:"checkins"`
+	JoinedAt       time.Time    `json:"joinedAt"`
+}
+
+// City is an area joints are grouped in for leaderboards
+type City struct {
+	Slug   string  `json:"slug"`
+	Name   string  `json:"name"`
+	Radius float64 `json:"radius"`
+	Coordinate
+}
+
+// LeaderboardEntry is the standing of a user on a leaderboard
+type LeaderboardEntry struct {
+	Rank       int        `json:"rank"`
+	UserID     uuid.UUID  `json:"userId"`
+	Username   string     `json:"username"`
+	Points     int        `json:"points"`
+	TrustLevel TrustLevel `json:"trustLevel"`
+}
+
+type LeaderboardQuery struct {
+	City string `form:"city" binding:"omitempty,max=100"`
+}
+
+// VoteWeights are the weights applied to votes when computing joint scores. Weights of a vote are multiplied
+type VoteWeights struct {
+	// Verified applies to votes of users who have checked in at the joint
+	Verified float64
+	// Trusted applies to votes of users with at least TrustedReputation
+	Trusted           float64
+	TrustedReputation int
+}
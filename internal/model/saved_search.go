@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearchDelivery is how a user is alerted about new joints matching a saved search
+type SavedSearchDelivery string
+
+const (
+	NotificationDelivery SavedSearchDelivery = "notification"
+	EmailDigestDelivery  SavedSearchDelivery = "email_digest"
+)
+
+// SavedSearch describes the joints a user wants to be alerted about. The area is either a circle around a point or a polygon.
+// Empty categories or keywords match every joint
+type SavedSearch struct {
+	ID         uuid.UUID           `json:"id"`
+	UserID     uuid.UUID           `json:"userId"`
+	Name       string              `json:"name"`
+	Center     *Coordinate         `json:"center,omitempty"`
+	Radius     *float64            `json:"radius,omitempty"`
+	Polygon    []Coordinate        `json:"polygon,omitempty"`
+	Categories []JointCategory     `json:"categories"`
+	Keywords   []string            `json:"keywords"`
+	Delivery   SavedSearchDelivery `json:"delivery"`
+	CreatedAt  time.Time           `json:"createdAt"`
+	UpdatedAt  time.Time           `json:"updatedAt"`
+}
+
+// SavedSearchMatch is a joint matched by a saved search when it was approved
+type SavedSearchMatch struct {
+	SavedSearchID uuid.UUID  `json:"savedSearchId"`
+	Joint         *Joint     `json:"joint"`
+	MatchedAt     time.Time  `json:"matchedAt"`
+	NotifiedAt    *time.Time `json:"notifiedAt"`
+}
+
+// DigestMatch is a match waiting to be sent in a user's email digest
+type DigestMatch struct {
+	UserID          uuid.UUID
+	Email           string
+	Username        string
+	SavedSearchID   uuid.UUID
+	SavedSearchName string
+	JointID         uuid.UUID
+	JointName       string
+	MatchedAt       time.Time
+}
+
+type PolygonPoint struct {
+	Latitude  float64 `json:"latitude" binding:"required,latitude"`
+	Longitude float64 `json:"longitude" binding:"required,longitude"`
+}
+
+type CreateSavedSearchReq struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude Radius,omitempty,latitude"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude Radius,omitempty,longitude"`
+	Radius    *float64 `json:"radius" binding:"required_without=Polygon,excluded_with=Polygon,omitempty,gt=0"`
+	// Polygon is a list of points outlining the area. The ring is closed automatically
+	Polygon    []PolygonPoint `json:"polygon" binding:"omitempty,min=3,max=100,dive"`
+	Categories []string       `json:"categories" binding:"omitempty,max=10,dive,oneof=chop_bar street_food fast_food grill bakery cafe restaurant drinks other"`
+	Keywords   []string       `json:"keywords" binding:"omitempty,max=10,dive,min=2,max=50"`
+	Delivery   string         `json:"delivery" binding:"omitempty,oneof=notification email_digest"`
+}
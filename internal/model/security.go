@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecurityEventType identifies an event on the security of a user's account
+type SecurityEventType string
+
+const (
+	LoginSucceeded  SecurityEventType = "login_succeeded"
+	LoginFailed     SecurityEventType = "login_failed"
+	AccountLocked   SecurityEventType = "account_locked"
+	AccountUnlocked SecurityEventType = "account_unlocked"
+	// two-factor authentication
+	TwoFactorEnabled         SecurityEventType = "two_factor_enabled"
+	TwoFactorDisabled        SecurityEventType = "two_factor_disabled"
+	RecoveryCodeUsed         SecurityEventType = "recovery_code_used"
+	RecoveryCodesRegenerated SecurityEventType = "recovery_codes_regenerated"
+	// openid connect identities
+	IdentityLinked   SecurityEventType = "identity_linked"
+	IdentityUnlinked SecurityEventType = "identity_unlinked"
+	// personal api keys
+	APIKeyCreated SecurityEventType = "api_key_created"
+	APIKeyRevoked SecurityEventType = "api_key_revoked"
+	// personal data
+	DataExportRequested      SecurityEventType = "data_export_requested"
+	AccountDeletionScheduled SecurityEventType = "account_deletion_scheduled"
+	AccountDeletionCancelled SecurityEventType = "account_deletion_cancelled"
+)
+
+// SecurityEvent is a record of a login or change to the security of an account, shown to its owner so that they can spot activity that was not theirs
+type SecurityEvent struct {
+	ID        uuid.UUID         `json:"id"`
+	UserID    *uuid.UUID        `json:"userId"`
+	Type      SecurityEventType `json:"type"`
+	IPAddress *string           `json:"ipAddress"`
+	UserAgent *string           `json:"userAgent"`
+	DeviceID  *string           `json:"deviceId"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// LoginAttempt tracks the failed logins to an email since its last successful login. Unknown emails are tracked the same way as registered ones
+type LoginAttempt struct {
+	Email          string
+	FailedAttempts int
+	LastFailedAt   time.Time
+	LockedUntil    *time.Time
+}
+
+type UnlockAccountReq struct {
+	Token string `json:"token" binding:"required"`
+}
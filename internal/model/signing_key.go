@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// SigningKey is a key pair tokens are signed with. PrivateKey is encrypted and keys stop signing once they are retired
+type SigningKey struct {
+	KID        string     `json:"kid"`
+	Algorithm  string     `json:"algorithm"`
+	PrivateKey string     `json:"-"`
+	PublicKey  string     `json:"-"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	RetiredAt  *time.Time `json:"retiredAt"`
+}
+
+// JWK is the public part of a signing key as a JSON Web Key. Ed25519 keys set crv and x, elliptic curve keys set crv, x and y and RSA keys set n and e
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS is the set of keys that verify the tokens in use
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StreamEventType identifies a real-time update sent to stream clients
+type StreamEventType string
+
+const (
+	VoteChangedStreamEvent        StreamEventType = "vote.changed"
+	JointApprovedStreamEvent      StreamEventType = "joint.approved"
+	JointStatusChangedStreamEvent StreamEventType = "joint.status_changed"
+)
+
+// JointStatus is the public state of a joint reported in status change updates
+type JointStatus string
+
+const (
+	VisibleJointStatus     JointStatus = "visible"
+	UnderReviewJointStatus JointStatus = "under_review"
+	DeletedJointStatus     JointStatus = "deleted"
+)
+
+// StreamMessage is a real-time update about a joint. The joint location is included so that clients subscribed to an area can be matched
+type StreamMessage struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      StreamEventType `json:"type"`
+	JointID   uuid.UUID       `json:"jointId"`
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// VoteChangedData is the data of vote changed updates
+type VoteChangedData struct {
+	UpVotes   int `json:"upvotes"`
+	DownVotes int `json:"downvotes"`
+}
+
+// JointStatusChangedData is the data of joint status changed updates
+type JointStatusChangedData struct {
+	Status JointStatus `json:"status"`
+}
+
+// StreamFilter selects the updates delivered to a stream client. Updates match when they are about one of the joints or located within the area
+type StreamFilter struct {
+	JointIDs []uuid.UUID
+	Area     *StreamArea
+}
+
+// StreamArea is a circle around a point with the radius in meters
+type StreamArea struct {
+	Center Coordinate
+	Radius float64
+}
+
+type StreamQuery struct {
+	JointIDs  []string `form:"jointId" binding:"omitempty,max=50,dive,uuid"`
+	Latitude  *float64 `form:"latitude" binding:"required_with=Longitude Radius,omitempty,latitude"`
+	Longitude *float64 `form:"longitude" binding:"required_with=Latitude Radius,omitempty,longitude"`
+	Radius    *float64 `form:"radius" binding:"required_with=Latitude Longitude,omitempty,gt=0"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JointTranslation is the name and description of a joint in a locale other than its default locale
+type JointTranslation struct {
+	JointID     uuid.UUID  `json:"jointId"`
+	Locale      string     `json:"locale"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	UpdatedBy   *uuid.UUID `json:"updatedBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// JointLocalization is the default locale of a joint along with its translations
+type JointLocalization struct {
+	DefaultLocale string
+	Translations  []JointTranslation
+}
+
+type TranslationParam struct {
+	ID     string `uri:"id" binding:"required,uuid"`
+	Locale string `uri:"locale" binding:"required,oneof=en fr tw"`
+}
+
+// GetID returns a uuid representation of the joint ID param string
+func (p *TranslationParam) GetID() uuid.UUID {
+	id, _ := uuid.Parse(p.ID)
+	return id
+}
+
+type SaveJointTranslationReq struct {
+	Name        string  `json:"name" binding:"required,gte=3,max=255"`
+	Description *string `json:"description" binding:"omitempty,max=5000"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// TrendingWindow is the period over which the recent activity of trending joints is measured
+type TrendingWindow string
+
+const (
+	DayTrendingWindow   TrendingWindow = "24h"
+	WeekTrendingWindow  TrendingWindow = "7d"
+	MonthTrendingWindow TrendingWindow = "30d"
+)
+
+// Duration returns the length of the window. Unknown windows default to a week
+func (w TrendingWindow) Duration() time.Duration {
+	switch w {
+	case DayTrendingWindow:
+		return 24 * time.Hour
+	case MonthTrendingWindow:
+		return 30 * 24 * time.Hour
+	default:
+		return 7 * 24 * time.Hour
+	}
+}
+
+// JointActivity is the activity of a joint within an hourly bucket
+type JointActivity struct {
+	UpVotes   int `json:"upvotes"`
+	DownVotes int `json:"downvotes"`
+	Checkins  int `json:"checkins"`
+	Views     int `json:"views"`
+}
+
+// TrendingJoint is a joint ranked by how much its recent activity exceeds its usual activity
+type TrendingJoint struct {
+	Joint
+	// Activity is what happened at the joint within the window
+	Activity JointActivity `json:"activity"`
+	// Velocity is how far the activity within the window is above the joint's baseline
+	Velocity float64 `json:"velocity"`
+}
+
+type TrendingJointsQuery struct {
+	NearbyJointsQuery
+	Window string `form:"window" binding:"omitempty,oneof=24h 7d 30d" enums:"24h,7d,30d" default:"7d"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor is the TOTP two-factor authentication of a user. Secret is encrypted and only in use once the user has enabled it
+type TwoFactor struct {
+	UserID       uuid.UUID
+	Secret       string
+	EnabledAt    *time.Time
+	LastUsedStep *int64
+	CreatedAt    time.Time
+}
+
+type TwoFactorStatus struct {
+	Enabled   bool       `json:"enabled"`
+	EnabledAt *time.Time `json:"enabledAt"`
+	// RecoveryCodesLeft is the number of unused recovery codes
+	RecoveryCodesLeft int `json:"recoveryCodesLeft"`
+}
+
+type TwoFactorSetupRes struct {
+	// Secret is the base32 key for authenticator apps that cannot scan the provisioning URI
+	Secret string `json:"secret"`
+	// ProvisioningURI is the otpauth URI shown as a QR code for authenticator apps to scan
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type RecoveryCodesRes struct {
+	// RecoveryCodes are shown once. Each can be used instead of a code from the authenticator app a single time
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type TwoFactorCodeReq struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type VerifyTwoFactorReq struct {
+	PreAuthToken string `json:"preAuthToken" binding:"required"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode,excluded_with=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recoveryCode" binding:"required_without=Code,omitempty,max=20"`
+}
+
+// ReauthenticateReq confirms the identity of a signed in user before a sensitive change with their password and a code from their authenticator app or a recovery code.
+// Users without a password leave it out
+type ReauthenticateReq struct {
+	Password     string `json:"password"`
+	Code         string `json:"code" binding:"required_without=RecoveryCode,excluded_with=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recoveryCode" binding:"required_without=Code,omitempty,max=20"`
+}
@@ -15,14 +15,19 @@ const (
 	AppUser   UserRole = "user"
 )
 
+// User is an account. Users without a password sign in with an identity linked from an OpenID Connect provider
 type User struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email"`
-	Username  string    `json:"username"`
-	Password  string    `json:"-"`
-	Role      UserRole  `json:"role"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID              uuid.UUID  `json:"id"`
+	Email           string     `json:"email"`
+	Username        string     `json:"username"`
+	Password        string     `json:"-"`
+	HasPassword     bool       `json:"hasPassword"`
+	Role            UserRole   `json:"role"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
+	// DeletionScheduledAt is when the account will be anonymised after its owner asked for it to be deleted
+	DeletionScheduledAt *time.Time `json:"deletionScheduledAt"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
 }
 
 type RegisterUserReq struct {
@@ -36,9 +41,12 @@ type LoginUserReq struct {
 	Password string `json:"password" binding:"required,gte=8"`
 }
 
+// LoginUserRes holds the access token of a signed in user. Users with two-factor authentication get a pre-auth token instead, to be exchanged for the access token along with a code
 type LoginUserRes struct {
-	User        User   `json:"user"`
-	AccessToken string `json:"accessToken"`
+	User              *User  `json:"user,omitempty"`
+	AccessToken       string `json:"accessToken,omitempty"`
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	PreAuthToken      string `json:"preAuthToken,omitempty"`
 }
 
 // AuthenticatedUser is the minimal user info passed throughout the application for an authenticated user
@@ -46,4 +54,8 @@ type AuthenticatedUser struct {
 	ID       uuid.UUID
 	Username string
 	Role     UserRole
+	// TwoFactor is set when the session completed two-factor authentication
+	TwoFactor bool
+	// APIKey is set when the request was authenticated with a personal API key rather than a session
+	APIKey *APIKey
 }
@@ -18,8 +18,15 @@ type Vote struct {
 	UserID    uuid.UUID     `json:"userId"`
 	JointID   uuid.UUID     `json:"jointId"`
 	Direction VoteDirection `json:"direction"`
-	CreatedAt time.Time     `json:"createdAt"`
-	UpdatedAt time.Time     `json:"updatedAt"`
+	// Verified is set when the voter had checked in at the joint
+	Verified  bool      `json:"verified"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// origin of the vote used to detect clusters of votes from the same source
+	IPAddress   *string `json:"-"`
+	Fingerprint *string `json:"-"`
+	// Voided is set when moderators voided the vote after an integrity review. Voided votes can no longer be changed by their voter
+	Voided bool `json:"-"`
 }
 
 type JointVoter struct {
@@ -27,10 +34,28 @@ type JointVoter struct {
 	Username  string        `json:"username"`
 	JointID   uuid.UUID     `json:"jointId"`
 	Direction VoteDirection `json:"direction"`
+	Verified  bool          `json:"verified"`
 	CreatedAt time.Time     `json:"createdAt"`
 	UpdatedAt time.Time     `json:"updatedAt"`
 }
 
+// UserVote is a vote in a user's vote history along with the joint voted on
+type UserVote struct {
+	Vote
+	JointName string `json:"jointName"`
+	// Voided is set when moderators voided the vote after an integrity review, so it no longer counts towards the joint
+	Voided bool `json:"voided"`
+}
+
 type VoteJointReq struct {
 	Direction VoteDirection `json:"direction" binding:"required,oneof=up down"`
 }
+
+// VoteCountDiscrepancy is a joint whose stored vote counts differ from the votes recorded for it
+type VoteCountDiscrepancy struct {
+	JointID         uuid.UUID `json:"jointId"`
+	UpVotes         int       `json:"upvotes"`
+	DownVotes       int       `json:"downvotes"`
+	ActualUpVotes   int       `json:"actualUpvotes"`
+	ActualDownVotes int       `json:"actualDownvotes"`
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VoteRiskSignal is a reason for a vote being considered suspicious
+type VoteRiskSignal string
+
+const (
+	NewAccountSignal   VoteRiskSignal = "new_account"
+	NoCheckinSignal    VoteRiskSignal = "no_checkin"
+	SharedIPSignal     VoteRiskSignal = "shared_ip"
+	SharedDeviceSignal VoteRiskSignal = "shared_device"
+	VoteBurstSignal    VoteRiskSignal = "burst"
+)
+
+// VoteReviewReason is the kind of cluster a vote review was opened for
+type VoteReviewReason string
+
+const (
+	IPClusterReview     VoteReviewReason = "ip_cluster"
+	DeviceClusterReview VoteReviewReason = "device_cluster"
+	VoteBurstReview     VoteReviewReason = "burst"
+)
+
+// VoteReviewStatus is the state of a vote review in the moderation queue
+type VoteReviewStatus string
+
+const (
+	OpenVoteReview      VoteReviewStatus = "open"
+	VoidedVoteReview    VoteReviewStatus = "voided"
+	DismissedVoteReview VoteReviewStatus = "dismissed"
+)
+
+// VoteSignals are the details of a vote and its voter used to assess whether it is genuine
+type VoteSignals struct {
+	VoteID         uuid.UUID
+	JointID        uuid.UUID
+	UserID         uuid.UUID
+	Direction      VoteDirection
+	IPAddress      *string
+	Fingerprint    *string
+	Verified       bool
+	AccountCreated time.Time
+	// number of other users who voted the same way on the joint within the cluster window from the same IP address or device
+	SharedIPVoters     int
+	SharedDeviceVoters int
+	// number of votes in the same direction on the joint within the burst window
+	BurstVotes int
+}
+
+// VoteReview is a cluster of suspicious votes on a joint queued for moderation
+type VoteReview struct {
+	ID         uuid.UUID        `json:"id"`
+	JointID    uuid.UUID        `json:"jointId"`
+	JointName  string           `json:"jointName"`
+	Reason     VoteReviewReason `json:"reason"`
+	ClusterKey string           `json:"clusterKey"`
+	Direction  VoteDirection    `json:"direction"`
+	Status     VoteReviewStatus `json:"status"`
+	VoteCount  int              `json:"voteCount"`
+	// RiskScore is the average risk score of the votes in the review
+	RiskScore  float64    `json:"riskScore"`
+	ResolvedBy *uuid.UUID `json:"resolvedBy"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// ReviewedVote is a vote in a vote review along with the signals that made it suspicious
+type ReviewedVote struct {
+	VoteID           uuid.UUID        `json:"voteId"`
+	UserID           uuid.UUID        `json:"userId"`
+	Username         string           `json:"username"`
+	AccountCreatedAt time.Time        `json:"accountCreatedAt"`
+	Direction        VoteDirection    `json:"direction"`
+	Verified         bool             `json:"verified"`
+	IPAddress        *string          `json:"ipAddress"`
+	Fingerprint      *string          `json:"fingerprint"`
+	RiskScore        *float64         `json:"riskScore"`
+	RiskSignals      []VoteRiskSignal `json:"riskSignals"`
+	VoidedAt         *time.Time       `json:"voidedAt"`
+	CreatedAt        time.Time        `json:"createdAt"`
+}
+
+// VotesVoidedPayload is the payload of votes voided events
+type VotesVoidedPayload struct {
+	ReviewID  uuid.UUID   `json:"reviewId"`
+	VoteIDs   []uuid.UUID `json:"voteIds"`
+	UpVotes   int         `json:"upvotes"`
+	DownVotes int         `json:"downvotes"`
+}
+
+type VoteReviewQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=open voided dismissed"`
+}
+
+type VoidVotesReq struct {
+	// VoteIDs limits the votes voided to a subset of the review. Every vote in the review is voided when empty
+	VoteIDs []string `json:"voteIds" binding:"omitempty,max=1000,dive,uuid"`
+}
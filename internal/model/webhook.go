@@ -0,0 +1,114 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventTypes are the event types partners can subscribe to. Events carrying personal data, such as complaints and votes, are not exposed
+var WebhookEventTypes = []EventType{
+	JointCreatedEvent,
+	JointUpdatedEvent,
+	JointApprovedEvent,
+	JointRejectedEvent,
+	JointDeletedEvent,
+	JointRestoredEvent,
+	JointVisibilityChangedEvent,
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	PendingDelivery   WebhookDeliveryStatus = "pending"
+	SucceededDelivery WebhookDeliveryStatus = "succeeded"
+	FailedDelivery    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook is an endpoint that receives signed event payloads. The secret is only returned when the webhook is created
+type Webhook struct {
+	ID                  uuid.UUID   `json:"id"`
+	URL                 string      `json:"url"`
+	Secret              string      `json:"-"`
+	EventTypes          []EventType `json:"eventTypes"`
+	IsActive            bool        `json:"isActive"`
+	ConsecutiveFailures int         `json:"consecutiveFailures"`
+	DisabledAt          *time.Time  `json:"disabledAt"`
+	CreatedBy           uuid.UUID   `json:"createdBy"`
+	CreatedAt           time.Time   `json:"createdAt"`
+	UpdatedAt           time.Time   `json:"updatedAt"`
+}
+
+// CreatedWebhook is returned once when a webhook is created so that the secret can be stored by the partner
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery is an attempt to send an event to a webhook along with the last response received
+type WebhookDelivery struct {
+	ID            uuid.UUID             `json:"id"`
+	WebhookID     uuid.UUID             `json:"webhookId"`
+	EventID       uuid.UUID             `json:"eventId"`
+	EventType     EventType             `json:"eventType"`
+	Payload       json.RawMessage       `json:"payload" swaggertype:"object"`
+	Status        WebhookDeliveryStatus `json:"status"`
+	Attempts      int                   `json:"attempts"`
+	ResponseCode  *int                  `json:"responseCode"`
+	ResponseBody  *string               `json:"responseBody"`
+	LastError     *string               `json:"lastError"`
+	NextAttemptAt time.Time             `json:"nextAttemptAt"`
+	DeliveredAt   *time.Time            `json:"deliveredAt"`
+	CreatedAt     time.Time             `json:"createdAt"`
+	UpdatedAt     time.Time             `json:"updatedAt"`
+}
+
+// WebhookDispatch is a claimed delivery along with the endpoint it must be sent to
+type WebhookDispatch struct {
+	Delivery *WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+// WebhookPayload is the body posted to webhook endpoints
+type WebhookPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      EventType       `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+type CreateWebhookReq struct {
+	URL string `json:"url" binding:"required,url,max=2000"`
+	// Secret is generated when not provided
+	Secret     *string  `json:"secret" binding:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"eventTypes" binding:"required,min=1,dive,oneof=joint.created joint.updated joint.approved joint.rejected joint.deleted joint.restored joint.visibility_changed"`
+}
+
+type UpdateWebhookReq struct {
+	URL        *string  `json:"url" binding:"omitempty,url,max=2000"`
+	Secret     *string  `json:"secret" binding:"omitempty,min=16,max=255"`
+	EventTypes []string `json:"eventTypes" binding:"omitempty,min=1,dive,oneof=joint.created joint.updated joint.approved joint.rejected joint.deleted joint.restored joint.visibility_changed"`
+	// IsActive re-enables a webhook that was disabled after repeated failures
+	IsActive *bool `json:"isActive"`
+}
+
+// params for routes on a single delivery of a webhook
+type WebhookDeliveryParam struct {
+	ID         string `uri:"id" binding:"required,uuid"`
+	DeliveryID string `uri:"deliveryId" binding:"required,uuid"`
+}
+
+// GetID returns a uuid representation of the webhook ID param string
+func (p *WebhookDeliveryParam) GetID() uuid.UUID {
+	id, _ := uuid.Parse(p.ID)
+	return id
+}
+
+// GetDeliveryID returns a uuid representation of the delivery ID param string
+func (p *WebhookDeliveryParam) GetDeliveryID() uuid.UUID {
+	id, _ := uuid.Parse(p.DeliveryID)
+	return id
+}
@@ -0,0 +1,428 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// AccountRepository handles database operations for the personal data of users: exporting it and anonymising deleted accounts
+type AccountRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *AccountRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// ScheduleDeletion schedules an account to be anonymised at the given time and returns when it will be. An account already scheduled keeps its time
+func (r *AccountRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) (time.Time, error) {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = COALESCE(deletion_scheduled_at, $2), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING deletion_scheduled_at
+		`
+	var scheduledAt time.Time
+	if err := r.db.QueryRowContext(ctx, query, userID, at).Scan(&scheduledAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, ErrNotFound
+		}
+		return time.Time{}, err
+	}
+	return scheduledAt, nil
+}
+
+// CancelDeletion keeps an account scheduled for deletion. ErrNotFound is returned when the account is not scheduled for deletion
+func (r *AccountRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL
+		`
+	return expectAffected(r.db.ExecContext(ctx, query, userID))
+}
+
+// GetDueDeletions returns the accounts whose deletion was scheduled before the given time, oldest first
+func (r *AccountRepository) GetDueDeletions(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at
+		LIMIT $2
+		`
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// LockDueDeletion locks an account whose deletion is due and returns its email. ErrNotFound is returned when the deletion was cancelled or has already run
+func (r *AccountRepository) LockDueDeletion(ctx context.Context, tx *sql.Tx, userID uuid.UUID) (string, error) {
+	query := `
+		SELECT email
+		FROM users
+		WHERE id = $1 AND deletion_scheduled_at <= NOW() AND deleted_at IS NULL
+		FOR UPDATE
+		`
+	var email string
+	if err := tx.QueryRowContext(ctx, query, userID).Scan(&email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return email, nil
+}
+
+// Anonymise removes the personal data of an account while keeping its contributions.
+// The user row stays as an anonymous placeholder so that joints.creator_id and joints.deleted_by, votes.user_id and votes.voided_by, complaints.user_id,
+// complaint_replies.user_id, vote_reviews.resolved_by, joint_translations.updated_by and webhooks.created_by keep pointing at it and vote totals stay intact.
+// Its reputation is reset, so the caller should recompute the scores of the joints it voted on in the same transaction.
+// Data only meaningful to the user is deleted, including the login attempts kept under their login key. Audit events are append-only and are kept as they are
+func (r *AccountRepository) Anonymise(ctx context.Context, tx *sql.Tx, userID uuid.UUID, loginKey string) error {
+	statements := []string{
+		// the network and device of a vote identify the voter
+		`UPDATE votes SET ip_address = NULL, fingerprint = NULL WHERE user_id = $1`,
+		// visits locate the user
+		`DELETE FROM checkins WHERE user_id = $1`,
+		// reputation is personal standing and would keep the account on leaderboards
+		`DELETE FROM reputation_events WHERE user_id = $1`,
+		`DELETE FROM user_badges WHERE user_id = $1`,
+		`DELETE FROM notifications WHERE user_id = $1`,
+		`DELETE FROM notification_events WHERE user_id = $1`,
+		`DELETE FROM notification_preferences WHERE user_id = $1`,
+		`DELETE FROM saved_searches WHERE user_id = $1`,
+		// these cascade when a user is deleted but the row is kept
+		`DELETE FROM security_events WHERE user_id = $1`,
+		`DELETE FROM two_factor_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_two_factor WHERE user_id = $1`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+		`DELETE FROM api_keys WHERE user_id = $1`,
+		`DELETE FROM data_exports WHERE user_id = $1`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement, userID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM login_attempts WHERE email = $1`, loginKey); err != nil {
+		return err
+	}
+
+	// the placeholder keeps unique values derived from the ID. the invalid domain can never receive email
+	query := `
+		UPDATE users
+		SET email = 'deleted-' || id || '@deleted.invalid', username = 'deleted-' || id, password = NULL, role = $2,
+			email_verified_at = NULL, reputation = 0, deletion_scheduled_at = NULL, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+		`
+	return expectAffected(tx.ExecContext(ctx, query, userID, model.AppUser))
+}
+
+const dataExportColumns = `id, user_id, status, size_bytes, attempts, last_error, completed_at, expires_at, created_at, updated_at`
+
+// CreateExport queues an export of the personal data of a user. ErrAlreadyExist is returned when one is already being built
+func (r *AccountRepository) CreateExport(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	query := `INSERT INTO data_exports(user_id) VALUES ($1) RETURNING ` + dataExportColumns
+	export, err := scanDataExport(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// GetExport returns an export of a user
+func (r *AccountRepository) GetExport(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE id = $1 AND user_id = $2`
+	return scanDataExport(r.db.QueryRowContext(ctx, query, id, userID))
+}
+
+// GetExports returns the exports of a user, newest first
+func (r *AccountRepository) GetExports(ctx context.Context, userID uuid.UUID) ([]*model.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := []*model.DataExport{}
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// GetArchive returns the archive of a completed export of a user that has not expired
+func (r *AccountRepository) GetArchive(ctx context.Context, userID, id uuid.UUID) (*model.DataExportArchive, error) {
+	query := `
+		SELECT archive, created_at
+		FROM data_exports
+		WHERE id = $1 AND user_id = $2 AND status = 'completed' AND expires_at > NOW()
+		`
+	var archive model.DataExportArchive
+	if err := r.db.QueryRowContext(ctx, query, id, userID).Scan(&archive.Archive, &archive.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// ClaimDueExports returns pending exports that are due, oldest first. Claimed exports are leased by pushing their next attempt past the lease so that other instances skip them while they are being built
+func (r *AccountRepository) ClaimDueExports(ctx context.Context, lease time.Duration, limit int) ([]*model.DataExport, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM data_exports
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE data_exports e
+		SET next_attempt_at = NOW() + make_interval(secs => $2), attempts = e.attempts + 1, updated_at = NOW()
+		FROM due
+		WHERE e.id = due.id
+		RETURNING e.id, e.user_id, e.status, e.size_bytes, e.attempts, e.last_error, e.completed_at, e.expires_at, e.created_at, e.updated_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exports := make([]*model.DataExport, 0, limit)
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// CompleteExport stores the archive of an export, which can be downloaded until it expires
+func (r *AccountRepository) CompleteExport(ctx context.Context, id uuid.UUID, archive []byte, expiresAt time.Time) (*model.DataExport, error) {
+	query := `
+		UPDATE data_exports
+		SET status = 'completed', archive = $2, size_bytes = $3, last_error = NULL, completed_at = NOW(), expires_at = $4, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING ` + dataExportColumns
+	return scanDataExport(r.db.QueryRowContext(ctx, query, id, archive, len(archive), expiresAt))
+}
+
+// FailExport records a failed attempt at building an export. It is retried at the given time, or marked as failed when there is none
+func (r *AccountRepository) FailExport(ctx context.Context, id uuid.UUID, lastError string, retryAt *time.Time) error {
+	query := `
+		UPDATE data_exports
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE status END,
+			next_attempt_at = COALESCE($3, next_attempt_at), last_error = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		`
+	return expectAffected(r.db.ExecContext(ctx, query, id, lastError, retryAt))
+}
+
+// DeleteExpiredExports removes the exports that expired before the given time along with failed exports created before it. It returns the number of exports removed
+func (r *AccountRepository) DeleteExpiredExports(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM data_exports WHERE expires_at < $1 OR (status = 'failed' AND created_at < $1)`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// personalDataSections are the queries gathering each kind of personal data of a user into a JSON value. Every table referencing users is covered
+var personalDataSections = []struct {
+	name  string
+	query string
+}{
+	{"profile", `
+		SELECT to_jsonb(t) FROM (
+			SELECT id, email, username, role, reputation, email_verified_at, deletion_scheduled_at, created_at, updated_at
+			FROM users WHERE id = $1
+		) t`},
+	{"joints", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, name, description, category, locale, latitude, longitude, photo_url, is_approved, visibility,
+				upvotes, downvotes, created_at, updated_at, deleted_at, deletion_reason
+			FROM joints WHERE creator_id = $1
+		) t`},
+	// joint photos are links to where the user uploaded them rather than files held by chow
+	{"photos", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id AS joint_id, name AS joint_name, photo_url, created_at
+			FROM joints WHERE creator_id = $1 AND photo_url IS NOT NULL
+		) t`},
+	{"votes", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, joint_id, direction, verified, ip_address, voided_at, created_at, updated_at
+			FROM votes WHERE user_id = $1
+		) t`},
+	{"complaints", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, joint_id, category, reason, status, created_at, updated_at
+			FROM complaints WHERE user_id = $1
+		) t`},
+	{"complaint_replies", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, complaint_id, body, created_at
+			FROM complaint_replies WHERE user_id = $1
+		) t`},
+	{"vote_reviews", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.resolved_at), '[]') FROM (
+			SELECT id, joint_id, reason, direction, status, resolved_at
+			FROM vote_reviews WHERE resolved_by = $1
+		) t`},
+	{"joint_translations", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.updated_at), '[]') FROM (
+			SELECT joint_id, locale, name, description, updated_at
+			FROM joint_translations WHERE updated_by = $1
+		) t`},
+	{"checkins", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, joint_id, ST_Y(location::geometry) AS latitude, ST_X(location::geometry) AS longitude, distance, created_at
+			FROM checkins WHERE user_id = $1
+		) t`},
+	{"reputation", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, reason, points, joint_id, created_at
+			FROM reputation_events WHERE user_id = $1
+		) t`},
+	{"badges", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.awarded_at), '[]') FROM (
+			SELECT badge, awarded_at
+			FROM user_badges WHERE user_id = $1
+		) t`},
+	{"saved_searches", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, name, ST_Y(center::geometry) AS latitude, ST_X(center::geometry) AS longitude, radius,
+				ST_AsGeoJSON(area)::jsonb AS area, categories, keywords, delivery, created_at, updated_at
+			FROM saved_searches WHERE user_id = $1
+		) t`},
+	{"notifications", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, type, title, body, target_type, target_id, count, read_at, created_at
+			FROM notifications WHERE user_id = $1
+		) t`},
+	{"notification_preferences", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.type), '[]') FROM (
+			SELECT type, enabled, updated_at
+			FROM notification_preferences WHERE user_id = $1
+		) t`},
+	{"security_events", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT type, ip_address, user_agent, device_id, created_at
+			FROM security_events WHERE user_id = $1
+		) t`},
+	{"identities", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT provider, email, created_at, last_login_at
+			FROM user_identities WHERE user_id = $1
+		) t`},
+	{"two_factor", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]') FROM (
+			SELECT enabled_at, created_at
+			FROM user_two_factor WHERE user_id = $1
+		) t`},
+	{"api_keys", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT name, prefix, scopes, expires_at, last_used_at, last_used_ip, revoked_at, created_at
+			FROM api_keys WHERE user_id = $1
+		) t`},
+	{"webhooks", `
+		SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]') FROM (
+			SELECT id, url, event_types, is_active, created_at
+			FROM webhooks WHERE created_by = $1
+		) t`},
+}
+
+// GetPersonalData gathers the personal data of a user into one JSON section per kind of data. The sections are read in a single snapshot so that they are consistent with each other
+func (r *AccountRepository) GetPersonalData(ctx context.Context, userID uuid.UUID) ([]model.PersonalDataSection, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	sections := make([]model.PersonalDataSection, 0, len(personalDataSections))
+	for _, section := range personalDataSections {
+		var data []byte
+		if err := tx.QueryRowContext(ctx, section.query, userID).Scan(&data); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+		sections = append(sections, model.PersonalDataSection{Name: section.name, Data: data})
+	}
+	return sections, nil
+}
+
+func scanDataExport(row scanner) (*model.DataExport, error) {
+	var export model.DataExport
+	if err := row.Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.SizeBytes,
+		&export.Attempts,
+		&export.LastError,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+		&export.CreatedAt,
+		&export.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &export, nil
+}
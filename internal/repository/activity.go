@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// ActivityRepository handles database operations for the hourly activity of joints
+type ActivityRepository struct {
+	db *sql.DB
+}
+
+// NewActivityRepository creates a new activity repository
+func NewActivityRepository(db *sql.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// activityScore weighs the activity of a bucket into a single number. Check-ins count the most as they are verified visits while views count the least. The activity table should be aliased as a
+const activityScore = `(a.upvotes - a.downvotes + 2 * a.checkins + 0.1 * a.views)`
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *ActivityRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// MarkEventProcessed records that an event has been aggregated. It reports false when the event had already been processed
+func (r *ActivityRepository) MarkEventProcessed(ctx context.Context, tx *sql.Tx, eventID uuid.UUID) (bool, error) {
+	result, err := tx.ExecContext(ctx, `INSERT INTO joint_activity_events(event_id) VALUES ($1) ON CONFLICT DO NOTHING`, eventID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// AddActivity adds to the activity of a joint in the hourly bucket the given time falls in
+func (r *ActivityRepository) AddActivity(ctx context.Context, tx *sql.Tx, jointID uuid.UUID, at time.Time, activity model.JointActivity) error {
+	query := `
+		INSERT INTO joint_activity(joint_id, bucket, upvotes, downvotes, checkins, views)
+		VALUES ($1, date_trunc('hour', $2::TIMESTAMPTZ), $3, $4, $5, $6)
+		ON CONFLICT(joint_id, bucket)
+		DO UPDATE SET
+			upvotes = joint_activity.upvotes + EXCLUDED.upvotes, downvotes = joint_activity.downvotes + EXCLUDED.downvotes,
+			checkins = joint_activity.checkins + EXCLUDED.checkins, views = joint_activity.views + EXCLUDED.views
+	`
+	_, err := tx.ExecContext(ctx, query, jointID, at, activity.UpVotes, activity.DownVotes, activity.Checkins, activity.Views)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AddView counts a view of a joint in the current hourly bucket
+func (r *ActivityRepository) AddView(ctx context.Context, jointID uuid.UUID) error {
+	query := `
+		INSERT INTO joint_activity(joint_id, bucket, views)
+		VALUES ($1, date_trunc('hour', NOW()), 1)
+		ON CONFLICT(joint_id, bucket)
+		DO UPDATE SET views = joint_activity.views + 1
+	`
+	_, err := r.db.ExecContext(ctx, query, jointID)
+	return err
+}
+
+// GetTrending ranks the joints within the radius by how far their activity since the given time is above their baseline.
+// The baseline is the average activity per window over the given number of windows before it. Joints whose activity is not above their baseline are left out
+func (r *ActivityRepository) GetTrending(ctx context.Context, cord model.Coordinate, radius float64, since time.Time, window time.Duration, baselineWindows int, page model.Page) (*model.Paged[*model.TrendingJoint], error) {
+	baselineSince := since.Add(-window * time.Duration(baselineWindows))
+	query := `
+		WITH activity AS (
+			SELECT
+				a.joint_id,
+				COALESCE(SUM(` + activityScore + `) FILTER (WHERE a.bucket >= $4), 0) AS recent,
+				COALESCE(SUM(` + activityScore + `) FILTER (WHERE a.bucket < $4), 0) / $6 AS baseline,
+				COALESCE(SUM(a.upvotes) FILTER (WHERE a.bucket >= $4), 0) AS upvotes,
+				COALESCE(SUM(a.downvotes) FILTER (WHERE a.bucket >= $4), 0) AS downvotes,
+				COALESCE(SUM(a.checkins) FILTER (WHERE a.bucket >= $4), 0) AS checkins,
+				COALESCE(SUM(a.views) FILTER (WHERE a.bucket >= $4), 0) AS views
+			FROM joint_activity a
+			JOIN joints j
+			ON j.id = a.joint_id
+			WHERE a.bucket >= $5 AND ST_DWithin(j.location, ST_Point($1, $2)::GEOGRAPHY, $3)
+			GROUP BY a.joint_id
+		)
+		SELECT
+			j.id, j.name, j.latitude, j.longitude, j.description, j.is_approved, j.visibility, j.category, j.creator_id, j.photo_url,
+			j.upvotes, j.downvotes, j.score, j.created_at, j.updated_at, ST_Distance(j.location, ST_Point($1, $2)::GEOGRAPHY) AS distance,
+			t.upvotes AS recent_upvotes, t.downvotes AS recent_downvotes, t.checkins AS recent_checkins, t.views AS recent_views,
+			-- the excess over the baseline is scaled down for joints that are usually busy
+			((t.recent - t.baseline) / SQRT(t.baseline + 1))::DOUBLE PRECISION AS velocity
+		FROM activity t
+		JOIN joints j
+		ON j.id = t.joint_id
+		WHERE j.is_approved = TRUE AND j.visibility = 'visible' AND j.deleted_at IS NULL AND t.recent > t.baseline
+	`
+	keys := []sortKey{{column: "velocity", cast: "DOUBLE PRECISION", desc: true}, {column: "id", cast: "UUID"}}
+	args := []any{cord.Longitude, cord.Latitude, radius, since, baselineSince, baselineWindows}
+	return queryPage(ctx, r.db, query, args, keys, page, func(row scanner) (*model.TrendingJoint, error) {
+		var joint model.TrendingJoint
+		var distance float64
+		if err := row.Scan(
+			&joint.ID,
+			&joint.Name,
+			&joint.Latitude,
+			&joint.Longitude,
+			&joint.Description,
+			&joint.IsApproved,
+			&joint.Visibility,
+			&joint.Category,
+			&joint.CreatorID,
+			&joint.PhotoURL,
+			&joint.UpVotes,
+			&joint.DownVotes,
+			&joint.Score,
+			&joint.CreatedAt,
+			&joint.UpdatedAt,
+			&distance,
+			&joint.Activity.UpVotes,
+			&joint.Activity.DownVotes,
+			&joint.Activity.Checkins,
+			&joint.Activity.Views,
+			&joint.Velocity,
+		); err != nil {
+			return nil, err
+		}
+		joint.Distance = &distance
+		return &joint, nil
+	})
+}
+
+// DeleteBefore removes activity buckets older than the given time along with the processed events that can no longer be redelivered as they have left the outbox
+func (r *ActivityRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM joint_activity WHERE bucket < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	query := `DELETE FROM joint_activity_events e WHERE NOT EXISTS (SELECT 1 FROM outbox_events o WHERE o.id = e.event_id)`
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
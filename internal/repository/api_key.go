@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository handles database operations for the personal API keys of users
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, last_used_ip, revoked_at, created_at`
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *APIKeyRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// GetByHash returns the key with the hash, whether or not it is still usable
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash))
+}
+
+// GetByUserID returns the keys of a user, newest first. Revoked and expired keys are included so that their owner can see when they were last used
+func (r *APIKeyRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CountActive returns the number of keys of a user that are neither revoked nor expired. The user is locked so that concurrent creations cannot exceed a limit checked with the count
+func (r *APIKeyRepository) CountActive(ctx context.Context, tx *sql.Tx, userID uuid.UUID) (int, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return 0, err
+	}
+
+	var count int
+	query := `SELECT COUNT(*) FROM api_keys WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()`
+	err := tx.QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+// Create adds a key. ErrAlreadyExist is returned when the prefix or hash is taken
+func (r *APIKeyRepository) Create(ctx context.Context, tx *sql.Tx, data *model.APIKey) (*model.APIKey, error) {
+	scopes, err := json.Marshal(data.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO api_keys(user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiKeyColumns
+	key, err := scanAPIKey(tx.QueryRowContext(ctx, query, data.UserID, data.Name, data.Prefix, data.KeyHash, string(scopes), data.ExpiresAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// Revoke stops a key of a user from being used. ErrNotFound is returned when the user has no such key or it was revoked already
+func (r *APIKeyRepository) Revoke(ctx context.Context, userID, id uuid.UUID) error {
+	query := `UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+	return expectAffected(r.db.ExecContext(ctx, query, id, userID))
+}
+
+// RecordUse stores when and where a key was last used. Uses within the interval of the last recorded one are skipped to spare a write on every request
+func (r *APIKeyRepository) RecordUse(ctx context.Context, id uuid.UUID, ipAddress string, interval time.Duration) error {
+	query := `
+		UPDATE api_keys
+		SET last_used_at = NOW(), last_used_ip = $2
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - make_interval(secs => $3))
+		`
+	_, err := r.db.ExecContext(ctx, query, id, nullableString(ipAddress), interval.Seconds())
+	return err
+}
+
+func scanAPIKey(row scanner) (*model.APIKey, error) {
+	var key model.APIKey
+	var scopes []byte
+	if err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.LastUsedIP,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
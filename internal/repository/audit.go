@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"chow/internal/model"
+)
+
+// AuditRepository handles database operations for audit events. Events are append-only and cannot be updated or deleted
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Create records an audit event. It must be called with the transaction of the change being audited so that both are committed together
+func (r *AuditRepository) Create(ctx context.Context, tx *sql.Tx, data *model.AuditEvent) (*model.AuditEvent, error) {
+	var event model.AuditEvent
+	var before, after []byte
+	query := `
+        INSERT INTO audit_events(actor_id, action, target_type, target_id, before, after, ip_address, request_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, actor_id, action, target_type, target_id, before, after, ip_address, request_id, created_at
+    `
+	if err := tx.QueryRowContext(ctx, query, data.ActorID, data.Action, data.TargetType, data.TargetID, nullableJSON(data.Before), nullableJSON(data.After), data.IPAddress, data.RequestID).Scan(
+		&event.ID,
+		&event.ActorID,
+		&event.Action,
+		&event.TargetType,
+		&event.TargetID,
+		&before,
+		&after,
+		&event.IPAddress,
+		&event.RequestID,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	event.Before, event.After = before, after
+	return &event, nil
+}
+
+// GetAll returns the requested page of audit events matching the filter, newest first
+func (r *AuditRepository) GetAll(ctx context.Context, filter model.AuditEventFilter, page model.Page) (*model.Paged[*model.AuditEvent], error) {
+	where, args := buildAuditFilter(filter)
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, before, after, ip_address, request_id, created_at
+		FROM audit_events
+		` + whereClause(where)
+
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, args, keys, page, scanAuditEvent)
+}
+
+// Stream calls fn for every audit event matching the filter in chronological order, stopping at the first error
+func (r *AuditRepository) Stream(ctx context.Context, filter model.AuditEventFilter, fn func(*model.AuditEvent) error) error {
+	where, args := buildAuditFilter(filter)
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, before, after, ip_address, request_id, created_at
+		FROM audit_events
+		` + whereClause(where) + `
+		ORDER BY created_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		event, err := scanAuditEvent(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func scanAuditEvent(row scanner) (*model.AuditEvent, error) {
+	var event model.AuditEvent
+	var before, after []byte
+	if err := row.Scan(
+		&event.ID,
+		&event.ActorID,
+		&event.Action,
+		&event.TargetType,
+		&event.TargetID,
+		&before,
+		&after,
+		&event.IPAddress,
+		&event.RequestID,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	event.Before, event.After = before, after
+	return &event, nil
+}
+
+// buildAuditFilter returns the where conditions and their positional arguments for an audit filter
+func buildAuditFilter(filter model.AuditEventFilter) ([]string, []any) {
+	var where []string
+	var args []any
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != nil {
+		add("actor_id = $%d", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		add("action = $%d", filter.Action)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		add("target_id = $%d", *filter.TargetID)
+	}
+	if filter.From != nil {
+		add("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		add("created_at < $%d", *filter.To)
+	}
+	return where, args
+}
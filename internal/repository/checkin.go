@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// CheckinRepository handles database operations for check-ins
+type CheckinRepository struct {
+	db *sql.DB
+}
+
+// NewCheckinRepository creates a new check-in repository
+func NewCheckinRepository(db *sql.DB) *CheckinRepository {
+	return &CheckinRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *CheckinRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// LockUserJoint serializes check-ins of a user at a joint until the transaction ends so that the cooldown cannot be bypassed by concurrent requests
+func (r *CheckinRepository) LockUserJoint(ctx context.Context, tx *sql.Tx, userID, jointID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('checkin:' || $1 || ':' || $2, 0))`, userID.String(), jointID.String())
+	return err
+}
+
+// Create records a check-in when the coordinates are within the tolerance in meters of the joint. ErrNotFound is returned when they are not
+func (r *CheckinRepository) Create(ctx context.Context, tx *sql.Tx, data *model.Checkin, tolerance float64) (*model.Checkin, error) {
+	query := `
+		INSERT INTO checkins(user_id, joint_id, location, distance)
+		SELECT $1, j.id, ST_Point($3, $4)::geography, ST_Distance(j.location, ST_Point($3, $4)::geography)
+		FROM joints j
+		WHERE j.id = $2 AND ST_DWithin(j.location, ST_Point($3, $4)::geography, $5)
+		RETURNING id, user_id, joint_id, ST_Y(location::geometry), ST_X(location::geometry), distance, created_at
+	`
+	var checkin model.Checkin
+	err := tx.QueryRowContext(ctx, query, data.UserID, data.JointID, data.Longitude, data.Latitude, tolerance).Scan(
+		&checkin.ID,
+		&checkin.UserID,
+		&checkin.JointID,
+		&checkin.Latitude,
+		&checkin.Longitude,
+		&checkin.Distance,
+		&checkin.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &checkin, nil
+}
+
+// GetLatestUserJointCheckin retrieves the most recent check-in of a user at a joint
+func (r *CheckinRepository) GetLatestUserJointCheckin(ctx context.Context, tx *sql.Tx, userID, jointID uuid.UUID) (*model.Checkin, error) {
+	query := `
+		SELECT id, user_id, joint_id, ST_Y(location::geometry), ST_X(location::geometry), distance, created_at
+		FROM checkins
+		WHERE user_id = $1 AND joint_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	var checkin model.Checkin
+	err := tx.QueryRowContext(ctx, query, userID, jointID).Scan(
+		&checkin.ID,
+		&checkin.UserID,
+		&checkin.JointID,
+		&checkin.Latitude,
+		&checkin.Longitude,
+		&checkin.Distance,
+		&checkin.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &checkin, nil
+}
+
+// HasCheckedIn reports whether a user has ever checked in at a joint
+func (r *CheckinRepository) HasCheckedIn(ctx context.Context, userID, jointID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM checkins WHERE user_id = $1 AND joint_id = $2)`
+	err := r.db.QueryRowContext(ctx, query, userID, jointID).Scan(&exists)
+	return exists, err
+}
+
+// GetVisitedJointIDs returns the subset of the joints a user has checked in at
+func (r *CheckinRepository) GetVisitedJointIDs(ctx context.Context, userID uuid.UUID, jointIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ids := make([]string, 0, len(jointIDs))
+	for _, id := range jointIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `SELECT DISTINCT joint_id FROM checkins WHERE user_id = $1 AND joint_id = ANY($2::uuid[])`
+	rows, err := r.db.QueryContext(ctx, query, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	visited := make(map[uuid.UUID]bool)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		visited[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return visited, nil
+}
+
+// GetUserCheckins retrieves the visit history of a user, newest first
+func (r *CheckinRepository) GetUserCheckins(ctx context.Context, userID uuid.UUID, page model.Page) (*model.Paged[*model.Visit], error) {
+	query := `
+		SELECT c.id, c.user_id, c.joint_id, ST_Y(c.location::geometry) AS latitude, ST_X(c.location::geometry) AS longitude, c.distance, c.created_at, j.name
+		FROM checkins c
+		JOIN joints j ON j.id = c.joint_id
+		WHERE c.user_id = $1 AND j.deleted_at IS NULL
+	`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{userID}, keys, page, func(row scanner) (*model.Visit, error) {
+		var visit model.Visit
+		if err := row.Scan(
+			&visit.ID,
+			&visit.UserID,
+			&visit.JointID,
+			&visit.Latitude,
+			&visit.Longitude,
+			&visit.Distance,
+			&visit.CreatedAt,
+			&visit.JointName,
+		); err != nil {
+			return nil, err
+		}
+		return &visit, nil
+	})
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"chow/internal/model"
 
@@ -19,186 +20,120 @@ func NewComplaintRepository(db *sql.DB) *ComplaintRepository {
 	return &ComplaintRepository{db: db}
 }
 
-func (r *ComplaintRepository) Create(ctx context.Context, data *model.Complaint) (*model.Complaint, error) {
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *ComplaintRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+func (r *ComplaintRepository) Create(ctx context.Context, tx *sql.Tx, data *model.Complaint) (*model.Complaint, error) {
 	var complaint model.Complaint
 	query := `
-        INSERT INTO complaints(joint_id, user_id, reason, status)
-        VALUES ($1, $2, $3, $4)
-		RETURNING id, joint_id, user_id, reason, status, created_at, updated_at
+        INSERT INTO complaints(joint_id, user_id, reason, category, status)
+        VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, joint_id, user_id, reason, category, status, created_at, updated_at
     `
-	if err := r.db.QueryRowContext(ctx, query, data.JointID, data.UserID, data.Reason, data.Status).Scan(
+	if err := tx.QueryRowContext(ctx, query, data.JointID, data.UserID, data.Reason, data.Category, data.Status).Scan(
 		&complaint.ID,
 		&complaint.JointID,
 		&complaint.UserID,
 		&complaint.Reason,
+		&complaint.Category,
 		&complaint.Status,
 		&complaint.CreatedAt,
 		&complaint.UpdatedAt,
 	); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
 		return nil, err
 	}
 	return &complaint, nil
 }
 
-func (r *ComplaintRepository) GetAll(ctx context.Context, offset, limit int) ([]*model.Complaint, error) {
+// complaintSortKeys orders complaints newest first
+var complaintSortKeys = []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+
+// GetAll returns the requested page of complaints on joints that have not been deleted, newest first
+func (r *ComplaintRepository) GetAll(ctx context.Context, page model.Page) (*model.Paged[*model.Complaint], error) {
 	query := `
-		SELECT id, joint_id, user_id, reason, status, created_at, updated_at
-		FROM complaints  
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		SELECT id, joint_id, user_id, reason, category, status, created_at, updated_at
+		FROM complaints
+		WHERE EXISTS (SELECT 1 FROM joints j WHERE j.id = complaints.joint_id AND j.deleted_at IS NULL)
 		`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	complaints := make([]*model.Complaint, 0, limit)
-
-	for rows.Next() {
-		var complaint model.Complaint
-		if err := rows.Scan(
-			&complaint.ID,
-			&complaint.JointID,
-			&complaint.UserID,
-			&complaint.Reason,
-			&complaint.Status,
-			&complaint.CreatedAt,
-			&complaint.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		complaints = append(complaints, &complaint)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return complaints, nil
+	return queryPage(ctx, r.db, query, nil, complaintSortKeys, page, scanComplaint)
 }
 
-func (r *ComplaintRepository) GetUserComplaints(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*model.Complaint, error) {
+// GetUserComplaints returns the requested page of the complaints made by a user, newest first
+func (r *ComplaintRepository) GetUserComplaints(ctx context.Context, userID uuid.UUID, page model.Page) (*model.Paged[*model.Complaint], error) {
 	query := `
-		SELECT id, joint_id, user_id, reason, status, created_at, updated_at
-		FROM complaints  
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		SELECT id, joint_id, user_id, reason, category, status, created_at, updated_at
+		FROM complaints
+		WHERE user_id = $1 AND EXISTS (SELECT 1 FROM joints j WHERE j.id = complaints.joint_id AND j.deleted_at IS NULL)
 		`
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	complaints := make([]*model.Complaint, 0, limit)
-
-	for rows.Next() {
-		var complaint model.Complaint
-		if err := rows.Scan(
-			&complaint.ID,
-			&complaint.JointID,
-			&complaint.UserID,
-			&complaint.Reason,
-			&complaint.Status,
-			&complaint.CreatedAt,
-			&complaint.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		complaints = append(complaints, &complaint)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return complaints, nil
+	return queryPage(ctx, r.db, query, []any{userID}, complaintSortKeys, page, scanComplaint)
 }
 
-func (r *ComplaintRepository) GetJointComplaints(ctx context.Context, jointID uuid.UUID, offset, limit int) ([]*model.Complaint, error) {
+// GetJointComplaints returns the requested page of the complaints made on a joint, newest first
+func (r *ComplaintRepository) GetJointComplaints(ctx context.Context, jointID uuid.UUID, page model.Page) (*model.Paged[*model.Complaint], error) {
 	query := `
-		SELECT id, joint_id, user_id, reason, status, created_at, updated_at
-		FROM complaints  
-		WHERE joint_id = $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
+		SELECT id, joint_id, user_id, reason, category, status, created_at, updated_at
+		FROM complaints
+		WHERE joint_id = $1 AND EXISTS (SELECT 1 FROM joints j WHERE j.id = complaints.joint_id AND j.deleted_at IS NULL)
 		`
-	rows, err := r.db.QueryContext(ctx, query, jointID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	complaints := make([]*model.Complaint, 0, limit)
-	for rows.Next() {
-		var complaint model.Complaint
-		if err := rows.Scan(
-			&complaint.ID,
-			&complaint.JointID,
-			&complaint.UserID,
-			&complaint.Reason,
-			&complaint.Status,
-			&complaint.CreatedAt,
-			&complaint.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		complaints = append(complaints, &complaint)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return complaints, nil
+	return queryPage(ctx, r.db, query, []any{jointID}, complaintSortKeys, page, scanComplaint)
 }
 
 // GetUserJointComplaints returns all complaints made by a user on a particular joint
-func (r *ComplaintRepository) GetUserJointComplaints(ctx context.Context, userID, jointID uuid.UUID, offset, limit int) ([]*model.Complaint, error) {
+func (r *ComplaintRepository) GetUserJointComplaints(ctx context.Context, userID, jointID uuid.UUID, page model.Page) (*model.Paged[*model.Complaint], error) {
+	query := `
+		SELECT id, joint_id, user_id, reason, category, status, created_at, updated_at
+		FROM complaints
+		WHERE user_id = $1 AND joint_id = $2 AND EXISTS (SELECT 1 FROM joints j WHERE j.id = complaints.joint_id AND j.deleted_at IS NULL)
+		`
+	return queryPage(ctx, r.db, query, []any{userID, jointID}, complaintSortKeys, page, scanComplaint)
+}
+
+func (r *ComplaintRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Complaint, error) {
 	query := `
-		SELECT id, joint_id, user_id, reason, status, created_at, updated_at
+		SELECT id, joint_id, user_id, reason, category, status, created_at, updated_at
 		FROM complaints  
-		WHERE user_id = $1 AND joint_id = $2
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4
+		WHERE id = $1
 		`
-	rows, err := r.db.QueryContext(ctx, query, userID, jointID, limit, offset)
+	var complaint model.Complaint
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&complaint.ID,
+		&complaint.JointID,
+		&complaint.UserID,
+		&complaint.Reason,
+		&complaint.Category,
+		&complaint.Status,
+		&complaint.CreatedAt,
+		&complaint.UpdatedAt,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	complaints := make([]*model.Complaint, 0, limit)
-
-	for rows.Next() {
-		var complaint model.Complaint
-		if err := rows.Scan(
-			&complaint.ID,
-			&complaint.JointID,
-			&complaint.UserID,
-			&complaint.Reason,
-			&complaint.Status,
-			&complaint.CreatedAt,
-			&complaint.UpdatedAt,
-		); err != nil {
-			return nil, err
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
 		}
-		complaints = append(complaints, &complaint)
-	}
-	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return complaints, nil
+	return &complaint, nil
 }
 
-func (r *ComplaintRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Complaint, error) {
+// LockByID retrieves a complaint and locks it until the transaction ends
+func (r *ComplaintRepository) LockByID(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*model.Complaint, error) {
 	query := `
-		SELECT id, joint_id, user_id, reason, status, created_at, updated_at
-		FROM complaints  
+		SELECT id, joint_id, user_id, reason, category, status, created_at, updated_at
+		FROM complaints
 		WHERE id = $1
+		FOR UPDATE
 		`
 	var complaint model.Complaint
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := tx.QueryRowContext(ctx, query, id).Scan(
 		&complaint.ID,
 		&complaint.JointID,
 		&complaint.UserID,
 		&complaint.Reason,
+		&complaint.Category,
 		&complaint.Status,
 		&complaint.CreatedAt,
 		&complaint.UpdatedAt,
@@ -212,19 +147,20 @@ func (r *ComplaintRepository) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return &complaint, nil
 }
 
-func (r *ComplaintRepository) UpdateComplaintStatus(ctx context.Context, id uuid.UUID, status model.ComplaintStatus) (*model.Complaint, error) {
+func (r *ComplaintRepository) UpdateComplaintStatus(ctx context.Context, tx *sql.Tx, id uuid.UUID, status model.ComplaintStatus) (*model.Complaint, error) {
 	query := `
         UPDATE complaints 
         SET status = $1, updated_at = NOW()
         WHERE id = $2
-        RETURNING id, joint_id, user_id, reason, status, created_at, updated_at
+        RETURNING id, joint_id, user_id, reason, category, status, created_at, updated_at
     `
 	var complaint model.Complaint
-	err := r.db.QueryRowContext(ctx, query, status, id).Scan(
+	err := tx.QueryRowContext(ctx, query, status, id).Scan(
 		&complaint.ID,
 		&complaint.JointID,
 		&complaint.UserID,
 		&complaint.Reason,
+		&complaint.Category,
 		&complaint.Status,
 		&complaint.CreatedAt,
 		&complaint.UpdatedAt,
@@ -237,3 +173,94 @@ func (r *ComplaintRepository) UpdateComplaintStatus(ctx context.Context, id uuid
 	}
 	return &complaint, nil
 }
+
+// CountOpenComplainantsByCategory returns the number of distinct users with open complaints against a joint for each category, considering only complaints filed since the given time.
+// It is called with the transaction that holds the lock on the joint so that the count and the decision made from it are not interleaved with another
+func (r *ComplaintRepository) CountOpenComplainantsByCategory(ctx context.Context, tx *sql.Tx, jointID uuid.UUID, since time.Time) (map[model.ComplaintCategory]int, error) {
+	query := `
+		SELECT category, COUNT(DISTINCT user_id)
+		FROM complaints
+		WHERE joint_id = $1 AND status = $2 AND created_at >= $3
+		GROUP BY category
+		`
+	rows, err := tx.QueryContext(ctx, query, jointID, model.OpenComplaint, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[model.ComplaintCategory]int)
+	for rows.Next() {
+		var category model.ComplaintCategory
+		var count int
+		if err := rows.Scan(&category, &count); err != nil {
+			return nil, err
+		}
+		counts[category] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CreateReply adds a reply to a complaint. It must be called with the transaction that publishes the reply
+func (r *ComplaintRepository) CreateReply(ctx context.Context, tx *sql.Tx, data *model.ComplaintReply) (*model.ComplaintReply, error) {
+	var reply model.ComplaintReply
+	query := `
+        INSERT INTO complaint_replies(complaint_id, user_id, body)
+        VALUES ($1, $2, $3)
+		RETURNING id, complaint_id, user_id, body, created_at
+    `
+	if err := tx.QueryRowContext(ctx, query, data.ComplaintID, data.UserID, data.Body).Scan(
+		&reply.ID,
+		&reply.ComplaintID,
+		&reply.UserID,
+		&reply.Body,
+		&reply.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// GetReplies returns the replies on a complaint in the order they were made
+func (r *ComplaintRepository) GetReplies(ctx context.Context, complaintID uuid.UUID, page model.Page) (*model.Paged[*model.ComplaintReply], error) {
+	query := `
+		SELECT id, complaint_id, user_id, body, created_at
+		FROM complaint_replies
+		WHERE complaint_id = $1
+		`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ"}, {column: "id", cast: "UUID"}}
+	return queryPage(ctx, r.db, query, []any{complaintID}, keys, page, func(row scanner) (*model.ComplaintReply, error) {
+		var reply model.ComplaintReply
+		if err := row.Scan(
+			&reply.ID,
+			&reply.ComplaintID,
+			&reply.UserID,
+			&reply.Body,
+			&reply.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		return &reply, nil
+	})
+}
+
+// scanComplaint scans a row of the columns of a complaint
+func scanComplaint(row scanner) (*model.Complaint, error) {
+	var complaint model.Complaint
+	if err := row.Scan(
+		&complaint.ID,
+		&complaint.JointID,
+		&complaint.UserID,
+		&complaint.Reason,
+		&complaint.Category,
+		&complaint.Status,
+		&complaint.CreatedAt,
+		&complaint.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &complaint, nil
+}
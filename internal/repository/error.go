@@ -1,10 +1,36 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
 
 // repository specific errors
 var (
 	ErrNotFound     = errors.New("not found")
 	ErrAlreadyExist = errors.New("already exists")
 	ErrCannotDelete = errors.New("cannot delete as other records depend on it")
+	// ErrInvalidCursor is returned when a cursor does not match the sort keys of the list it is used with
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrVoided is returned when a record was voided by moderators and can no longer be changed by its owner
+	ErrVoided = errors.New("voided")
+)
+
+// postgres error codes
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
 )
+
+// isUniqueViolation reports whether err was caused by a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// isForeignKeyViolation reports whether err was caused by a foreign key constraint violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
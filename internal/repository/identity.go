@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// IdentityRepository handles database operations for the OpenID Connect identities of users and the sign ins started with a provider
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *IdentityRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// GetBySubject returns the identity of the user with the subject at a provider
+func (r *IdentityRepository) GetBySubject(ctx context.Context, provider, subject string) (*model.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+		`
+	identity, err := scanIdentity(r.db.QueryRowContext(ctx, query, provider, subject))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// GetByUserID returns the identities of a user, oldest first
+func (r *IdentityRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	query := `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at, id
+		`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []model.UserIdentity{}
+	for rows.Next() {
+		identity, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, *identity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// Create links an identity to a user. ErrAlreadyExist is returned when the identity is linked already
+func (r *IdentityRepository) Create(ctx context.Context, tx *sql.Tx, data *model.UserIdentity) (*model.UserIdentity, error) {
+	query := `
+		INSERT INTO user_identities(user_id, provider, subject, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, provider, subject, email, created_at, last_login_at
+		`
+	identity, err := scanIdentity(tx.QueryRowContext(ctx, query, data.UserID, data.Provider, data.Subject, data.Email))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// RecordLogin updates the email of an identity as last reported by its provider along with the time it was signed in with
+func (r *IdentityRepository) RecordLogin(ctx context.Context, id uuid.UUID, email *string) error {
+	query := `
+		UPDATE user_identities
+		SET email = $2, last_login_at = NOW()
+		WHERE id = $1
+		`
+	return expectAffected(r.db.ExecContext(ctx, query, id, email))
+}
+
+// Delete removes an identity of a user and returns the number of identities the user has left. The user is locked so that concurrent removals cannot leave them without any
+func (r *IdentityRepository) Delete(ctx context.Context, tx *sql.Tx, userID, id uuid.UUID) (int, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return 0, err
+	}
+	if err := expectAffected(tx.ExecContext(ctx, `DELETE FROM user_identities WHERE id = $1 AND user_id = $2`, id, userID)); err != nil {
+		return 0, err
+	}
+
+	var remaining int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_identities WHERE user_id = $1`, userID).Scan(&remaining); err != nil {
+		return 0, err
+	}
+	return remaining, nil
+}
+
+// CreateState stores a sign in started with a provider
+func (r *IdentityRepository) CreateState(ctx context.Context, data *model.OIDCState) error {
+	query := `
+		INSERT INTO oidc_states(state_hash, provider, code_verifier, nonce, user_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		`
+	_, err := r.db.ExecContext(ctx, query, data.StateHash, data.Provider, data.CodeVerifier, data.Nonce, data.UserID, data.ExpiresAt)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// TakeState removes and returns a sign in that has not expired so that it can only be completed once
+func (r *IdentityRepository) TakeState(ctx context.Context, stateHash string) (*model.OIDCState, error) {
+	query := `
+		DELETE FROM oidc_states
+		WHERE state_hash = $1 AND expires_at > NOW()
+		RETURNING state_hash, provider, code_verifier, nonce, user_id, expires_at, created_at
+		`
+	var state model.OIDCState
+	if err := r.db.QueryRowContext(ctx, query, stateHash).Scan(
+		&state.StateHash,
+		&state.Provider,
+		&state.CodeVerifier,
+		&state.Nonce,
+		&state.UserID,
+		&state.ExpiresAt,
+		&state.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+// DeleteExpiredStates removes the sign ins that expired before the given time. It returns the number of sign ins removed
+func (r *IdentityRepository) DeleteExpiredStates(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM oidc_states WHERE expires_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanIdentity(row scanner) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	if err := row.Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+		&identity.LastLoginAt,
+	); err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"chow/internal/model"
 
@@ -24,24 +25,27 @@ func (r *JointRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
 	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
 }
 
-func (r *JointRepository) Create(ctx context.Context, data *model.Joint) (*model.Joint, error) {
+func (r *JointRepository) Create(ctx context.Context, tx *sql.Tx, data *model.Joint) (*model.Joint, error) {
 	var joint model.Joint
 	query := `
-        INSERT INTO joints(name, latitude, longitude, location, description, is_approved, creator_id, photo_url)
-        VALUES ($1, $2, $3, ST_Point($4, $5), $6, $7, $8, $9)
-		RETURNING id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at
+        INSERT INTO joints(name, latitude, longitude, location, description, is_approved, creator_id, photo_url, category, locale)
+        VALUES ($1, $2, $3, ST_Point($4, $5), $6, $7, $8, $9, $10, $11)
+		RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
     `
-	if err := r.db.QueryRowContext(ctx, query, data.Name, data.Latitude, data.Longitude, data.Longitude, data.Latitude, data.Description, data.IsApproved, data.CreatorID, data.PhotoURL).Scan(
+	if err := tx.QueryRowContext(ctx, query, data.Name, data.Latitude, data.Longitude, data.Longitude, data.Latitude, data.Description, data.IsApproved, data.CreatorID, data.PhotoURL, data.Category, data.DefaultLocale).Scan(
 		&joint.ID,
 		&joint.Name,
 		&joint.Latitude,
 		&joint.Longitude,
 		&joint.Description,
 		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
 		&joint.CreatorID,
 		&joint.PhotoURL,
 		&joint.UpVotes,
 		&joint.DownVotes,
+		&joint.Score,
 		&joint.CreatedAt,
 		&joint.UpdatedAt,
 	); err != nil {
@@ -52,9 +56,9 @@ func (r *JointRepository) Create(ctx context.Context, data *model.Joint) (*model
 
 func (r *JointRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Joint, error) {
 	query := `
-		SELECT id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
 		FROM joints  
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		`
 	var joint model.Joint
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
@@ -64,10 +68,13 @@ func (r *JointRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Joi
 		&joint.Longitude,
 		&joint.Description,
 		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
 		&joint.CreatorID,
 		&joint.PhotoURL,
 		&joint.UpVotes,
 		&joint.DownVotes,
+		&joint.Score,
 		&joint.CreatedAt,
 		&joint.UpdatedAt,
 	)
@@ -80,68 +87,130 @@ func (r *JointRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Joi
 	return &joint, nil
 }
 
-func (r *JointRepository) GetAll(ctx context.Context, offset, limit int) ([]*model.Joint, error) {
+// LockByID retrieves a joint and locks it until the transaction ends, so that changes decided from its current state are made one at a time
+func (r *JointRepository) LockByID(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*model.Joint, error) {
 	query := `
-		SELECT id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
 		FROM joints
-		WHERE is_approved = true
-		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
 		`
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	joint, err := scanJoint(tx.QueryRowContext(ctx, query, id))
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
-	defer rows.Close()
+	return joint, nil
+}
 
-	joints := make([]*model.Joint, 0)
-	for rows.Next() {
-		var joint model.Joint
-		if err := rows.Scan(
-			&joint.ID,
-			&joint.Name,
-			&joint.Latitude,
-			&joint.Longitude,
-			&joint.Description,
-			&joint.IsApproved,
-			&joint.CreatorID,
-			&joint.PhotoURL,
-			&joint.UpVotes,
-			&joint.DownVotes,
-			&joint.CreatedAt,
-			&joint.UpdatedAt,
-		); err != nil {
-			return nil, err
+// GetAll returns the requested page of approved and visible joints, newest first
+func (r *JointRepository) GetAll(ctx context.Context, page model.Page) (*model.Paged[*model.Joint], error) {
+	query := `
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+		FROM joints
+		WHERE is_approved = true AND visibility = 'visible' AND deleted_at IS NULL
+		`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, nil, keys, page, scanJoint)
+}
+
+func (r *JointRepository) UpdateByID(ctx context.Context, tx *sql.Tx, id uuid.UUID, data *model.Joint) (*model.Joint, error) {
+	query := `
+        UPDATE joints 
+        SET name = $1, latitude = $2, longitude = $3, location = ST_Point($4, $5), description = $6, is_approved = $7, photo_url = $8, category = $9, updated_at = NOW()
+        WHERE id = $10 AND deleted_at IS NULL
+        RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+    `
+
+	var joint model.Joint
+	err := tx.QueryRowContext(ctx, query, data.Name, data.Latitude, data.Longitude, data.Longitude, data.Latitude, data.Description, data.IsApproved, data.PhotoURL, data.Category, id).Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
 		}
-		joints = append(joints, &joint)
+		return nil, err
 	}
+	return &joint, nil
+}
 
-	if err := rows.Err(); err != nil {
+// UpdateApproval sets the approval status of a joint
+func (r *JointRepository) UpdateApproval(ctx context.Context, tx *sql.Tx, id uuid.UUID, approved bool) (*model.Joint, error) {
+	query := `
+        UPDATE joints 
+        SET is_approved = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+        RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+    `
+
+	var joint model.Joint
+	err := tx.QueryRowContext(ctx, query, approved, id).Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
-	return joints, nil
+	return &joint, nil
 }
 
-func (r *JointRepository) UpdateByID(ctx context.Context, id uuid.UUID, data *model.Joint) (*model.Joint, error) {
+// UpdateVisibility sets whether a joint is publicly listed without changing its approval status
+func (r *JointRepository) UpdateVisibility(ctx context.Context, tx *sql.Tx, id uuid.UUID, visibility model.JointVisibility) (*model.Joint, error) {
 	query := `
         UPDATE joints 
-        SET name = $1, latitude = $2, longitude = $3, location = ST_Point($4, $5), description = $6, is_approved = $7, photo_url = $8, updated_at = NOW()
-        WHERE id = $9
-        RETURNING id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at
+        SET visibility = $1, updated_at = NOW()
+        WHERE id = $2 AND deleted_at IS NULL
+        RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
     `
 
 	var joint model.Joint
-	err := r.db.QueryRowContext(ctx, query, data.Name, data.Latitude, data.Longitude, data.Longitude, data.Latitude, data.Description, data.IsApproved, data.PhotoURL, id).Scan(
+	err := tx.QueryRowContext(ctx, query, visibility, id).Scan(
 		&joint.ID,
 		&joint.Name,
 		&joint.Latitude,
 		&joint.Longitude,
 		&joint.Description,
 		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
 		&joint.CreatorID,
 		&joint.PhotoURL,
 		&joint.UpVotes,
 		&joint.DownVotes,
+		&joint.Score,
 		&joint.CreatedAt,
 		&joint.UpdatedAt,
 	)
@@ -154,9 +223,14 @@ func (r *JointRepository) UpdateByID(ctx context.Context, id uuid.UUID, data *mo
 	return &joint, nil
 }
 
-func (r *JointRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	query := `DELETE FROM joints WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+// DeleteByID soft deletes a joint, hiding it and its dependents from every query until it is restored or purged
+func (r *JointRepository) DeleteByID(ctx context.Context, tx *sql.Tx, id uuid.UUID, deletedBy uuid.UUID, reason *string) error {
+	query := `
+		UPDATE joints
+		SET deleted_at = NOW(), deleted_by = $1, deletion_reason = $2, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, query, deletedBy, reason, id)
 	if err != nil {
 		return err
 	}
@@ -171,43 +245,33 @@ func (r *JointRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// UpdateVote updates the upvotes/downvotes count for a given joint. A parent transaction should be passed as an argument to ensure that inserting a vote record and upvoting/downvoting is atomic
-func (r *JointRepository) UpdateVotes(ctx context.Context, tx *sql.Tx, id uuid.UUID, direction model.VoteDirection, voteExists bool) (*model.Joint, error) {
-	query := "UPDATE JOINT SET "
-	// increment one direction while decrementing the other for existing votes
-	if voteExists {
-		if direction == model.UpVote {
-			query += "upvotes = upvotes + 1, downvotes = downvotes - 1"
-		} else {
-			query += "downvotes = downvotes + 1, upvotes = upvotes - 1"
-		}
-	} else {
-		if direction == model.UpVote {
-			query += "upvotes = upvotes + 1"
-		} else {
-			query += "downvotes = downvotes + 1"
-		}
-	}
-
-	query += `
-		WHERE id = $1
-		RETURNING id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at
-	`
-
+// GetDeletedByID retrieves a soft deleted joint
+func (r *JointRepository) GetDeletedByID(ctx context.Context, id uuid.UUID) (*model.Joint, error) {
+	query := `
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at, deleted_at, deleted_by, deletion_reason
+		FROM joints
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		`
 	var joint model.Joint
-	err := tx.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&joint.ID,
 		&joint.Name,
 		&joint.Latitude,
 		&joint.Longitude,
 		&joint.Description,
 		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
 		&joint.CreatorID,
 		&joint.PhotoURL,
 		&joint.UpVotes,
 		&joint.DownVotes,
+		&joint.Score,
 		&joint.CreatedAt,
 		&joint.UpdatedAt,
+		&joint.DeletedAt,
+		&joint.DeletedBy,
+		&joint.DeletionReason,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -218,83 +282,404 @@ func (r *JointRepository) UpdateVotes(ctx context.Context, tx *sql.Tx, id uuid.U
 	return &joint, nil
 }
 
-// Search searches for a given joint by name or description
-func (r *JointRepository) Search(ctx context.Context, q string, offset, limit int) ([]*model.Joint, error) {
+// LockDeletedByID retrieves a soft deleted joint and locks it until the transaction ends
+func (r *JointRepository) LockDeletedByID(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*model.Joint, error) {
 	query := `
-		SELECT id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at, deleted_at, deleted_by, deletion_reason
 		FROM joints
-		WHERE is_approved = true AND name ILIKE $1 OR description ILIKE $1
-		ORDER BY name
-		LIMIT $2 OFFSET $3
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		FOR UPDATE
 		`
-	rows, err := r.db.QueryContext(ctx, query, "%"+q+"%", limit, offset)
+	var joint model.Joint
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+		&joint.DeletedAt,
+		&joint.DeletedBy,
+		&joint.DeletionReason,
+	)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
-	defer rows.Close()
+	return &joint, nil
+}
 
-	joints := make([]*model.Joint, 0)
-	for rows.Next() {
+// GetDeleted returns the requested page of soft deleted joints, most recently deleted first
+func (r *JointRepository) GetDeleted(ctx context.Context, page model.Page) (*model.Paged[*model.Joint], error) {
+	query := `
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at, deleted_at, deleted_by, deletion_reason
+		FROM joints
+		WHERE deleted_at IS NOT NULL
+		`
+	keys := []sortKey{{column: "deleted_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, nil, keys, page, func(row scanner) (*model.Joint, error) {
 		var joint model.Joint
-		if err := rows.Scan(
+		if err := row.Scan(
 			&joint.ID,
 			&joint.Name,
 			&joint.Latitude,
 			&joint.Longitude,
 			&joint.Description,
 			&joint.IsApproved,
+			&joint.Visibility,
+			&joint.Category,
 			&joint.CreatorID,
 			&joint.PhotoURL,
 			&joint.UpVotes,
 			&joint.DownVotes,
+			&joint.Score,
 			&joint.CreatedAt,
 			&joint.UpdatedAt,
+			&joint.DeletedAt,
+			&joint.DeletedBy,
+			&joint.DeletionReason,
 		); err != nil {
 			return nil, err
 		}
+		return &joint, nil
+	})
+}
 
-		joints = append(joints, &joint)
+// Restore brings back a soft deleted joint
+func (r *JointRepository) Restore(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*model.Joint, error) {
+	query := `
+        UPDATE joints 
+        SET deleted_at = NULL, deleted_by = NULL, deletion_reason = NULL, updated_at = NOW()
+        WHERE id = $1 AND deleted_at IS NOT NULL
+        RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+    `
+
+	var joint model.Joint
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &joint, nil
+}
+
+// GetPurgeable returns the IDs of joints soft deleted before the given time
+func (r *JointRepository) GetPurgeable(ctx context.Context, deletedBefore time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM joints
+		WHERE deleted_at < $1
+		ORDER BY deleted_at
+		LIMIT $2
+		`
+	rows, err := r.db.QueryContext(ctx, query, deletedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
-	return joints, nil
+	return ids, nil
 }
 
-// GetNearby finds nearby joints within the from the given coordinates within the provided radius in meters
-func (r *JointRepository) GetNearby(ctx context.Context, cord model.Coordinate, radius float64, offset, limit int) ([]*model.Joint, error) {
+// GetUnderReview returns the IDs of joints hidden while complaints against them are reviewed, ordered by ID starting after the given one
+func (r *JointRepository) GetUnderReview(ctx context.Context, after uuid.UUID, limit int) ([]uuid.UUID, error) {
 	query := `
-		SELECT id, name, latitude, longitude, description, is_approved, creator_id, photo_url, upvotes, downvotes, created_at, updated_at, ST_Distance(location, ST_Point($1, $2)::GEOGRAPHY) AS distance
+		SELECT id
 		FROM joints
-		WHERE is_approved = true AND 
-		-- nearby distance relative to the location. (lon, lat)
-		ST_DWithin(location, ST_Point($1, $2)::GEOGRAPHY, $3)
-		ORDER BY distance
-		LIMIT $4 OFFSET $5
+		WHERE visibility = $1 AND deleted_at IS NULL AND id > $2
+		ORDER BY id
+		LIMIT $3
 		`
-	rows, err := r.db.QueryContext(ctx, query, cord.Longitude, cord.Latitude, radius, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, model.UnderReviewJoint, after, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	joints := make([]*model.Joint, 0)
+	ids := make([]uuid.UUID, 0)
 	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Purge permanently removes a soft deleted joint along with its votes and complaints. ErrCannotDelete is returned if other records still reference the joint
+func (r *JointRepository) Purge(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	// dependents are removed first so that the joint itself can be deleted
+	dependents := []string{
+		`DELETE FROM vote_reviews WHERE joint_id = $1`,
+		`DELETE FROM votes WHERE joint_id = $1`,
+		`DELETE FROM complaint_replies WHERE complaint_id IN (SELECT id FROM complaints WHERE joint_id = $1)`,
+		`DELETE FROM complaints WHERE joint_id = $1`,
+		`DELETE FROM saved_search_matches WHERE joint_id = $1`,
+		`DELETE FROM checkins WHERE joint_id = $1`,
+		// reputation earned for the joint is kept in the ledger
+		`UPDATE reputation_events SET joint_id = NULL WHERE joint_id = $1`,
+	}
+	for _, query := range dependents {
+		if _, err := tx.ExecContext(ctx, query, id); err != nil {
+			return err
+		}
+	}
+
+	query := `DELETE FROM joints WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrCannotDelete
+		}
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// scoreExpression computes the weighted score of the joint being updated from its votes. $1 is the weight of verified votes, $2 the weight of trusted voters and $3 the reputation of trusted voters
+const scoreExpression = `(
+	SELECT COALESCE(SUM(
+		CASE WHEN v.verified THEN $1 ELSE 1 END
+		* CASE WHEN u.reputation >= $3 THEN $2 ELSE 1 END
+		* CASE WHEN v.direction = 'up' THEN 1 ELSE -1 END
+	), 0)
+	FROM votes v
+	JOIN users u
+	ON u.id = v.user_id
+	WHERE v.joint_id = joints.id AND v.voided_at IS NULL
+)`
+
+// UpdateScore recomputes the weighted score of a joint from its votes and returns the joint with its current vote counts.
+// The vote counts themselves are maintained by a trigger on the votes table so the transaction that changed the votes should be passed to read them
+func (r *JointRepository) UpdateScore(ctx context.Context, tx *sql.Tx, id uuid.UUID, weights model.VoteWeights) (*model.Joint, error) {
+	query := `
+		UPDATE joints
+		SET score = ` + scoreExpression + `
+		WHERE id = $4 AND deleted_at IS NULL
+		RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+	`
+
+	var joint model.Joint
+	err := tx.QueryRowContext(ctx, query, weights.Verified, weights.Trusted, weights.TrustedReputation, id).Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &joint, nil
+}
+
+// UpdateVoterScores recomputes the scores of the joints a user has voted on, such as when the weight of their votes changes
+func (r *JointRepository) UpdateVoterScores(ctx context.Context, tx *sql.Tx, userID uuid.UUID, weights model.VoteWeights) error {
+	query := `
+		UPDATE joints
+		SET score = ` + scoreExpression + `
+		WHERE id IN (SELECT joint_id FROM votes WHERE user_id = $4)
+	`
+	_, err := tx.ExecContext(ctx, query, weights.Verified, weights.Trusted, weights.TrustedReputation, userID)
+	return err
+}
+
+// GetVoteCountDiscrepancies returns the joints whose vote counts differ from the votes recorded for them. Voided votes are not counted
+func (r *JointRepository) GetVoteCountDiscrepancies(ctx context.Context, limit int) ([]*model.VoteCountDiscrepancy, error) {
+	query := `
+		SELECT j.id, j.upvotes, j.downvotes, COALESCE(v.upvotes, 0), COALESCE(v.downvotes, 0)
+		FROM joints j
+		LEFT JOIN (
+			SELECT joint_id, COUNT(*) FILTER (WHERE direction = 'up') AS upvotes, COUNT(*) FILTER (WHERE direction = 'down') AS downvotes
+			FROM votes
+			WHERE voided_at IS NULL
+			GROUP BY joint_id
+		) v
+		ON v.joint_id = j.id
+		WHERE j.upvotes <> COALESCE(v.upvotes, 0) OR j.downvotes <> COALESCE(v.downvotes, 0)
+		ORDER BY j.id
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discrepancies := make([]*model.VoteCountDiscrepancy, 0)
+	for rows.Next() {
+		var discrepancy model.VoteCountDiscrepancy
+		if err := rows.Scan(
+			&discrepancy.JointID,
+			&discrepancy.UpVotes,
+			&discrepancy.DownVotes,
+			&discrepancy.ActualUpVotes,
+			&discrepancy.ActualDownVotes,
+		); err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, &discrepancy)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return discrepancies, nil
+}
+
+// ReconcileVoteCounts recomputes the vote counts of a joint from its votes.
+// The joint is locked before counting so that votes committed concurrently are either counted or applied by the trigger afterwards, never both
+func (r *JointRepository) ReconcileVoteCounts(ctx context.Context, tx *sql.Tx, id uuid.UUID) (*model.Joint, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM joints WHERE id = $1 FOR UPDATE`, id); err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE joints
+		SET
+			upvotes = (SELECT COUNT(*) FROM votes WHERE joint_id = $1 AND direction = 'up' AND voided_at IS NULL),
+			downvotes = (SELECT COUNT(*) FROM votes WHERE joint_id = $1 AND direction = 'down' AND voided_at IS NULL)
+		WHERE id = $1
+		RETURNING id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+	`
+
+	var joint model.Joint
+	err := tx.QueryRowContext(ctx, query, id).Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &joint, nil
+}
+
+// Search searches for a given joint by name or description
+func (r *JointRepository) Search(ctx context.Context, q string, page model.Page) (*model.Paged[*model.Joint], error) {
+	query := `
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at
+		FROM joints
+		WHERE is_approved = true AND visibility = 'visible' AND deleted_at IS NULL AND (
+			name ILIKE $1 OR description ILIKE $1 OR
+			-- joints are also found by their translated names and descriptions
+			EXISTS (SELECT 1 FROM joint_translations t WHERE t.joint_id = joints.id AND (t.name ILIKE $1 OR t.description ILIKE $1))
+		)
+		`
+	keys := []sortKey{{column: "name", cast: "TEXT"}, {column: "id", cast: "UUID"}}
+	return queryPage(ctx, r.db, query, []any{"%" + q + "%"}, keys, page, scanJoint)
+}
+
+// GetNearby finds nearby joints within the from the given coordinates within the provided radius in meters
+func (r *JointRepository) GetNearby(ctx context.Context, cord model.Coordinate, radius float64, page model.Page) (*model.Paged[*model.Joint], error) {
+	query := `
+		SELECT id, name, latitude, longitude, description, is_approved, visibility, category, creator_id, photo_url, upvotes, downvotes, score, created_at, updated_at, ST_Distance(location, ST_Point($1, $2)::GEOGRAPHY) AS distance
+		FROM joints
+		WHERE is_approved = true AND visibility = 'visible' AND deleted_at IS NULL AND
+		-- nearby distance relative to the location. (lon, lat)
+		ST_DWithin(location, ST_Point($1, $2)::GEOGRAPHY, $3)
+		`
+	// joints at the same distance are told apart by their id
+	keys := []sortKey{{column: "distance", cast: "DOUBLE PRECISION"}, {column: "id", cast: "UUID"}}
+	return queryPage(ctx, r.db, query, []any{cord.Longitude, cord.Latitude, radius}, keys, page, func(row scanner) (*model.Joint, error) {
 		var joint model.Joint
 		var distance float64
 
-		if err := rows.Scan(
+		if err := row.Scan(
 			&joint.ID,
 			&joint.Name,
 			&joint.Latitude,
 			&joint.Longitude,
 			&joint.Description,
 			&joint.IsApproved,
+			&joint.Visibility,
+			&joint.Category,
 			&joint.CreatorID,
 			&joint.PhotoURL,
 			&joint.UpVotes,
 			&joint.DownVotes,
+			&joint.Score,
 			&joint.CreatedAt,
 			&joint.UpdatedAt,
 			&distance,
@@ -304,11 +689,31 @@ func (r *JointRepository) GetNearby(ctx context.Context, cord model.Coordinate,
 
 		// update distance
 		joint.Distance = &distance
-		joints = append(joints, &joint)
-	}
+		return &joint, nil
+	})
+}
 
-	if err := rows.Err(); err != nil {
+// scanJoint scans a row of the columns of a joint
+func scanJoint(row scanner) (*model.Joint, error) {
+	var joint model.Joint
+	if err := row.Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+	); err != nil {
 		return nil, err
 	}
-	return joints, nil
+	return &joint, nil
 }
@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// NotificationRepository handles database operations for notifications and notification preferences
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create adds a notification to a user's inbox. Notifications for an event the user was already notified about are ignored and ErrAlreadyExist is returned.
+// When a group key is set and the user has an unread notification with the same key, that notification is updated and its count incremented instead.
+// The event is recorded along with the notification so that a redelivered event is not counted again once it has been grouped
+func (r *NotificationRepository) Create(ctx context.Context, data *model.Notification) (*model.Notification, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if data.EventID != nil {
+		result, err := tx.ExecContext(ctx, `INSERT INTO notification_events(user_id, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, data.UserID, data.EventID)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rows == 0 {
+			return nil, ErrAlreadyExist
+		}
+	}
+
+	query := `
+        INSERT INTO notifications(user_id, type, title, body, target_type, target_id, event_id)
+        VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+		RETURNING id, user_id, type, title, body, target_type, target_id, count, read_at, created_at, updated_at
+    `
+	args := []any{data.UserID, data.Type, data.Title, data.Body, data.TargetType, data.TargetID, data.EventID}
+
+	if data.GroupKey != nil {
+		query = `
+			INSERT INTO notifications(user_id, type, title, body, target_type, target_id, event_id, group_key)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (user_id, group_key) WHERE read_at IS NULL
+			DO UPDATE SET
+				title = EXCLUDED.title, body = EXCLUDED.body, target_type = EXCLUDED.target_type, target_id = EXCLUDED.target_id,
+				count = notifications.count + 1, updated_at = NOW()
+			RETURNING id, user_id, type, title, body, target_type, target_id, count, read_at, created_at, updated_at
+		`
+		args = append(args, data.GroupKey)
+	}
+
+	var notification model.Notification
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Type,
+		&notification.Title,
+		&notification.Body,
+		&notification.TargetType,
+		&notification.TargetID,
+		&notification.Count,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	if err != nil {
+		// nothing is returned when the event was already delivered
+		if err == sql.ErrNoRows || isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// DeleteProcessedEvents forgets the events notifications were made for once they have left the outbox and can no longer be redelivered
+func (r *NotificationRepository) DeleteProcessedEvents(ctx context.Context) (int64, error) {
+	query := `DELETE FROM notification_events e WHERE NOT EXISTS (SELECT 1 FROM outbox_events o WHERE o.id = e.event_id)`
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetUserNotifications returns the requested page of a user's notifications, newest first
+func (r *NotificationRepository) GetUserNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, page model.Page) (*model.Paged[*model.Notification], error) {
+	where := []string{"user_id = $1"}
+	if unreadOnly {
+		where = append(where, "read_at IS NULL")
+	}
+	query := `
+		SELECT id, user_id, type, title, body, target_type, target_id, count, read_at, created_at, updated_at
+		FROM notifications
+		` + whereClause(where)
+
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{userID}, keys, page, func(row scanner) (*model.Notification, error) {
+		var notification model.Notification
+		if err := row.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.Title,
+			&notification.Body,
+			&notification.TargetType,
+			&notification.TargetID,
+			&notification.Count,
+			&notification.ReadAt,
+			&notification.CreatedAt,
+			&notification.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		return &notification, nil
+	})
+}
+
+// CountUnread returns the number of unread notifications of a user
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkRead marks a notification of a user as read
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) (*model.Notification, error) {
+	query := `
+        UPDATE notifications 
+        SET read_at = COALESCE(read_at, NOW()), updated_at = NOW()
+        WHERE id = $1 AND user_id = $2
+        RETURNING id, user_id, type, title, body, target_type, target_id, count, read_at, created_at, updated_at
+    `
+	var notification model.Notification
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Type,
+		&notification.Title,
+		&notification.Body,
+		&notification.TargetType,
+		&notification.TargetID,
+		&notification.Count,
+		&notification.ReadAt,
+		&notification.CreatedAt,
+		&notification.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns how many were updated
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	query := `UPDATE notifications SET read_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// GetPreferences returns the notification preferences a user has set. Types without a preference are enabled
+func (r *NotificationRepository) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*model.NotificationPreference, error) {
+	query := `SELECT type, enabled FROM notification_preferences WHERE user_id = $1`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	preferences := make([]*model.NotificationPreference, 0)
+	for rows.Next() {
+		var preference model.NotificationPreference
+		if err := rows.Scan(&preference.Type, &preference.Enabled); err != nil {
+			return nil, err
+		}
+		preferences = append(preferences, &preference)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return preferences, nil
+}
+
+// IsEnabled reports whether a user wants to receive notifications of the given type
+func (r *NotificationRepository) IsEnabled(ctx context.Context, userID uuid.UUID, notificationType model.NotificationType) (bool, error) {
+	query := `SELECT enabled FROM notification_preferences WHERE user_id = $1 AND type = $2`
+
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, query, userID, notificationType).Scan(&enabled)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return true, nil
+		}
+		return false, err
+	}
+	return enabled, nil
+}
+
+// UpsertPreferences saves the notification preferences of a user in a single transaction
+func (r *NotificationRepository) UpsertPreferences(ctx context.Context, userID uuid.UUID, preferences []model.NotificationPreference) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        INSERT INTO notification_preferences(user_id, type, enabled)
+        VALUES ($1, $2, $3)
+		ON CONFLICT(user_id, type)
+		DO UPDATE SET
+			enabled = EXCLUDED.enabled, updated_at = NOW()
+    `
+	for _, preference := range preferences {
+		if _, err := tx.ExecContext(ctx, query, userID, preference.Type, preference.Enabled); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// OutboxRepository handles database operations for the transactional outbox
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *OutboxRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// Create adds an event to the outbox. It must be called with the transaction of the state change so that the event is only published if the change is committed
+func (r *OutboxRepository) Create(ctx context.Context, tx *sql.Tx, data *model.Event) (*model.Event, error) {
+	var event model.Event
+	var payload []byte
+	query := `
+        INSERT INTO outbox_events(event_type, aggregate_id, actor_id, payload)
+        VALUES ($1, $2, $3, $4)
+		RETURNING id, event_type, aggregate_id, actor_id, payload, created_at
+    `
+	if err := tx.QueryRowContext(ctx, query, data.Type, data.AggregateID, data.ActorID, string(data.Payload)).Scan(
+		&event.ID,
+		&event.Type,
+		&event.AggregateID,
+		&event.ActorID,
+		&payload,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	event.Payload = payload
+	return &event, nil
+}
+
+// ClaimDue returns undelivered events that are due for a delivery attempt, oldest first. Claimed events are leased by pushing their next attempt past the lease
+// so that other dispatchers skip them while they are being handled, and are attempted again once it expires if their outcome was never recorded
+func (r *OutboxRepository) ClaimDue(ctx context.Context, maxAttempts, limit int, lease time.Duration) ([]*model.OutboxEvent, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM outbox_events
+			WHERE dispatched_at IS NULL AND attempts < $1 AND next_attempt_at <= NOW()
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox_events e
+		SET next_attempt_at = NOW() + make_interval(secs => $3)
+		FROM due
+		WHERE e.id = due.id
+		RETURNING e.id, e.event_type, e.aggregate_id, e.actor_id, e.payload, e.created_at, e.attempts, e.delivered_to
+		`
+	rows, err := r.db.QueryContext(ctx, query, maxAttempts, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*model.OutboxEvent, 0, limit)
+	for rows.Next() {
+		var event model.OutboxEvent
+		var payload, deliveredTo []byte
+		if err := rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.AggregateID,
+			&event.ActorID,
+			&payload,
+			&event.CreatedAt,
+			&event.Attempts,
+			&deliveredTo,
+		); err != nil {
+			return nil, err
+		}
+		event.Payload = payload
+		if err := json.Unmarshal(deliveredTo, &event.DeliveredTo); err != nil {
+			return nil, err
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// the rows returned by an update are not ordered
+	slices.SortFunc(events, func(a, b *model.OutboxEvent) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return events, nil
+}
+
+// MarkDispatched records that an event has been handled by every subscriber
+func (r *OutboxRepository) MarkDispatched(ctx context.Context, id uuid.UUID, deliveredTo []string) error {
+	delivered, err := json.Marshal(deliveredTo)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE outbox_events
+		SET dispatched_at = NOW(), delivered_to = $1, attempts = attempts + 1, last_error = NULL
+		WHERE id = $2
+	`
+	_, err = r.db.ExecContext(ctx, query, string(delivered), id)
+	return err
+}
+
+// MarkFailed records a failed delivery attempt and schedules the next one after the backoff. Subscribers in deliveredTo are not called again
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, deliveredTo []string, lastError string, backoff time.Duration) error {
+	delivered, err := json.Marshal(deliveredTo)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE outbox_events
+		SET delivered_to = $1, attempts = attempts + 1, last_error = $2, next_attempt_at = NOW() + make_interval(secs => $3)
+		WHERE id = $4
+	`
+	_, err = r.db.ExecContext(ctx, query, string(delivered), lastError, backoff.Seconds(), id)
+	return err
+}
+
+// Release ends the lease of a claimed event that was not attempted so that it is due again straight away
+func (r *OutboxRepository) Release(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox_events SET next_attempt_at = NOW() WHERE id = $1 AND dispatched_at IS NULL`, id)
+	return err
+}
+
+// DeleteDispatchedBefore removes events dispatched before the given time and returns how many were removed
+func (r *OutboxRepository) DeleteDispatchedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM outbox_events WHERE dispatched_at < $1`
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
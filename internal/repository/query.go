@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"chow/internal/model"
+)
+
+// whereClause joins conditions into a WHERE clause. No clause is returned when there are no conditions
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+// placeholder returns the positional parameter number for use after a `$`
+func placeholder(n int) string {
+	return strconv.Itoa(n)
+}
+
+// nullableJSON stores empty json documents as NULL
+func nullableJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
+
+// nullableString stores empty strings as NULL
+func nullableString(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// sortKey is a column a paginated list is ordered by. The keys of a list must end with a unique column so that every record has a distinct position
+type sortKey struct {
+	// column of the paginated query, or an expression over its columns. It must never be NULL
+	column string
+	// type the key values of a cursor are cast to
+	cast string
+	desc bool
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// pageQuery wraps a query without ORDER BY or LIMIT so that it returns the requested page of its rows ordered by the keys, which refer to the columns of the query by name.
+// Pages after a cursor only read rows positioned after its key, so they stay consistent while records are added
+func pageQuery(query string, args []any, keys []sortKey, page model.Page) (string, []any, error) {
+	var where string
+	if page.After != nil {
+		if len(page.After) != len(keys) {
+			return "", nil, ErrInvalidCursor
+		}
+		first := len(args) + 1
+		for _, value := range page.After {
+			args = append(args, value)
+		}
+		where = "WHERE " + keysetCondition(keys, first)
+	}
+
+	order := make([]string, len(keys))
+	for i, key := range keys {
+		order[i] = key.column
+		if key.desc {
+			order[i] += " DESC"
+		}
+	}
+
+	paged := "SELECT * FROM (" + query + ") page " + where + " ORDER BY " + strings.Join(order, ", ")
+	args = append(args, page.Limit)
+	paged += " LIMIT $" + placeholder(len(args))
+	if page.After == nil && page.Offset > 0 {
+		args = append(args, page.Offset)
+		paged += " OFFSET $" + placeholder(len(args))
+	}
+	return paged, args, nil
+}
+
+// keysetCondition matches the rows positioned after the cursor key bound from parameter first onwards.
+// Keys sorted in the same direction are compared as a row so that an index on them can be used
+func keysetCondition(keys []sortKey, first int) string {
+	values := make([]string, len(keys))
+	columns := make([]string, len(keys))
+	sameDirection := true
+	for i, key := range keys {
+		values[i] = "$" + placeholder(first+i) + "::" + key.cast
+		columns[i] = key.column
+		sameDirection = sameDirection && key.desc == keys[0].desc
+	}
+
+	if sameDirection {
+		operator := " > "
+		if keys[0].desc {
+			operator = " < "
+		}
+		return "(" + strings.Join(columns, ", ") + ")" + operator + "(" + strings.Join(values, ", ") + ")"
+	}
+
+	// (a, b, c) after (x, y, z) expands to a after x OR (a = x AND b after y) OR (a = x AND b = y AND c after z)
+	alternatives := make([]string, len(keys))
+	for i, key := range keys {
+		operator := " > "
+		if key.desc {
+			operator = " < "
+		}
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, columns[j]+" = "+values[j])
+		}
+		terms = append(terms, columns[i]+operator+values[i])
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// queryPage reads the requested page of the rows of a query without ORDER BY or LIMIT, scanning each row with scan. The rows across all pages are counted when the page asks for it
+func queryPage[T any](ctx context.Context, db *sql.DB, query string, args []any, keys []sortKey, page model.Page, scan func(row scanner) (T, error)) (*model.Paged[T], error) {
+	result := &model.Paged[T]{Items: make([]T, 0, page.Limit)}
+	if page.IncludeTotal {
+		var total int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+query+") total", args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		result.Total = &total
+	}
+
+	paged, args, err := pageQuery(query, args, keys, page)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, paged, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
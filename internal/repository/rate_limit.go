@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"chow/internal/model"
+)
+
+// RateLimitRepository keeps the token buckets of rate limited clients in the database so that every instance enforces the same limits
+type RateLimitRepository struct {
+	db *sql.DB
+}
+
+func NewRateLimitRepository(db *sql.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// UpdateBucket applies the update to the bucket with the key while holding a lock on its row. Buckets that do not exist start as the initial bucket
+func (r *RateLimitRepository) UpdateBucket(ctx context.Context, key string, initial model.TokenBucket, update func(bucket *model.TokenBucket)) error {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// create the bucket first so that concurrent requests of a new client wait on the same row
+	query := `
+		INSERT INTO rate_limit_buckets(key, tokens, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT(key) DO NOTHING
+		`
+	if _, err := tx.ExecContext(ctx, query, key, initial.Tokens, initial.UpdatedAt); err != nil {
+		return err
+	}
+
+	var bucket model.TokenBucket
+	query = `SELECT tokens, updated_at FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&bucket.Tokens, &bucket.UpdatedAt); err != nil {
+		return err
+	}
+
+	update(&bucket)
+
+	query = `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`
+	if _, err := tx.ExecContext(ctx, query, key, bucket.Tokens, bucket.UpdatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteIdleBuckets removes the buckets that have not been updated since the given time
+func (r *RateLimitRepository) DeleteIdleBuckets(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before)
+	return err
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// RecommendationRepository handles database operations for joint recommendations
+type RecommendationRepository struct {
+	db *sql.DB
+}
+
+// NewRecommendationRepository creates a new recommendation repository
+func NewRecommendationRepository(db *sql.DB) *RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// recommendedJointColumns selects a recommended joint along with its distance from $2 (lon) and $3 (lat). The joints table should be aliased as j
+const recommendedJointColumns = `
+	j.id, j.name, j.latitude, j.longitude, j.description, j.is_approved, j.visibility, j.category, j.creator_id, j.photo_url,
+	j.upvotes, j.downvotes, j.score, j.created_at, j.updated_at, ST_Distance(j.location, ST_Point($2, $3)::GEOGRAPHY) AS distance
+`
+
+// RecomputeSimilarities replaces the similarities between joints with the cosine similarity of the preferences of users who interacted with both.
+// Only the given number of most similar joints with at least minSupport common users are kept for each joint. It reports false without computing anything when another instance is already recomputing them
+func (r *RecommendationRepository) RecomputeSimilarities(ctx context.Context, neighbours, minSupport int) (int64, bool, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('joint_similarities'))`).Scan(&locked); err != nil {
+		return 0, false, err
+	}
+	if !locked {
+		return 0, false, nil
+	}
+
+	// the previous similarities remain visible to readers until the transaction commits
+	if _, err := tx.ExecContext(ctx, `DELETE FROM joint_similarities`); err != nil {
+		return 0, false, err
+	}
+
+	query := `
+		WITH preferences AS (
+			SELECT p.user_id, p.joint_id, p.rating
+			FROM joint_preferences p
+			JOIN joints j
+			ON j.id = p.joint_id
+			WHERE j.deleted_at IS NULL
+		),
+		norms AS (
+			SELECT joint_id, SQRT(SUM(rating * rating)) AS norm
+			FROM preferences
+			GROUP BY joint_id
+		)
+		INSERT INTO joint_similarities(joint_id, similar_joint_id, similarity, support)
+		SELECT joint_id, similar_joint_id, similarity, support
+		FROM (
+			SELECT
+				a.joint_id, b.joint_id AS similar_joint_id,
+				SUM(a.rating * b.rating) / (na.norm * nb.norm) AS similarity,
+				COUNT(*) AS support,
+				ROW_NUMBER() OVER (PARTITION BY a.joint_id ORDER BY SUM(a.rating * b.rating) / (na.norm * nb.norm) DESC) AS rank
+			FROM preferences a
+			JOIN preferences b
+			ON b.user_id = a.user_id AND b.joint_id <> a.joint_id
+			JOIN norms na
+			ON na.joint_id = a.joint_id
+			JOIN norms nb
+			ON nb.joint_id = b.joint_id
+			GROUP BY a.joint_id, b.joint_id, na.norm, nb.norm
+			HAVING COUNT(*) >= $2 AND SUM(a.rating * b.rating) > 0
+		) similarities
+		WHERE rank <= $1
+	`
+	result, err := tx.ExecContext(ctx, query, neighbours, minSupport)
+	if err != nil {
+		return 0, false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, false, err
+	}
+	return rows, true, nil
+}
+
+// CountLikedJoints returns the number of joints a user has a positive preference for
+func (r *RecommendationRepository) CountLikedJoints(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM joint_preferences WHERE user_id = $1 AND rating > 0`, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetRecommended ranks the joints within the radius that a user has neither voted on nor visited by their similarity to the joints the user liked
+// and the user's affinity for their category, weighted by affinityWeight. Joints with neither are left out
+func (r *RecommendationRepository) GetRecommended(ctx context.Context, userID uuid.UUID, cord model.Coordinate, radius, affinityWeight float64, page model.Page) (*model.Paged[*model.RecommendedJoint], error) {
+	query := `
+		WITH preferences AS (
+			SELECT joint_id, rating FROM joint_preferences WHERE user_id = $1
+		),
+		candidates AS (
+			SELECT
+				s.similar_joint_id AS joint_id, SUM(s.similarity * p.rating) AS similarity,
+				-- the liked joint contributing the most explains the recommendation
+				(ARRAY_AGG(s.joint_id ORDER BY s.similarity * p.rating DESC))[1] AS because_of
+			FROM joint_similarities s
+			JOIN preferences p
+			ON p.joint_id = s.joint_id
+			WHERE p.rating > 0
+			GROUP BY s.similar_joint_id
+		),
+		affinities AS (
+			-- share of the user's preferences that went to each category
+			SELECT j.category, SUM(p.rating) / SUM(SUM(ABS(p.rating))) OVER () AS affinity
+			FROM preferences p
+			JOIN joints j
+			ON j.id = p.joint_id
+			GROUP BY j.category
+		)
+		SELECT ` + recommendedJointColumns + `, COALESCE(c.similarity, 0) AS similarity, COALESCE(a.affinity, 0) AS category_affinity,
+			b.id AS because_of_id, b.name AS because_of_name,
+			(COALESCE(c.similarity, 0) + $5 * COALESCE(a.affinity, 0))::DOUBLE PRECISION AS relevance
+		FROM joints j
+		LEFT JOIN candidates c
+		ON c.joint_id = j.id
+		LEFT JOIN affinities a
+		ON a.category = j.category
+		LEFT JOIN joints b
+		ON b.id = c.because_of
+		WHERE j.is_approved = TRUE AND j.visibility = 'visible' AND j.deleted_at IS NULL
+		AND ST_DWithin(j.location, ST_Point($2, $3)::GEOGRAPHY, $4)
+		AND NOT EXISTS (SELECT 1 FROM preferences p WHERE p.joint_id = j.id)
+		AND (c.similarity > 0 OR a.affinity > 0)
+	`
+	keys := []sortKey{
+		{column: "relevance", cast: "DOUBLE PRECISION", desc: true},
+		{column: "score", cast: "DOUBLE PRECISION", desc: true},
+		{column: "id", cast: "UUID"},
+	}
+	args := []any{userID, cord.Longitude, cord.Latitude, radius, affinityWeight}
+	return queryPage(ctx, r.db, query, args, keys, page, scanRecommendedJoint)
+}
+
+// GetPopularNearby ranks the joints within the radius that a user has neither voted on nor visited by their score
+func (r *RecommendationRepository) GetPopularNearby(ctx context.Context, userID uuid.UUID, cord model.Coordinate, radius float64, page model.Page) (*model.Paged[*model.RecommendedJoint], error) {
+	query := `
+		SELECT ` + recommendedJointColumns + `, 0::DOUBLE PRECISION AS similarity, 0::DOUBLE PRECISION AS category_affinity,
+			NULL::UUID AS because_of_id, NULL AS because_of_name, 0::DOUBLE PRECISION AS relevance
+		FROM joints j
+		WHERE j.is_approved = TRUE AND j.visibility = 'visible' AND j.deleted_at IS NULL
+		AND ST_DWithin(j.location, ST_Point($2, $3)::GEOGRAPHY, $4)
+		AND NOT EXISTS (SELECT 1 FROM joint_preferences p WHERE p.user_id = $1 AND p.joint_id = j.id)
+	`
+	keys := []sortKey{
+		{column: "score", cast: "DOUBLE PRECISION", desc: true},
+		{column: "distance", cast: "DOUBLE PRECISION"},
+		{column: "id", cast: "UUID"},
+	}
+	args := []any{userID, cord.Longitude, cord.Latitude, radius}
+	return queryPage(ctx, r.db, query, args, keys, page, scanRecommendedJoint)
+}
+
+// scanRecommendedJoint scans a row of recommendedJointColumns followed by the similarity, category affinity, the id and name of the joint explaining the recommendation and its relevance
+func scanRecommendedJoint(row scanner) (*model.RecommendedJoint, error) {
+	var joint model.RecommendedJoint
+	var distance float64
+	if err := row.Scan(
+		&joint.ID,
+		&joint.Name,
+		&joint.Latitude,
+		&joint.Longitude,
+		&joint.Description,
+		&joint.IsApproved,
+		&joint.Visibility,
+		&joint.Category,
+		&joint.CreatorID,
+		&joint.PhotoURL,
+		&joint.UpVotes,
+		&joint.DownVotes,
+		&joint.Score,
+		&joint.CreatedAt,
+		&joint.UpdatedAt,
+		&distance,
+		&joint.Similarity,
+		&joint.CategoryAffinity,
+		&joint.BecauseOfID,
+		&joint.BecauseOfName,
+		&joint.Relevance,
+	); err != nil {
+		return nil, err
+	}
+	joint.Distance = &distance
+	return &joint, nil
+}
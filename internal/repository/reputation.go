@@ -0,0 +1,243 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// ReputationRepository handles database operations for the reputation ledger, badges and leaderboards
+type ReputationRepository struct {
+	db *sql.DB
+}
+
+// NewReputationRepository creates a new reputation repository
+func NewReputationRepository(db *sql.DB) *ReputationRepository {
+	return &ReputationRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *ReputationRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// AddEntry records an entry in the ledger of a user and applies its points to their reputation. Entries for a source and reason that have already been recorded are ignored.
+// It returns the reputation of the user before and after the entry and reports whether the entry was recorded
+func (r *ReputationRepository) AddEntry(ctx context.Context, tx *sql.Tx, data *model.ReputationEntry) (int, int, bool, error) {
+	query := `
+		INSERT INTO reputation_events(user_id, reason, points, joint_id, source_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(source_id, user_id, reason) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, query, data.UserID, data.Reason, data.Points, data.JointID, data.SourceID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return 0, 0, false, ErrNotFound
+		}
+		return 0, 0, false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	var reputation int
+	if rows == 0 {
+		if err := tx.QueryRowContext(ctx, `SELECT reputation FROM users WHERE id = $1`, data.UserID).Scan(&reputation); err != nil {
+			return 0, 0, false, err
+		}
+		return reputation, reputation, false, nil
+	}
+
+	query = `UPDATE users SET reputation = reputation + $1 WHERE id = $2 RETURNING reputation`
+	if err := tx.QueryRowContext(ctx, query, data.Points, data.UserID).Scan(&reputation); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, false, ErrNotFound
+		}
+		return 0, 0, false, err
+	}
+	return reputation - data.Points, reputation, true, nil
+}
+
+// GetReputation retrieves the reputation of a user
+func (r *ReputationRepository) GetReputation(ctx context.Context, userID uuid.UUID) (int, error) {
+	var reputation int
+	if err := r.db.QueryRowContext(ctx, `SELECT reputation FROM users WHERE id = $1`, userID).Scan(&reputation); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return reputation, nil
+}
+
+// GetUserEntries retrieves the ledger of a user, most recent first
+func (r *ReputationRepository) GetUserEntries(ctx context.Context, userID uuid.UUID, page model.Page) (*model.Paged[*model.ReputationEntry], error) {
+	query := `
+		SELECT id, user_id, reason, points, joint_id, source_id, created_at
+		FROM reputation_events
+		WHERE user_id = $1
+	`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{userID}, keys, page, func(row scanner) (*model.ReputationEntry, error) {
+		var entry model.ReputationEntry
+		if err := row.Scan(
+			&entry.ID,
+			&entry.UserID,
+			&entry.Reason,
+			&entry.Points,
+			&entry.JointID,
+			&entry.SourceID,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		return &entry, nil
+	})
+}
+
+// AwardBadge awards a badge to a user. It reports whether the badge was awarded, which is not the case when the user already has it
+func (r *ReputationRepository) AwardBadge(ctx context.Context, tx *sql.Tx, userID uuid.UUID, badge model.Badge) (bool, error) {
+	query := `INSERT INTO user_badges(user_id, badge) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	result, err := tx.ExecContext(ctx, query, userID, badge)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return false, ErrNotFound
+		}
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// GetUserBadges retrieves the badges of a user in the order they were awarded
+func (r *ReputationRepository) GetUserBadges(ctx context.Context, userID uuid.UUID) ([]*model.UserBadge, error) {
+	query := `SELECT badge, awarded_at FROM user_badges WHERE user_id = $1 ORDER BY awarded_at`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	badges := make([]*model.UserBadge, 0)
+	for rows.Next() {
+		var badge model.UserBadge
+		if err := rows.Scan(&badge.Badge, &badge.AwardedAt); err != nil {
+			return nil, err
+		}
+		badge.Name = model.BadgeNames[badge.Badge]
+		badges = append(badges, &badge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return badges, nil
+}
+
+// CountApprovedJoints returns the number of approved joints created by a user that have not been deleted
+func (r *ReputationRepository) CountApprovedJoints(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM joints WHERE creator_id = $1 AND is_approved = TRUE AND deleted_at IS NULL`
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountCheckins returns the number of check-ins of a user
+func (r *ReputationRepository) CountCheckins(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM checkins WHERE user_id = $1`, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetCities retrieves the cities leaderboards are available for
+func (r *ReputationRepository) GetCities(ctx context.Context) ([]*model.City, error) {
+	query := `SELECT slug, name, radius, ST_Y(center::geometry), ST_X(center::geometry) FROM cities ORDER BY name`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cities := make([]*model.City, 0)
+	for rows.Next() {
+		var city model.City
+		if err := rows.Scan(&city.Slug, &city.Name, &city.Radius, &city.Latitude, &city.Longitude); err != nil {
+			return nil, err
+		}
+		cities = append(cities, &city)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return cities, nil
+}
+
+// CityExists reports whether a city with the given slug exists
+func (r *ReputationRepository) CityExists(ctx context.Context, slug string) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM cities WHERE slug = $1)`, slug).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// GetLeaderboard ranks users by their reputation. Users without reputation are left out
+func (r *ReputationRepository) GetLeaderboard(ctx context.Context, page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY reputation DESC) AS rank, id AS user_id, username, reputation AS points, reputation
+		FROM users
+		WHERE reputation > 0
+	`
+	return r.getLeaderboard(ctx, query, nil, page)
+}
+
+// GetCityLeaderboard ranks users by the points they earned for joints in a city. Users without points in the city are left out
+func (r *ReputationRepository) GetCityLeaderboard(ctx context.Context, slug string, page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY SUM(e.points) DESC) AS rank, u.id AS user_id, u.username, SUM(e.points) AS points, u.reputation
+		FROM reputation_events e
+		JOIN joints j
+		ON j.id = e.joint_id
+		JOIN cities c
+		ON c.slug = $1 AND ST_DWithin(j.location, c.center, c.radius)
+		JOIN users u
+		ON u.id = e.user_id
+		GROUP BY u.id, u.username, u.reputation
+		HAVING SUM(e.points) > 0
+	`
+	return r.getLeaderboard(ctx, query, []any{slug}, page)
+}
+
+// GetModeratorCandidates ranks regular users with at least the given reputation, who can be nominated as moderators
+func (r *ReputationRepository) GetModeratorCandidates(ctx context.Context, minReputation int, page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY reputation DESC) AS rank, id AS user_id, username, reputation AS points, reputation
+		FROM users
+		WHERE role = 'user' AND reputation >= $1
+	`
+	return r.getLeaderboard(ctx, query, []any{minReputation}, page)
+}
+
+// getLeaderboard reads a page of a leaderboard query selecting the rank, user_id, username, points and reputation of each entry. Ties in points are ordered by username
+func (r *ReputationRepository) getLeaderboard(ctx context.Context, query string, args []any, page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+	keys := []sortKey{{column: "points", cast: "BIGINT", desc: true}, {column: "username", cast: "TEXT"}, {column: "user_id", cast: "UUID"}}
+	return queryPage(ctx, r.db, query, args, keys, page, func(row scanner) (*model.LeaderboardEntry, error) {
+		var entry model.LeaderboardEntry
+		var reputation int
+		if err := row.Scan(&entry.Rank, &entry.UserID, &entry.Username, &entry.Points, &reputation); err != nil {
+			return nil, err
+		}
+		entry.TrustLevel = model.TrustLevelFor(reputation)
+		return &entry, nil
+	})
+}
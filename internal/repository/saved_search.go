@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearchRepository handles database operations for saved searches and their matches
+type SavedSearchRepository struct {
+	db *sql.DB
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(db *sql.DB) *SavedSearchRepository {
+	return &SavedSearchRepository{db: db}
+}
+
+const savedSearchColumns = `
+	s.id, s.user_id, s.name, ST_Y(s.center::geometry), ST_X(s.center::geometry), s.radius, ST_AsGeoJSON(s.area),
+	s.categories, s.keywords, s.delivery, s.created_at, s.updated_at`
+
+// Create adds a saved search. The area is taken from the polygon when it is set, otherwise from the center and radius
+func (r *SavedSearchRepository) Create(ctx context.Context, data *model.SavedSearch) (*model.SavedSearch, error) {
+	categories, err := json.Marshal(data.Categories)
+	if err != nil {
+		return nil, err
+	}
+	keywords, err := json.Marshal(data.Keywords)
+	if err != nil {
+		return nil, err
+	}
+
+	var lon, lat any
+	if data.Center != nil {
+		lon, lat = data.Center.Longitude, data.Center.Latitude
+	}
+	var area any
+	if len(data.Polygon) > 0 {
+		area = polygonWKT(data.Polygon)
+	}
+
+	query := `
+		WITH s AS (
+			INSERT INTO saved_searches(user_id, name, center, radius, area, categories, keywords, delivery)
+			VALUES ($1, $2, CASE WHEN $3::float8 IS NULL THEN NULL ELSE ST_Point($3, $4)::geography END, $5, ST_GeogFromText($6), $7, $8, $9)
+			RETURNING *
+		)
+		SELECT ` + savedSearchColumns + ` FROM s
+	`
+	return scanSavedSearch(r.db.QueryRowContext(ctx, query, data.UserID, data.Name, lon, lat, data.Radius, area, string(categories), string(keywords), data.Delivery))
+}
+
+// GetByID retrieves a saved search of a user
+func (r *SavedSearchRepository) GetByID(ctx context.Context, userID, id uuid.UUID) (*model.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.id = $1 AND s.user_id = $2`
+	return scanSavedSearch(r.db.QueryRowContext(ctx, query, id, userID))
+}
+
+// GetUserSearches retrieves the saved searches of a user, newest first
+func (r *SavedSearchRepository) GetUserSearches(ctx context.Context, userID uuid.UUID) ([]*model.SavedSearch, error) {
+	query := `SELECT ` + savedSearchColumns + ` FROM saved_searches s WHERE s.user_id = $1 ORDER BY s.created_at DESC`
+	return r.query(ctx, query, userID)
+}
+
+// CountUserSearches returns the number of saved searches of a user
+func (r *SavedSearchRepository) CountUserSearches(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM saved_searches WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// DeleteByID removes a saved search of a user along with its matches
+func (r *SavedSearchRepository) DeleteByID(ctx context.Context, userID, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetMatchingSearches retrieves the saved searches of other users matching a joint by area, category and keywords
+func (r *SavedSearchRepository) GetMatchingSearches(ctx context.Context, jointID uuid.UUID) ([]*model.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches s
+		JOIN joints j ON j.id = $1
+		WHERE s.user_id <> j.creator_id
+			AND (ST_DWithin(s.center, j.location, s.radius) OR ST_Covers(s.area, j.location))
+			AND (jsonb_array_length(s.categories) = 0 OR s.categories @> jsonb_build_array(j.category))
+			AND (jsonb_array_length(s.keywords) = 0 OR EXISTS (
+				SELECT 1 FROM jsonb_array_elements_text(s.keywords) AS k(keyword)
+				WHERE strpos(lower(j.name), lower(k.keyword)) > 0 OR strpos(lower(COALESCE(j.description, '')), lower(k.keyword)) > 0
+			))
+	`
+	return r.query(ctx, query, jointID)
+}
+
+// CreateMatch records that a joint matched a saved search. A joint that already matched the search is ignored and ErrAlreadyExist is returned
+func (r *SavedSearchRepository) CreateMatch(ctx context.Context, savedSearchID, jointID uuid.UUID, notified bool) error {
+	query := `
+		INSERT INTO saved_search_matches(saved_search_id, joint_id, notified_at)
+		VALUES ($1, $2, CASE WHEN $3 THEN NOW() END)
+		ON CONFLICT DO NOTHING
+	`
+	result, err := r.db.ExecContext(ctx, query, savedSearchID, jointID, notified)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAlreadyExist
+	}
+	return nil
+}
+
+// GetMatches retrieves the joints matched by a saved search, newest first. Joints that have since been removed are skipped
+func (r *SavedSearchRepository) GetMatches(ctx context.Context, savedSearchID uuid.UUID, page model.Page) (*model.Paged[*model.SavedSearchMatch], error) {
+	query := `
+		SELECT m.saved_search_id, m.matched_at, m.notified_at,
+			j.id, j.name, j.latitude, j.longitude, j.description, j.is_approved, j.visibility, j.category, j.creator_id, j.photo_url, j.upvotes, j.downvotes, j.score, j.created_at, j.updated_at
+		FROM saved_search_matches m
+		JOIN joints j ON j.id = m.joint_id
+		WHERE m.saved_search_id = $1 AND j.is_approved AND j.visibility = 'visible' AND j.deleted_at IS NULL
+	`
+	keys := []sortKey{{column: "matched_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{savedSearchID}, keys, page, func(row scanner) (*model.SavedSearchMatch, error) {
+		var match model.SavedSearchMatch
+		var joint model.Joint
+		if err := row.Scan(
+			&match.SavedSearchID,
+			&match.MatchedAt,
+			&match.NotifiedAt,
+			&joint.ID,
+			&joint.Name,
+			&joint.Latitude,
+			&joint.Longitude,
+			&joint.Description,
+			&joint.IsApproved,
+			&joint.Visibility,
+			&joint.Category,
+			&joint.CreatorID,
+			&joint.PhotoURL,
+			&joint.UpVotes,
+			&joint.DownVotes,
+			&joint.Score,
+			&joint.CreatedAt,
+			&joint.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		match.Joint = &joint
+		return &match, nil
+	})
+}
+
+// GetPendingDigestMatches retrieves the matches of email digest searches that have not been sent, grouped by user
+func (r *SavedSearchRepository) GetPendingDigestMatches(ctx context.Context, matchedBefore time.Time) ([]*model.DigestMatch, error) {
+	query := `
+		SELECT u.id, u.email, u.username, s.id, s.name, j.id, j.name, m.matched_at
+		FROM saved_search_matches m
+		JOIN saved_searches s ON s.id = m.saved_search_id
+		JOIN users u ON u.id = s.user_id
+		JOIN joints j ON j.id = m.joint_id
+		WHERE m.notified_at IS NULL AND s.delivery = 'email_digest' AND m.matched_at <= $1
+			AND j.is_approved AND j.visibility = 'visible' AND j.deleted_at IS NULL
+		ORDER BY u.id, s.id, m.matched_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, matchedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []*model.DigestMatch
+	for rows.Next() {
+		var match model.DigestMatch
+		if err := rows.Scan(
+			&match.UserID,
+			&match.Email,
+			&match.Username,
+			&match.SavedSearchID,
+			&match.SavedSearchName,
+			&match.JointID,
+			&match.JointName,
+			&match.MatchedAt,
+		); err != nil {
+			return nil, err
+		}
+		matches = append(matches, &match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// MarkDigestSent marks the pending email digest matches of a user up to the given time as notified
+func (r *SavedSearchRepository) MarkDigestSent(ctx context.Context, userID uuid.UUID, matchedBefore time.Time) error {
+	query := `
+		UPDATE saved_search_matches m
+		SET notified_at = NOW()
+		FROM saved_searches s
+		WHERE s.id = m.saved_search_id AND s.user_id = $1 AND s.delivery = 'email_digest'
+			AND m.notified_at IS NULL AND m.matched_at <= $2
+	`
+	_, err := r.db.ExecContext(ctx, query, userID, matchedBefore)
+	return err
+}
+
+func (r *SavedSearchRepository) query(ctx context.Context, query string, args ...any) ([]*model.SavedSearch, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []*model.SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// scanSavedSearch scans a saved search from a row selected with savedSearchColumns
+func scanSavedSearch(row interface{ Scan(...any) error }) (*model.SavedSearch, error) {
+	var search model.SavedSearch
+	var lat, lon sql.NullFloat64
+	var area sql.NullString
+	var categories, keywords []byte
+	err := row.Scan(
+		&search.ID,
+		&search.UserID,
+		&search.Name,
+		&lat,
+		&lon,
+		&search.Radius,
+		&area,
+		&categories,
+		&keywords,
+		&search.Delivery,
+		&search.CreatedAt,
+		&search.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	if lat.Valid && lon.Valid {
+		search.Center = &model.Coordinate{Latitude: lat.Float64, Longitude: lon.Float64}
+	}
+	if area.Valid {
+		polygon, err := parsePolygon(area.String)
+		if err != nil {
+			return nil, err
+		}
+		search.Polygon = polygon
+	}
+	if err := json.Unmarshal(categories, &search.Categories); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(keywords, &search.Keywords); err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+// polygonWKT returns the well-known text of a polygon, closing the ring when the last point differs from the first
+func polygonWKT(points []model.Coordinate) string {
+	if points[0] != points[len(points)-1] {
+		points = append(slices.Clone(points), points[0])
+	}
+
+	coords := make([]string, 0, len(points))
+	for _, p := range points {
+		coords = append(coords, fmt.Sprintf("%f %f", p.Longitude, p.Latitude))
+	}
+	return "SRID=4326;POLYGON((" + strings.Join(coords, ", ") + "))"
+}
+
+// parsePolygon reads the outer ring of a GeoJSON polygon without the closing point
+func parsePolygon(geoJSON string) ([]model.Coordinate, error) {
+	var polygon struct {
+		Coordinates [][][2]float64 `json:"coordinates"`
+	}
+	if err := json.Unmarshal([]byte(geoJSON), &polygon); err != nil {
+		return nil, err
+	}
+	if len(polygon.Coordinates) == 0 || len(polygon.Coordinates[0]) == 0 {
+		return nil, nil
+	}
+
+	ring := polygon.Coordinates[0]
+	points := make([]model.Coordinate, 0, len(ring)-1)
+	for _, c := range ring[:len(ring)-1] {
+		points = append(points, model.Coordinate{Latitude: c[1], Longitude: c[0]})
+	}
+	return points, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// SecurityRepository handles database operations for login attempts and security events
+type SecurityRepository struct {
+	db *sql.DB
+}
+
+func NewSecurityRepository(db *sql.DB) *SecurityRepository {
+	return &SecurityRepository{db: db}
+}
+
+// GetLoginAttempt returns the failed logins to an email
+func (r *SecurityRepository) GetLoginAttempt(ctx context.Context, email string) (*model.LoginAttempt, error) {
+	query := `
+		SELECT email, failed_attempts, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE email = $1
+		`
+	attempt, err := scanLoginAttempt(r.db.QueryRowContext(ctx, query, email))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// RecordFailedLogin adds a failed login to an email. The count starts over when the previous failure is older than resetBefore or the lockout has expired
+func (r *SecurityRepository) RecordFailedLogin(ctx context.Context, email string, at, resetBefore time.Time) (*model.LoginAttempt, error) {
+	query := `
+		INSERT INTO login_attempts(email, failed_attempts, last_failed_at)
+		VALUES ($1, 1, $2)
+		ON CONFLICT(email) DO UPDATE SET
+			failed_attempts = CASE
+				WHEN login_attempts.last_failed_at < $3 OR login_attempts.locked_until <= $2 THEN 1
+				ELSE login_attempts.failed_attempts + 1
+			END,
+			locked_until = CASE WHEN login_attempts.locked_until <= $2 THEN NULL ELSE login_attempts.locked_until END,
+			unlock_token_hash = CASE WHEN login_attempts.locked_until <= $2 THEN NULL ELSE login_attempts.unlock_token_hash END,
+			last_failed_at = $2
+		RETURNING email, failed_attempts, last_failed_at, locked_until
+		`
+	return scanLoginAttempt(r.db.QueryRowContext(ctx, query, email, at, resetBefore))
+}
+
+// Lock locks an email until the given time. The unlock token hash is only set for emails of registered accounts.
+// It reports false when the email is already locked so that the owner is only alerted once
+func (r *SecurityRepository) Lock(ctx context.Context, email string, until time.Time, unlockTokenHash *string) (bool, error) {
+	query := `
+		UPDATE login_attempts
+		SET locked_until = $2, unlock_token_hash = $3
+		WHERE email = $1 AND locked_until IS NULL
+		`
+	result, err := r.db.ExecContext(ctx, query, email, until, unlockTokenHash)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// Unlock clears the failed logins of the email locked with the unlock token and returns the email. ErrNotFound is returned when no email is locked with the token
+func (r *SecurityRepository) Unlock(ctx context.Context, unlockTokenHash string) (string, error) {
+	query := `
+		DELETE FROM login_attempts
+		WHERE unlock_token_hash = $1 AND locked_until > NOW()
+		RETURNING email
+		`
+	var email string
+	if err := r.db.QueryRowContext(ctx, query, unlockTokenHash).Scan(&email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return email, nil
+}
+
+// ClearLoginAttempts forgets the failed logins to an email
+func (r *SecurityRepository) ClearLoginAttempts(ctx context.Context, email string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE email = $1`, email)
+	return err
+}
+
+// DeleteStaleLoginAttempts removes the failed logins last made before the given time from emails that are not locked
+func (r *SecurityRepository) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) error {
+	query := `
+		DELETE FROM login_attempts
+		WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < NOW())
+		`
+	_, err := r.db.ExecContext(ctx, query, before)
+	return err
+}
+
+// CountFailedLoginsByIP returns the number of failed logins from an IP address since the given time and when the earliest of them was made
+func (r *SecurityRepository) CountFailedLoginsByIP(ctx context.Context, ipAddress string, since time.Time) (int, *time.Time, error) {
+	query := `
+		SELECT COUNT(*), MIN(created_at)
+		FROM security_events
+		WHERE type = $1 AND ip_address = $2 AND created_at > $3
+		`
+	var (
+		count    int
+		earliest sql.NullTime
+	)
+	if err := r.db.QueryRowContext(ctx, query, model.LoginFailed, ipAddress, since).Scan(&count, &earliest); err != nil {
+		return 0, nil, err
+	}
+	if !earliest.Valid {
+		return count, nil, nil
+	}
+	return count, &earliest.Time, nil
+}
+
+// CreateEvent records a security event
+func (r *SecurityRepository) CreateEvent(ctx context.Context, data *model.SecurityEvent) (*model.SecurityEvent, error) {
+	query := `
+		INSERT INTO security_events(user_id, type, ip_address, user_agent, device_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, type, ip_address, user_agent, device_id, created_at
+		`
+	return scanSecurityEvent(r.db.QueryRowContext(ctx, query, data.UserID, data.Type, data.IPAddress, data.UserAgent, data.DeviceID))
+}
+
+// GetUserEvents returns the requested page of a user's security events, newest first
+func (r *SecurityRepository) GetUserEvents(ctx context.Context, userID uuid.UUID, page model.Page) (*model.Paged[*model.SecurityEvent], error) {
+	query := `
+		SELECT id, user_id, type, ip_address, user_agent, device_id, created_at
+		FROM security_events
+		WHERE user_id = $1
+		`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{userID}, keys, page, scanSecurityEvent)
+}
+
+// DeleteEventsBefore removes the security events recorded before the given time
+func (r *SecurityRepository) DeleteEventsBefore(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM security_events WHERE created_at < $1`, before)
+	return err
+}
+
+func scanLoginAttempt(row scanner) (*model.LoginAttempt, error) {
+	var attempt model.LoginAttempt
+	if err := row.Scan(&attempt.Email, &attempt.FailedAttempts, &attempt.LastFailedAt, &attempt.LockedUntil); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func scanSecurityEvent(row scanner) (*model.SecurityEvent, error) {
+	var event model.SecurityEvent
+	if err := row.Scan(
+		&event.ID,
+		&event.UserID,
+		&event.Type,
+		&event.IPAddress,
+		&event.UserAgent,
+		&event.DeviceID,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
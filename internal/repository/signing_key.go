@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"chow/internal/model"
+)
+
+// SigningKeyRepository handles database operations for token signing keys
+type SigningKeyRepository struct {
+	db *sql.DB
+}
+
+func NewSigningKeyRepository(db *sql.DB) *SigningKeyRepository {
+	return &SigningKeyRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *SigningKeyRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// GetUsable returns the active keys and the keys retired after the given time, newest first
+func (r *SigningKeyRepository) GetUsable(ctx context.Context, retiredAfter time.Time) ([]model.SigningKey, error) {
+	query := `
+		SELECT kid, algorithm, private_key, public_key, created_at, retired_at
+		FROM signing_keys
+		WHERE retired_at IS NULL OR retired_at > $1
+		ORDER BY created_at DESC, kid
+		`
+	rows, err := r.db.QueryContext(ctx, query, retiredAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []model.SigningKey{}
+	for rows.Next() {
+		key, err := scanSigningKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CreateIfNoneActive adds a key unless there is already an active one. It reports whether the key was added
+func (r *SigningKeyRepository) CreateIfNoneActive(ctx context.Context, data *model.SigningKey) (bool, error) {
+	query := `
+		INSERT INTO signing_keys(kid, algorithm, private_key, public_key)
+		SELECT $1, $2, $3, $4
+		WHERE NOT EXISTS (SELECT 1 FROM signing_keys WHERE retired_at IS NULL)
+		`
+	result, err := r.db.ExecContext(ctx, query, data.KID, data.Algorithm, data.PrivateKey, data.PublicKey)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// Create adds a key
+func (r *SigningKeyRepository) Create(ctx context.Context, tx *sql.Tx, data *model.SigningKey) (*model.SigningKey, error) {
+	query := `
+		INSERT INTO signing_keys(kid, algorithm, private_key, public_key)
+		VALUES ($1, $2, $3, $4)
+		RETURNING kid, algorithm, private_key, public_key, created_at, retired_at
+		`
+	key, err := scanSigningKey(tx.QueryRowContext(ctx, query, data.KID, data.Algorithm, data.PrivateKey, data.PublicKey))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+// RetireActive retires the active keys so that they no longer sign tokens. It returns the number of keys retired
+func (r *SigningKeyRepository) RetireActive(ctx context.Context, tx *sql.Tx) (int64, error) {
+	result, err := tx.ExecContext(ctx, `UPDATE signing_keys SET retired_at = NOW() WHERE retired_at IS NULL`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteRetiredBefore removes the keys retired before the given time. It returns the number of keys removed
+func (r *SigningKeyRepository) DeleteRetiredBefore(ctx context.Context, tx *sql.Tx, before time.Time) (int64, error) {
+	result, err := tx.ExecContext(ctx, `DELETE FROM signing_keys WHERE retired_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func scanSigningKey(row scanner) (*model.SigningKey, error) {
+	var key model.SigningKey
+	if err := row.Scan(
+		&key.KID,
+		&key.Algorithm,
+		&key.PrivateKey,
+		&key.PublicKey,
+		&key.CreatedAt,
+		&key.RetiredAt,
+	); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
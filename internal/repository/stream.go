@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// StreamRepository publishes and receives real-time updates through postgres LISTEN/NOTIFY so that every server instance sees every update
+type StreamRepository struct {
+	db *sql.DB
+}
+
+// NewStreamRepository creates a new stream repository
+func NewStreamRepository(db *sql.DB) *StreamRepository {
+	return &StreamRepository{db: db}
+}
+
+// Notify sends a payload to every connection listening on the channel. Payloads must be shorter than 8000 bytes
+func (r *StreamRepository) Notify(ctx context.Context, channel, payload string) error {
+	_, err := r.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, channel, payload)
+	return err
+}
+
+// Listen holds a dedicated connection listening on the channel and calls fn with every payload received. It blocks until the context is done or the connection fails
+func (r *StreamRepository) Listen(ctx context.Context, channel string, fn func(payload string)) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("listen requires a pgx connection")
+		}
+		pgxConn := stdConn.Conn()
+		// the connection is closed rather than returned to the pool while still listening
+		defer pgxConn.Close(context.Background())
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+			return err
+		}
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			fn(notification.Payload)
+		}
+	})
+}
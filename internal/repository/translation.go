@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// TranslationRepository handles database operations for joint translations
+type TranslationRepository struct {
+	db *sql.DB
+}
+
+func NewTranslationRepository(db *sql.DB) *TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *TranslationRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// GetLocalizations returns the default locale and translations of the given joints. Joints that do not exist are left out
+func (r *TranslationRepository) GetLocalizations(ctx context.Context, jointIDs []uuid.UUID) (map[uuid.UUID]*model.JointLocalization, error) {
+	ids := make([]string, 0, len(jointIDs))
+	for _, id := range jointIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `
+		SELECT j.id, j.locale, t.locale, t.name, t.description, t.updated_by, t.created_at, t.updated_at
+		FROM joints j
+		LEFT JOIN joint_translations t ON t.joint_id = j.id
+		WHERE j.id = ANY($1::uuid[])
+		ORDER BY j.id, t.locale
+		`
+	rows, err := r.db.QueryContext(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	localizations := make(map[uuid.UUID]*model.JointLocalization)
+	for rows.Next() {
+		var (
+			jointID       uuid.UUID
+			defaultLocale string
+			locale, name  sql.NullString
+			translation   model.JointTranslation
+			createdAt     sql.NullTime
+			updatedAt     sql.NullTime
+		)
+		if err := rows.Scan(&jointID, &defaultLocale, &locale, &name, &translation.Description, &translation.UpdatedBy, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+
+		localization, ok := localizations[jointID]
+		if !ok {
+			localization = &model.JointLocalization{DefaultLocale: defaultLocale}
+			localizations[jointID] = localization
+		}
+		// joints without translations have a single row without a translation
+		if !locale.Valid {
+			continue
+		}
+		translation.JointID = jointID
+		translation.Locale = locale.String
+		translation.Name = name.String
+		translation.CreatedAt = createdAt.Time
+		translation.UpdatedAt = updatedAt.Time
+		localization.Translations = append(localization.Translations, translation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return localizations, nil
+}
+
+// GetByJointID returns the translations of a joint ordered by locale
+func (r *TranslationRepository) GetByJointID(ctx context.Context, jointID uuid.UUID) ([]model.JointTranslation, error) {
+	query := `
+		SELECT joint_id, locale, name, description, updated_by, created_at, updated_at
+		FROM joint_translations
+		WHERE joint_id = $1
+		ORDER BY locale
+		`
+	rows, err := r.db.QueryContext(ctx, query, jointID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []model.JointTranslation{}
+	for rows.Next() {
+		translation, err := scanTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, *translation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// GetByLocale returns the translation of a joint in a locale
+func (r *TranslationRepository) GetByLocale(ctx context.Context, tx *sql.Tx, jointID uuid.UUID, locale string) (*model.JointTranslation, error) {
+	query := `
+		SELECT joint_id, locale, name, description, updated_by, created_at, updated_at
+		FROM joint_translations
+		WHERE joint_id = $1 AND locale = $2
+		`
+	translation, err := scanTranslation(tx.QueryRowContext(ctx, query, jointID, locale))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return translation, nil
+}
+
+// Upsert creates the translation of a joint in a locale or replaces the existing one
+func (r *TranslationRepository) Upsert(ctx context.Context, tx *sql.Tx, data *model.JointTranslation) (*model.JointTranslation, error) {
+	query := `
+		INSERT INTO joint_translations(joint_id, locale, name, description, updated_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT(joint_id, locale) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description, updated_by = EXCLUDED.updated_by, updated_at = NOW()
+		RETURNING joint_id, locale, name, description, updated_by, created_at, updated_at
+		`
+	translation, err := scanTranslation(tx.QueryRowContext(ctx, query, data.JointID, data.Locale, data.Name, data.Description, data.UpdatedBy))
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return translation, nil
+}
+
+// Delete removes the translation of a joint in a locale
+func (r *TranslationRepository) Delete(ctx context.Context, tx *sql.Tx, jointID uuid.UUID, locale string) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM joint_translations WHERE joint_id = $1 AND locale = $2`, jointID, locale)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanTranslation(row scanner) (*model.JointTranslation, error) {
+	var translation model.JointTranslation
+	if err := row.Scan(
+		&translation.JointID,
+		&translation.Locale,
+		&translation.Name,
+		&translation.Description,
+		&translation.UpdatedBy,
+		&translation.CreatedAt,
+		&translation.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
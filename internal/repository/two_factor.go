@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactorRepository handles database operations for two-factor authentication and recovery codes
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *TwoFactorRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// Get returns the two-factor authentication of a user, whether or not it is enabled
+func (r *TwoFactorRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error) {
+	query := `
+		SELECT user_id, secret, enabled_at, last_used_step, created_at
+		FROM user_two_factor
+		WHERE user_id = $1
+		`
+	var twoFactor model.TwoFactor
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&twoFactor.UserID,
+		&twoFactor.Secret,
+		&twoFactor.EnabledAt,
+		&twoFactor.LastUsedStep,
+		&twoFactor.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// SaveSecret sets the secret of a user who has not enabled two-factor authentication, replacing any secret from an earlier setup. ErrAlreadyExist is returned when it is enabled
+func (r *TwoFactorRepository) SaveSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := `
+		INSERT INTO user_two_factor(user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT(user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, last_used_step = NULL, created_at = NOW()
+		WHERE user_two_factor.enabled_at IS NULL
+		`
+	result, err := r.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrAlreadyExist
+	}
+	return nil
+}
+
+// Enable turns on two-factor authentication for a user with the time step of the code that confirmed it. ErrNotFound is returned when there is no secret to enable or it is already enabled
+func (r *TwoFactorRepository) Enable(ctx context.Context, tx *sql.Tx, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_two_factor
+		SET enabled_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NULL
+		`
+	return expectAffected(tx.ExecContext(ctx, query, userID, step))
+}
+
+// UseStep records the time step of an accepted code. ErrNotFound is returned when a code of that step or a later one was already used
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	query := `
+		UPDATE user_two_factor
+		SET last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND (last_used_step IS NULL OR last_used_step < $2)
+		`
+	return expectAffected(r.db.ExecContext(ctx, query, userID, step))
+}
+
+// Delete removes the two-factor authentication and recovery codes of a user
+func (r *TwoFactorRepository) Delete(ctx context.Context, tx *sql.Tx, userID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return expectAffected(tx.ExecContext(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID))
+}
+
+// ReplaceRecoveryCodes replaces the recovery codes of a user with the given hashes
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO two_factor_recovery_codes(user_id, code_hash)
+		SELECT $1, UNNEST($2::text[])
+		`
+	_, err := tx.ExecContext(ctx, query, userID, codeHashes)
+	return err
+}
+
+// UseRecoveryCode marks an unused recovery code of a user as used. ErrNotFound is returned when the user has no such unused code
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	query := `
+		UPDATE two_factor_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+		`
+	return expectAffected(r.db.ExecContext(ctx, query, userID, codeHash))
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes of a user
+func (r *TwoFactorRepository) CountRecoveryCodes(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `SELECT COUNT(*) FROM two_factor_recovery_codes WHERE user_id = $1 AND used_at IS NULL`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// expectAffected returns ErrNotFound when a statement changed no rows
+func expectAffected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -19,105 +19,150 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) Create(ctx context.Context, data *model.User) (*model.User, error) {
-	var user model.User
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *UserRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// Create adds a user. Users without a password are stored without one and can only sign in with a linked identity
+func (r *UserRepository) Create(ctx context.Context, tx *sql.Tx, data *model.User) (*model.User, error) {
 	query := `
-        INSERT INTO users(email, username, password, role)
-        VALUES ($1, $2, $3, $4)
-		RETURNING id, email, username, password, role, created_at, updated_at
+        INSERT INTO users(email, username, password, role, email_verified_at)
+        VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
     `
-	if err := r.db.QueryRowContext(ctx, query, data.Email, data.Username, data.Password, data.Role).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Username,
-		&user.Password,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	); err != nil {
+	user, err := scanUser(tx.QueryRowContext(ctx, query, data.Email, data.Username, nullableString(data.Password), data.Role, data.EmailVerifiedAt))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExist
+		}
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 // GetUserByEmailOrUsername retrieves a user by their email or username. The fallback is email if key does not match `username`
 func (r *UserRepository) GetUserByEmailOrUsername(ctx context.Context, key, value string) (*model.User, error) {
 	query := `
-		SELECT id, email, username, password, role, created_at, updated_at
+		SELECT id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
 		FROM users 
 		WHERE email = $1
 		`
 
 	if key == "username" {
 		query = `
-			SELECT id, email, username, password, role, created_at, updated_at
+			SELECT id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
 			FROM users 
 			WHERE username = $1
 		`
 	}
 
-	var user model.User
-	err := r.db.QueryRow(query, value).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Username,
-		&user.Password,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, value))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
 	query := `
-		SELECT id, email, username, password, role, created_at, updated_at
+		SELECT id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
 		FROM users  
 		WHERE id = $1
 		`
-	var user model.User
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Username,
-		&user.Password,
-		&user.Role,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
 func (r *UserRepository) UpdateByID(ctx context.Context, id uuid.UUID, data *model.User) (*model.User, error) {
-	var user model.User
 	query := `
 		UPDATE users
 		SET email = $1, username = $2, password = $3, role = $4, updated_at = NOW()
 		WHERE id = $5
-		RETURNING id, email, username, password, role, created_at, updated_at
+		RETURNING id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
     `
-	if err := r.db.QueryRowContext(ctx, query, data.Email, data.Username, data.Password, data.Role).Scan(
+	return scanUser(r.db.QueryRowContext(ctx, query, data.Email, data.Username, nullableString(data.Password), data.Role, id))
+}
+
+// GetByRoles returns every user with one of the given roles
+func (r *UserRepository) GetByRoles(ctx context.Context, roles ...model.UserRole) ([]*model.User, error) {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+
+	query := `
+		SELECT id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
+		FROM users
+		WHERE role = ANY($1)
+		ORDER BY created_at
+		`
+	rows, err := r.db.QueryContext(ctx, query, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*model.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateRole changes the role of a user within the given transaction
+func (r *UserRepository) UpdateRole(ctx context.Context, tx *sql.Tx, id uuid.UUID, role model.UserRole) (*model.User, error) {
+	query := `
+		UPDATE users
+		SET role = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id, email, username, password, role, email_verified_at, deletion_scheduled_at, created_at, updated_at
+	`
+	user, err := scanUser(tx.QueryRowContext(ctx, query, role, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func scanUser(row scanner) (*model.User, error) {
+	var (
+		user     model.User
+		password sql.NullString
+	)
+	if err := row.Scan(
 		&user.ID,
 		&user.Email,
 		&user.Username,
-		&user.Password,
+		&password,
 		&user.Role,
+		&user.EmailVerifiedAt,
+		&user.DeletionScheduledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	); err != nil {
 		return nil, err
 	}
+	user.Password = password.String
+	user.HasPassword = password.Valid
 	return &user, nil
 }
@@ -27,61 +27,84 @@ func (r *VoteRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
 func (r *VoteRepository) Create(ctx context.Context, tx *sql.Tx, data *model.Vote) (*model.Vote, error) {
 	var vote model.Vote
 	query := `
-        INSERT INTO votes(user_id, joint_id, direction)
-        VALUES ($1, $2, $3)
-		RETURNING id, user_id, joint_id, direction, created_at, updated_at
+        INSERT INTO votes(user_id, joint_id, direction, verified, ip_address, fingerprint)
+        VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, user_id, joint_id, direction, verified, created_at, updated_at
     `
-	if err := r.db.QueryRowContext(ctx, query, data.UserID, data.JointID, data.Direction).Scan(
+	if err := tx.QueryRowContext(ctx, query, data.UserID, data.JointID, data.Direction, data.Verified, data.IPAddress, data.Fingerprint).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.JointID,
 		&vote.Direction,
+		&vote.Verified,
 		&vote.CreatedAt,
 		&vote.UpdatedAt,
 	); err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
 	return &vote, nil
 }
 
-// Upsert adds a new vote record or update the direction if it already exists
+// Upsert adds a new vote record or update the direction if it already exists. A vote voided by moderators is left unchanged and ErrVoided is returned
 func (r *VoteRepository) Upsert(ctx context.Context, tx *sql.Tx, data *model.Vote) (*model.Vote, error) {
 	var vote model.Vote
 	query := `
-        INSERT INTO votes(user_id, joint_id, direction)
-        VALUES ($1, $2, $3)
+        INSERT INTO votes(user_id, joint_id, direction, verified, ip_address, fingerprint)
+        VALUES ($1, $2, $3, $4, $5, $6)
 		ON CONFLICT(user_id, joint_id) 
 		DO UPDATE SET
-			direction = EXCLUDED.direction, updated_at = NOW()
-		RETURNING id, user_id, joint_id, direction, created_at, updated_at
+			direction = EXCLUDED.direction, verified = votes.verified OR EXCLUDED.verified,
+			ip_address = EXCLUDED.ip_address, fingerprint = EXCLUDED.fingerprint, updated_at = NOW()
+		WHERE votes.voided_at IS NULL
+		RETURNING id, user_id, joint_id, direction, verified, created_at, updated_at
     `
-	if err := tx.QueryRowContext(ctx, query, data.UserID, data.JointID, data.Direction).Scan(
+	if err := tx.QueryRowContext(ctx, query, data.UserID, data.JointID, data.Direction, data.Verified, data.IPAddress, data.Fingerprint).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.JointID,
 		&vote.Direction,
+		&vote.Verified,
 		&vote.CreatedAt,
 		&vote.UpdatedAt,
 	); err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		// the conflicting vote was voided so nothing was updated
+		if err == sql.ErrNoRows {
+			return nil, ErrVoided
+		}
 		return nil, err
 	}
 	return &vote, nil
 }
 
-func (r *VoteRepository) GetUserJointVote(ctx context.Context, userID, jointID uuid.UUID) (*model.Vote, error) {
+// LockUserJoint serializes votes of a user on a joint until the transaction ends so that concurrent vote flips are applied one after the other
+func (r *VoteRepository) LockUserJoint(ctx context.Context, tx *sql.Tx, userID, jointID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended('vote:' || $1 || ':' || $2, 0))`, userID.String(), jointID.String())
+	return err
+}
+
+// GetUserJointVote retrieves the vote of a user on a joint within the given transaction
+func (r *VoteRepository) GetUserJointVote(ctx context.Context, tx *sql.Tx, userID, jointID uuid.UUID) (*model.Vote, error) {
 	query := `
-		SELECT id, user_id, joint_id, direction, created_at, updated_at
+		SELECT id, user_id, joint_id, direction, verified, created_at, updated_at, voided_at IS NOT NULL AS voided
 		FROM votes  
 		WHERE user_id = $1 AND joint_id = $2
 		`
 	var vote model.Vote
-	err := r.db.QueryRowContext(ctx, query, userID, jointID).Scan(
+	err := tx.QueryRowContext(ctx, query, userID, jointID).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.JointID,
 		&vote.Direction,
+		&vote.Verified,
 		&vote.CreatedAt,
 		&vote.UpdatedAt,
+		&vote.Voided,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -94,7 +117,7 @@ func (r *VoteRepository) GetUserJointVote(ctx context.Context, userID, jointID u
 
 func (r *VoteRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Vote, error) {
 	query := `
-		SELECT id, user_id, joint_id, direction, created_at, updated_at
+		SELECT id, user_id, joint_id, direction, verified, created_at, updated_at
 		FROM votes  
 		WHERE id = $1
 		`
@@ -104,6 +127,7 @@ func (r *VoteRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Vote
 		&vote.UserID,
 		&vote.JointID,
 		&vote.Direction,
+		&vote.Verified,
 		&vote.CreatedAt,
 		&vote.UpdatedAt,
 	)
@@ -116,66 +140,137 @@ func (r *VoteRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Vote
 	return &vote, nil
 }
 
-func (r *VoteRepository) GetVotersByJointID(ctx context.Context, jointID uuid.UUID, offset, limit int) ([]*model.JointVoter, error) {
+// GetVotersByJointID returns the requested page of the users whose votes count on a joint, most recent votes first
+func (r *VoteRepository) GetVotersByJointID(ctx context.Context, jointID uuid.UUID, page model.Page) (*model.Paged[*model.JointVoter], error) {
 	query := `
-		SELECT v.user_id, v.joint_id, v.direction, v.created_at, v.updated_at, u.username 
+		SELECT v.user_id, u.username, v.joint_id, v.direction, v.verified, v.created_at, v.updated_at
 		FROM votes v
 		JOIN users u
 		ON v.user_id = u.id
-		WHERE v.joint_id = $1
-		ORDER BY v.created_at DESC
-		LIMIT $2 OFFSET $3
+		JOIN joints j
+		ON v.joint_id = j.id
+		WHERE v.joint_id = $1 AND v.voided_at IS NULL AND j.deleted_at IS NULL
 		`
-	rows, err := r.db.QueryContext(ctx, query, jointID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var voters []*model.JointVoter
-	for rows.Next() {
+	// users vote at most once on a joint
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "user_id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{jointID}, keys, page, func(row scanner) (*model.JointVoter, error) {
 		var voter model.JointVoter
-		if err := rows.Scan(
+		if err := row.Scan(
 			&voter.UserID,
 			&voter.Username,
 			&voter.JointID,
 			&voter.Direction,
+			&voter.Verified,
 			&voter.CreatedAt,
 			&voter.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
-
-		voters = append(voters, &voter)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return voters, nil
+		return &voter, nil
+	})
 }
 
-func (r *VoteRepository) UpdateVoteDirectionByID(ctx context.Context, id uuid.UUID, direction model.VoteDirection) (*model.Vote, error) {
+// Delete removes the vote of a user on a joint and returns it. A vote voided by moderators is kept as evidence of the review that voided it and ErrVoided is returned
+func (r *VoteRepository) Delete(ctx context.Context, tx *sql.Tx, userID, jointID uuid.UUID) (*model.Vote, error) {
 	query := `
-        UPDATE votes 
-        SET direction = $1, updated_at = NOW()
-        WHERE id = $2
-        RETURNING id, user_id, joint_id, direction, created_at, updated_at
-    `
+		DELETE FROM votes
+		WHERE user_id = $1 AND joint_id = $2 AND voided_at IS NULL
+		RETURNING id, user_id, joint_id, direction, verified, created_at, updated_at
+	`
 	var vote model.Vote
-	err := r.db.QueryRowContext(ctx, query, id, direction).Scan(
+	err := tx.QueryRowContext(ctx, query, userID, jointID).Scan(
 		&vote.ID,
 		&vote.UserID,
 		&vote.JointID,
 		&vote.Direction,
+		&vote.Verified,
 		&vote.CreatedAt,
 		&vote.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
+			var voided bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM votes WHERE user_id = $1 AND joint_id = $2)`, userID, jointID).Scan(&voided); err != nil {
+				return nil, err
+			}
+			if voided {
+				return nil, ErrVoided
+			}
 			return nil, ErrNotFound
 		}
 		return nil, err
 	}
 	return &vote, nil
 }
+
+// GetUserVotes retrieves the votes of a user along with the joints voted on, most recently updated first. Voided votes are included and flagged so that users can see they no longer count
+func (r *VoteRepository) GetUserVotes(ctx context.Context, userID uuid.UUID, page model.Page) (*model.Paged[*model.UserVote], error) {
+	query := `
+		SELECT v.id, v.user_id, v.joint_id, v.direction, v.verified, v.created_at, v.updated_at, j.name, v.voided_at IS NOT NULL AS voided
+		FROM votes v
+		JOIN joints j
+		ON v.joint_id = j.id
+		WHERE v.user_id = $1 AND j.deleted_at IS NULL
+	`
+	keys := []sortKey{{column: "updated_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{userID}, keys, page, func(row scanner) (*model.UserVote, error) {
+		var vote model.UserVote
+		if err := row.Scan(
+			&vote.ID,
+			&vote.UserID,
+			&vote.JointID,
+			&vote.Direction,
+			&vote.Verified,
+			&vote.CreatedAt,
+			&vote.UpdatedAt,
+			&vote.JointName,
+			&vote.Voided,
+		); err != nil {
+			return nil, err
+		}
+		return &vote, nil
+	})
+}
+
+// GetUserVoteDirections returns the direction of the votes of a user on the given joints. Joints the user has not voted on or whose vote was voided are omitted
+func (r *VoteRepository) GetUserVoteDirections(ctx context.Context, userID uuid.UUID, jointIDs []uuid.UUID) (map[uuid.UUID]model.VoteDirection, error) {
+	ids := make([]string, 0, len(jointIDs))
+	for _, id := range jointIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `SELECT joint_id, direction FROM votes WHERE user_id = $1 AND joint_id = ANY($2::uuid[]) AND voided_at IS NULL`
+	rows, err := r.db.QueryContext(ctx, query, userID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	directions := make(map[uuid.UUID]model.VoteDirection)
+	for rows.Next() {
+		var id uuid.UUID
+		var direction model.VoteDirection
+		if err := rows.Scan(&id, &direction); err != nil {
+			return nil, err
+		}
+		directions[id] = direction
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return directions, nil
+}
+
+// MarkVerified flags the vote of a user on a joint as coming from a verified visitor. It reports whether a vote was changed
+func (r *VoteRepository) MarkVerified(ctx context.Context, tx *sql.Tx, userID, jointID uuid.UUID) (bool, error) {
+	query := `UPDATE votes SET verified = TRUE WHERE user_id = $1 AND joint_id = $2 AND NOT verified`
+	result, err := tx.ExecContext(ctx, query, userID, jointID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// VoteReviewRepository handles database operations for vote integrity signals and the vote review queue
+type VoteReviewRepository struct {
+	db *sql.DB
+}
+
+// NewVoteReviewRepository creates a new vote review repository
+func NewVoteReviewRepository(db *sql.DB) *VoteReviewRepository {
+	return &VoteReviewRepository{db: db}
+}
+
+// voteReviewColumns selects a review along with the aggregates of its votes. The reviews table should be aliased as r
+const voteReviewColumns = `
+	r.id, r.joint_id, j.name, r.reason, r.cluster_key, r.direction, r.status,
+	(SELECT COUNT(*) FROM vote_review_votes rv WHERE rv.review_id = r.id),
+	(SELECT COALESCE(AVG(v.risk_score), 0) FROM vote_review_votes rv JOIN votes v ON v.id = rv.vote_id WHERE rv.review_id = r.id),
+	r.resolved_by, r.resolved_at, r.created_at, r.updated_at
+`
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *VoteReviewRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// GetVoteSignals retrieves the details of a vote used to assess it. Other votes are only considered when they were cast in the same direction since the given times
+func (r *VoteReviewRepository) GetVoteSignals(ctx context.Context, voteID uuid.UUID, clusterSince, burstSince time.Time) (*model.VoteSignals, error) {
+	query := `
+		SELECT
+			v.id, v.joint_id, v.user_id, v.direction, v.ip_address, v.fingerprint, v.verified, u.created_at,
+			(
+				SELECT COUNT(DISTINCT o.user_id) FROM votes o
+				WHERE o.joint_id = v.joint_id AND o.user_id <> v.user_id AND o.direction = v.direction AND o.voided_at IS NULL
+				AND o.updated_at >= $2 AND o.ip_address = v.ip_address
+			),
+			(
+				SELECT COUNT(DISTINCT o.user_id) FROM votes o
+				WHERE o.joint_id = v.joint_id AND o.user_id <> v.user_id AND o.direction = v.direction AND o.voided_at IS NULL
+				AND o.updated_at >= $2 AND o.fingerprint = v.fingerprint
+			),
+			(
+				SELECT COUNT(*) FROM votes o
+				WHERE o.joint_id = v.joint_id AND o.direction = v.direction AND o.voided_at IS NULL AND o.updated_at >= $3
+			)
+		FROM votes v
+		JOIN users u
+		ON u.id = v.user_id
+		WHERE v.id = $1 AND v.voided_at IS NULL
+	`
+	var signals model.VoteSignals
+	err := r.db.QueryRowContext(ctx, query, voteID, clusterSince, burstSince).Scan(
+		&signals.VoteID,
+		&signals.JointID,
+		&signals.UserID,
+		&signals.Direction,
+		&signals.IPAddress,
+		&signals.Fingerprint,
+		&signals.Verified,
+		&signals.AccountCreated,
+		&signals.SharedIPVoters,
+		&signals.SharedDeviceVoters,
+		&signals.BurstVotes,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &signals, nil
+}
+
+// UpdateVoteRisk records the risk assessment of a vote
+func (r *VoteReviewRepository) UpdateVoteRisk(ctx context.Context, tx *sql.Tx, voteID uuid.UUID, score float64, signals []model.VoteRiskSignal) error {
+	data, err := json.Marshal(signals)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE votes SET risk_score = $1, risk_signals = $2 WHERE id = $3`, score, string(data), voteID)
+	return err
+}
+
+// OpenReview returns the open review of a cluster, creating it if there is none. It reports whether the review was created
+func (r *VoteReviewRepository) OpenReview(ctx context.Context, tx *sql.Tx, jointID uuid.UUID, reason model.VoteReviewReason, clusterKey string, direction model.VoteDirection) (uuid.UUID, bool, error) {
+	query := `
+		INSERT INTO vote_reviews(joint_id, reason, cluster_key, direction)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT(joint_id, reason, cluster_key, direction) WHERE status = 'open'
+		DO UPDATE SET updated_at = NOW()
+		RETURNING id, xmax = 0
+	`
+	var id uuid.UUID
+	var created bool
+	if err := tx.QueryRowContext(ctx, query, jointID, reason, clusterKey, direction).Scan(&id, &created); err != nil {
+		return uuid.Nil, false, err
+	}
+	return id, created, nil
+}
+
+// AddClusterVotes adds the votes of a cluster cast since the given time to a review. IP and device clusters are matched on the cluster key while bursts only include votes at or above the given risk score
+func (r *VoteReviewRepository) AddClusterVotes(ctx context.Context, tx *sql.Tx, reviewID, jointID uuid.UUID, reason model.VoteReviewReason, clusterKey string, direction model.VoteDirection, since time.Time, minRiskScore float64) error {
+	query := `
+		INSERT INTO vote_review_votes(review_id, vote_id)
+		SELECT $1, id FROM votes
+		WHERE joint_id = $2 AND direction = $3 AND voided_at IS NULL AND updated_at >= $4 AND `
+	args := []any{reviewID, jointID, direction, since}
+
+	switch reason {
+	case model.IPClusterReview:
+		query += `ip_address = $5`
+		args = append(args, clusterKey)
+	case model.DeviceClusterReview:
+		query += `fingerprint = $5`
+		args = append(args, clusterKey)
+	default:
+		query += `risk_score >= $5`
+		args = append(args, minRiskScore)
+	}
+	query += ` ON CONFLICT DO NOTHING`
+
+	_, err := tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+// GetByID retrieves a vote review
+func (r *VoteReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.VoteReview, error) {
+	query := `SELECT ` + voteReviewColumns + ` FROM vote_reviews r JOIN joints j ON j.id = r.joint_id WHERE r.id = $1`
+	review, err := scanVoteReview(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetAll retrieves vote reviews, most recently updated first. All statuses are returned when status is empty
+func (r *VoteReviewRepository) GetAll(ctx context.Context, status model.VoteReviewStatus, page model.Page) (*model.Paged[*model.VoteReview], error) {
+	query := `
+		SELECT ` + voteReviewColumns + `
+		FROM vote_reviews r
+		JOIN joints j
+		ON j.id = r.joint_id
+		WHERE ($1 = '' OR r.status = $1)
+	`
+	keys := []sortKey{{column: "updated_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{status}, keys, page, func(row scanner) (*model.VoteReview, error) {
+		return scanVoteReview(row)
+	})
+}
+
+// GetReviewVotes retrieves the votes in a review along with their voters, riskiest first
+func (r *VoteReviewRepository) GetReviewVotes(ctx context.Context, reviewID uuid.UUID, page model.Page) (*model.Paged[*model.ReviewedVote], error) {
+	query := `
+		SELECT v.id, v.user_id, u.username, u.created_at AS account_created_at, v.direction, v.verified, v.ip_address, v.fingerprint, v.risk_score, v.risk_signals, v.voided_at, v.created_at
+		FROM vote_review_votes rv
+		JOIN votes v
+		ON v.id = rv.vote_id
+		JOIN users u
+		ON u.id = v.user_id
+		WHERE rv.review_id = $1
+	`
+	// risk scores are never negative so unscored votes are placed last
+	keys := []sortKey{
+		{column: "COALESCE(risk_score, -1)", cast: "DOUBLE PRECISION", desc: true},
+		{column: "created_at", cast: "TIMESTAMPTZ", desc: true},
+		{column: "id", cast: "UUID", desc: true},
+	}
+	return queryPage(ctx, r.db, query, []any{reviewID}, keys, page, func(row scanner) (*model.ReviewedVote, error) {
+		var vote model.ReviewedVote
+		var signals []byte
+		if err := row.Scan(
+			&vote.VoteID,
+			&vote.UserID,
+			&vote.Username,
+			&vote.AccountCreatedAt,
+			&vote.Direction,
+			&vote.Verified,
+			&vote.IPAddress,
+			&vote.Fingerprint,
+			&vote.RiskScore,
+			&signals,
+			&vote.VoidedAt,
+			&vote.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(signals, &vote.RiskSignals); err != nil {
+			return nil, err
+		}
+		return &vote, nil
+	})
+}
+
+// VoidReviewVotes voids the votes of a review that have not been voided yet and returns their IDs. Only the given votes are voided when voteIDs is not empty
+func (r *VoteReviewRepository) VoidReviewVotes(ctx context.Context, tx *sql.Tx, reviewID uuid.UUID, voteIDs []uuid.UUID, voidedBy uuid.UUID) ([]uuid.UUID, error) {
+	ids := make([]string, 0, len(voteIDs))
+	for _, id := range voteIDs {
+		ids = append(ids, id.String())
+	}
+
+	query := `
+		UPDATE votes
+		SET voided_at = NOW(), voided_by = $3
+		WHERE id IN (SELECT vote_id FROM vote_review_votes WHERE review_id = $1)
+		AND voided_at IS NULL
+		AND (cardinality($2::uuid[]) = 0 OR id = ANY($2::uuid[]))
+		RETURNING id
+	`
+	rows, err := tx.QueryContext(ctx, query, reviewID, ids, voidedBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	voided := make([]uuid.UUID, 0)
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		voided = append(voided, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return voided, nil
+}
+
+// Resolve closes an open review with the given status. ErrNotFound is returned when the review is not open
+func (r *VoteReviewRepository) Resolve(ctx context.Context, tx *sql.Tx, id uuid.UUID, status model.VoteReviewStatus, resolvedBy uuid.UUID) error {
+	query := `
+		UPDATE vote_reviews
+		SET status = $1, resolved_by = $2, resolved_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND status = 'open'
+	`
+	result, err := tx.ExecContext(ctx, query, status, resolvedBy, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanVoteReview(row interface{ Scan(...any) error }) (*model.VoteReview, error) {
+	var review model.VoteReview
+	if err := row.Scan(
+		&review.ID,
+		&review.JointID,
+		&review.JointName,
+		&review.Reason,
+		&review.ClusterKey,
+		&review.Direction,
+		&review.Status,
+		&review.VoteCount,
+		&review.RiskScore,
+		&review.ResolvedBy,
+		&review.ResolvedAt,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
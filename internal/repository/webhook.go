@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"chow/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepository handles database operations for webhooks and their deliveries
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, url, secret, event_types, is_active, consecutive_failures, disabled_at, created_by, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, response_code, response_body, last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+// GetTx returns a transaction that can be passed down to other repository functions. The transaction should be rolled back on error or committed on success by the caller
+func (r *WebhookRepository) GetTx(ctx context.Context) (*sql.Tx, error) {
+	return r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+}
+
+// Create adds a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, tx *sql.Tx, data *model.Webhook) (*model.Webhook, error) {
+	eventTypes, err := json.Marshal(data.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        INSERT INTO webhooks(url, secret, event_types, created_by)
+        VALUES ($1, $2, $3, $4)
+		RETURNING ` + webhookColumns
+	return scanWebhook(tx.QueryRowContext(ctx, query, data.URL, data.Secret, string(eventTypes), data.CreatedBy))
+}
+
+// GetByID retrieves a webhook by its ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+	return scanWebhook(r.db.QueryRowContext(ctx, query, id))
+}
+
+// GetAll retrieves webhooks, newest first
+func (r *WebhookRepository) GetAll(ctx context.Context, page model.Page) (*model.Paged[*model.Webhook], error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, nil, keys, page, func(row scanner) (*model.Webhook, error) {
+		return scanWebhook(row)
+	})
+}
+
+// GetActiveByEventType retrieves the active webhooks subscribed to an event type
+func (r *WebhookRepository) GetActiveByEventType(ctx context.Context, eventType model.EventType) ([]*model.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE is_active AND event_types @> jsonb_build_array($1::text)`
+	rows, err := r.db.QueryContext(ctx, query, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []*model.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// UpdateByID replaces the url, secret, event types and active state of a webhook. Activating a webhook clears its failure count
+func (r *WebhookRepository) UpdateByID(ctx context.Context, tx *sql.Tx, id uuid.UUID, data *model.Webhook) (*model.Webhook, error) {
+	eventTypes, err := json.Marshal(data.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		UPDATE webhooks
+		SET url = $1, secret = $2, event_types = $3, is_active = $4,
+			consecutive_failures = CASE WHEN $4 AND NOT is_active THEN 0 ELSE consecutive_failures END,
+			disabled_at = CASE WHEN $4 THEN NULL ELSE COALESCE(disabled_at, NOW()) END,
+			updated_at = NOW()
+		WHERE id = $5
+		RETURNING ` + webhookColumns
+	return scanWebhook(tx.QueryRowContext(ctx, query, data.URL, data.Secret, string(eventTypes), data.IsActive, id))
+}
+
+// DeleteByID removes a webhook along with its delivery log
+func (r *WebhookRepository) DeleteByID(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	result, err := tx.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// RecordSuccess clears the failure count of a webhook after a successful delivery
+func (r *WebhookRepository) RecordSuccess(ctx context.Context, tx *sql.Tx, id uuid.UUID) error {
+	query := `UPDATE webhooks SET consecutive_failures = 0 WHERE id = $1 AND consecutive_failures > 0`
+	_, err := tx.ExecContext(ctx, query, id)
+	return err
+}
+
+// RecordFailure increments the failure count of a webhook and disables it once the count reaches disableAfter. It reports whether the webhook was disabled by this failure
+func (r *WebhookRepository) RecordFailure(ctx context.Context, tx *sql.Tx, id uuid.UUID, disableAfter int) (bool, error) {
+	query := `
+		UPDATE webhooks
+		SET consecutive_failures = consecutive_failures + 1,
+			is_active = is_active AND consecutive_failures + 1 < $1,
+			disabled_at = CASE WHEN is_active AND consecutive_failures + 1 >= $1 THEN NOW() ELSE disabled_at END
+		WHERE id = $2
+		RETURNING disabled_at IS NOT NULL AND consecutive_failures = $1
+	`
+	var disabled bool
+	if err := tx.QueryRowContext(ctx, query, disableAfter, id).Scan(&disabled); err != nil {
+		if err == sql.ErrNoRows {
+			return false, ErrNotFound
+		}
+		return false, err
+	}
+	return disabled, nil
+}
+
+// CreateDelivery queues an event for delivery to a webhook. Events already queued for the webhook are ignored and ErrAlreadyExist is returned
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, data *model.WebhookDelivery) (*model.WebhookDelivery, error) {
+	query := `
+        INSERT INTO webhook_deliveries(webhook_id, event_id, event_type, payload)
+        VALUES ($1, $2, $3, $4)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+		RETURNING ` + webhookDeliveryColumns
+	delivery, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, query, data.WebhookID, data.EventID, data.EventType, string(data.Payload)))
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, ErrAlreadyExist
+		}
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// GetDeliveryByID retrieves a delivery of a webhook
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, webhookID, id uuid.UUID) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = $1 AND webhook_id = $2`
+	return scanWebhookDelivery(r.db.QueryRowContext(ctx, query, id, webhookID))
+}
+
+// GetDeliveries retrieves the delivery log of a webhook, newest first
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID uuid.UUID, page model.Page) (*model.Paged[*model.WebhookDelivery], error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+	`
+	keys := []sortKey{{column: "created_at", cast: "TIMESTAMPTZ", desc: true}, {column: "id", cast: "UUID", desc: true}}
+	return queryPage(ctx, r.db, query, []any{webhookID}, keys, page, func(row scanner) (*model.WebhookDelivery, error) {
+		return scanWebhookDelivery(row)
+	})
+}
+
+// ClaimDueDeliveries returns pending deliveries of active webhooks that are due, oldest first. Claimed deliveries are leased by pushing their next attempt past the lease so that other instances skip them while they are being sent
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*model.WebhookDispatch, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.is_active
+			ORDER BY d.next_attempt_at
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts, d.response_code, d.response_body, d.last_error,
+			d.next_attempt_at, d.delivered_at, d.created_at, d.updated_at, w.url, w.secret
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dispatches := make([]*model.WebhookDispatch, 0, limit)
+	for rows.Next() {
+		var dispatch model.WebhookDispatch
+		delivery, err := scanWebhookDelivery(rows, &dispatch.URL, &dispatch.Secret)
+		if err != nil {
+			return nil, err
+		}
+		dispatch.Delivery = delivery
+		dispatches = append(dispatches, &dispatch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return dispatches, nil
+}
+
+// MarkDeliverySucceeded records a successful delivery attempt
+func (r *WebhookRepository) MarkDeliverySucceeded(ctx context.Context, tx *sql.Tx, id uuid.UUID, responseCode int, responseBody string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', attempts = attempts + 1, response_code = $1, response_body = $2, last_error = NULL, delivered_at = NOW(), updated_at = NOW()
+		WHERE id = $3
+	`
+	_, err := tx.ExecContext(ctx, query, responseCode, responseBody, id)
+	return err
+}
+
+// MarkDeliveryFailed records a failed delivery attempt. The delivery is retried after the backoff unless it is marked as failed
+func (r *WebhookRepository) MarkDeliveryFailed(ctx context.Context, tx *sql.Tx, id uuid.UUID, status model.WebhookDeliveryStatus, responseCode *int, responseBody *string, lastError string, backoff time.Duration) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $1, attempts = attempts + 1, response_code = $2, response_body = $3, last_error = $4,
+			next_attempt_at = NOW() + make_interval(secs => $5), updated_at = NOW()
+		WHERE id = $6
+	`
+	_, err := tx.ExecContext(ctx, query, status, responseCode, responseBody, lastError, backoff.Seconds(), id)
+	return err
+}
+
+// ResetDelivery queues a delivery to be sent again immediately with a fresh attempt count
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, tx *sql.Tx, webhookID, id uuid.UUID) (*model.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND webhook_id = $2
+		RETURNING ` + webhookDeliveryColumns
+	return scanWebhookDelivery(tx.QueryRowContext(ctx, query, id, webhookID))
+}
+
+// scanWebhook scans a webhook from a row selected with webhookColumns
+func scanWebhook(row interface{ Scan(...any) error }) (*model.Webhook, error) {
+	var webhook model.Webhook
+	var eventTypes []byte
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		&eventTypes,
+		&webhook.IsActive,
+		&webhook.ConsecutiveFailures,
+		&webhook.DisabledAt,
+		&webhook.CreatedBy,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(eventTypes, &webhook.EventTypes); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// scanWebhookDelivery scans a delivery from a row selected with webhookDeliveryColumns followed by any extra columns
+func scanWebhookDelivery(row interface{ Scan(...any) error }, extra ...any) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	var payload []byte
+	dest := []any{
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.ResponseCode,
+		&delivery.ResponseBody,
+		&delivery.LastError,
+		&delivery.NextAttemptAt,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	delivery.Payload = payload
+	return &delivery, nil
+}
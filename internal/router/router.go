@@ -3,6 +3,7 @@ package router
 import (
 	"chow/docs"
 	"chow/internal/handler"
+	"chow/internal/model"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -10,15 +11,26 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func RegisterRoutes(router *gin.Engine, authHandler *handler.AuthHandler, jointHandler *handler.JointHandler, complaintHandler *handler.ComplaintHandler, middleware *handler.Middleware) {
+func RegisterRoutes(router *gin.Engine, authHandler *handler.AuthHandler, keyHandler *handler.KeyHandler, jointHandler *handler.JointHandler, complaintHandler *handler.ComplaintHandler, auditHandler *handler.AuditHandler, notificationHandler *handler.NotificationHandler, webhookHandler *handler.WebhookHandler, streamHandler *handler.StreamHandler, savedSearchHandler *handler.SavedSearchHandler, checkinHandler *handler.CheckinHandler, voteReviewHandler *handler.VoteReviewHandler, reputationHandler *handler.ReputationHandler, accountHandler *handler.AccountHandler, recommendationHandler *handler.RecommendationHandler, trendingHandler *handler.TrendingHandler, translationHandler *handler.TranslationHandler, middleware *handler.Middleware) {
 	// cors
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Authorization", "X-Forwarded-For", "Origin", "Content-Type", "Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Authorization", "X-Request-ID", "X-Device-ID", "Origin", "Content-Type", "Content-Length"},
+		ExposeHeaders:    []string{"X-Request-ID", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
 		AllowCredentials: true,
 	}))
 
+	// request origin for auditing
+	router.Use(middleware.RequestInfoMiddleware())
+
+	// render handler errors
+	router.Use(handler.ErrorMiddleware())
+	router.NoRoute(handler.RouteNotFound)
+
+	// public keys for verifying access tokens, at the well-known path outside the api
+	router.GET("/.well-known/jwks.json", keyHandler.GetJWKS)
+
 	// base api router
 	apiRouter := router.Group("/api")
 	apiRouter.GET("/health", func(ctx *gin.Context) { ctx.JSON(200, "OK") })
@@ -27,31 +39,61 @@ func RegisterRoutes(router *gin.Engine, authHandler *handler.AuthHandler, jointH
 	docs.SwaggerInfo.BasePath = "/api"
 	apiRouter.GET("/docs/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
+	// every route registered from here is limited by IP address. routes prone to abuse have their own stricter policy
+	apiRouter.Use(middleware.RateLimitMiddleware(model.DefaultRateLimit))
+
 	// auth
 	auth := apiRouter.Group("/auth")
 	{
-		auth.POST("/login", authHandler.Login)
-		auth.POST("/register", authHandler.Register)
+		auth.POST("/login", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.Login)
+		auth.POST("/register", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.Register)
+		auth.POST("/unlock", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.UnlockAccount)
+		auth.POST("/2fa/verify", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.VerifyTwoFactor)
+		auth.GET("/oidc/providers", authHandler.GetOIDCProviders)
+		auth.POST("/oidc/:provider/authorize", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.StartOIDCLogin)
+		auth.POST("/oidc/callback", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.CompleteOIDCLogin)
+
+		// protected
+		twoFactor := auth.Group("/2fa", middleware.AuthMiddleware())
+		{
+			twoFactor.GET("", authHandler.GetTwoFactorStatus)
+			twoFactor.POST("/setup", authHandler.SetupTwoFactor)
+			twoFactor.POST("/enable", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.EnableTwoFactor)
+			twoFactor.POST("/disable", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.DisableTwoFactor)
+			twoFactor.POST("/recovery-codes", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.RegenerateRecoveryCodes)
+		}
 	}
 
 	// joints
 	joints := apiRouter.Group("/joints")
 	{
-		// public
-		joints.GET("", jointHandler.GetAllJoints)
-		joints.GET("/nearby", jointHandler.GetNearByJoints)
-		joints.GET("/search", jointHandler.SearchJoints)
-		joints.GET("/:id", jointHandler.GetJoint)
+		// public. responses are personalised when the user is authenticated
+		joints.GET("", middleware.OptionalAuthMiddleware(model.JointsReadScope), jointHandler.GetAllJoints)
+		joints.GET("/nearby", middleware.OptionalAuthMiddleware(model.JointsReadScope), jointHandler.GetNearByJoints)
+		joints.GET("/search", middleware.OptionalAuthMiddleware(model.JointsReadScope), jointHandler.SearchJoints)
+		joints.GET("/trending", trendingHandler.GetTrendingJoints)
+		joints.GET("/:id", middleware.OptionalAuthMiddleware(model.JointsReadScope), jointHandler.GetJoint)
+		joints.GET("/:id/translations", translationHandler.GetJointTranslations)
+
+		// protected routes that integrations can also use with an API key of the scope
+		joints.GET("/recommended", middleware.AuthMiddleware(model.JointsReadScope), recommendationHandler.GetRecommendedJoints)
+		joints.POST("/:id/vote", middleware.AuthMiddleware(model.VotesWriteScope), middleware.RateLimitMiddleware(model.VoteRateLimit), jointHandler.VoteJoint)
+		joints.DELETE("/:id/vote", middleware.AuthMiddleware(model.VotesWriteScope), middleware.RateLimitMiddleware(model.VoteRateLimit), jointHandler.RetractVote)
 
 		// protected
 		protectedJoints := joints.Use(middleware.AuthMiddleware())
 		{
-			protectedJoints.POST("", jointHandler.CreateJoint)
+			protectedJoints.POST("", middleware.RateLimitMiddleware(model.SubmissionRateLimit), jointHandler.CreateJoint)
 			protectedJoints.PATCH("/:id", jointHandler.UpdateJoint)
 			protectedJoints.DELETE("/:id", jointHandler.DeleteJoint)
-			protectedJoints.POST("/:id/vote", jointHandler.VoteJoint)
-			protectedJoints.POST("/:id/complaints", jointHandler.CreateJointComplaint)
+			protectedJoints.PATCH("/:id/approve", jointHandler.ApproveJoint)
+			protectedJoints.PATCH("/:id/reject", jointHandler.RejectJoint)
+			protectedJoints.GET("/:id/voters", jointHandler.GetJointVoters)
+			protectedJoints.POST("/:id/checkins", middleware.RateLimitMiddleware(model.CheckinRateLimit), checkinHandler.CreateCheckin)
+			protectedJoints.POST("/:id/complaints", middleware.RateLimitMiddleware(model.ComplaintRateLimit), jointHandler.CreateJointComplaint)
 			protectedJoints.GET("/:id/complaints", jointHandler.GetJointComplaints)
+			protectedJoints.PUT("/:id/translations/:locale", translationHandler.SaveJointTranslation)
+			protectedJoints.DELETE("/:id/translations/:locale", translationHandler.DeleteJointTranslation)
 		}
 	}
 
@@ -65,6 +107,95 @@ func RegisterRoutes(router *gin.Engine, authHandler *handler.AuthHandler, jointH
 			protectedComplaints.GET("/me", complaintHandler.GetUserComplaints)
 			protectedComplaints.GET("/:id", complaintHandler.GetComplaint)
 			protectedComplaints.PATCH("/:id/resolve", complaintHandler.ResolveComplaint)
+			protectedComplaints.PATCH("/:id/reject", complaintHandler.RejectComplaint)
+			protectedComplaints.GET("/:id/replies", complaintHandler.GetComplaintReplies)
+			protectedComplaints.POST("/:id/replies", middleware.RateLimitMiddleware(model.ComplaintRateLimit), complaintHandler.CreateComplaintReply)
+		}
+	}
+
+	// notifications
+	notifications := apiRouter.Group("/notifications")
+	{
+		// protected
+		protectedNotifications := notifications.Use(middleware.AuthMiddleware())
+		{
+			protectedNotifications.GET("", notificationHandler.GetNotifications)
+			protectedNotifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+			protectedNotifications.POST("/read-all", notificationHandler.MarkAllNotificationsRead)
+			protectedNotifications.GET("/preferences", notificationHandler.GetNotificationPreferences)
+			protectedNotifications.PUT("/preferences", notificationHandler.UpdateNotificationPreferences)
+			protectedNotifications.PATCH("/:id/read", notificationHandler.MarkNotificationRead)
+		}
+	}
+
+	// users
+	users := apiRouter.Group("/users")
+	{
+		// public
+		users.GET("/:id/profile", reputationHandler.GetUserProfile)
+
+		// protected
+		protectedUsers := users.Use(middleware.AuthMiddleware())
+		{
+			protectedUsers.GET("/me/checkins", checkinHandler.GetVisitHistory)
+			protectedUsers.GET("/me/security-events", authHandler.GetSecurityEvents)
+			protectedUsers.GET("/me/identities", authHandler.GetIdentities)
+			protectedUsers.POST("/me/identities/:provider/authorize", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.StartOIDCLink)
+			protectedUsers.POST("/me/identities/callback", middleware.RateLimitMiddleware(model.AuthRateLimit), authHandler.CompleteOIDCLink)
+			protectedUsers.DELETE("/me/identities/:id", authHandler.UnlinkIdentity)
+			protectedUsers.GET("/me/api-keys", authHandler.GetAPIKeys)
+			protectedUsers.POST("/me/api-keys", middleware.RateLimitMiddleware(model.SubmissionRateLimit), authHandler.CreateAPIKey)
+			protectedUsers.DELETE("/me/api-keys/:id", authHandler.RevokeAPIKey)
+			protectedUsers.POST("/me/export", middleware.RateLimitMiddleware(model.SubmissionRateLimit), accountHandler.RequestDataExport)
+			protectedUsers.GET("/me/export", accountHandler.GetDataExports)
+			protectedUsers.GET("/me/export/:id", accountHandler.GetDataExport)
+			protectedUsers.GET("/me/export/:id/download", accountHandler.DownloadDataExport)
+			protectedUsers.DELETE("/me", middleware.RateLimitMiddleware(model.AuthRateLimit), accountHandler.DeleteAccount)
+			protectedUsers.DELETE("/me/deletion", accountHandler.CancelAccountDeletion)
+			protectedUsers.GET("/me/votes", jointHandler.GetUserVotes)
+			protectedUsers.GET("/me/reputation", reputationHandler.GetReputationLedger)
+			protectedUsers.GET("/me/saved-searches", savedSearchHandler.GetSavedSearches)
+			protectedUsers.POST("/me/saved-searches", middleware.RateLimitMiddleware(model.SubmissionRateLimit), savedSearchHandler.CreateSavedSearch)
+			protectedUsers.DELETE("/me/saved-searches/:id", savedSearchHandler.DeleteSavedSearch)
+			protectedUsers.GET("/me/saved-searches/:id/matches", savedSearchHandler.GetSavedSearchMatches)
+		}
+	}
+
+	// leaderboards
+	leaderboard := apiRouter.Group("/leaderboard")
+	{
+		leaderboard.GET("", reputationHandler.GetLeaderboard)
+		leaderboard.GET("/cities", reputationHandler.GetLeaderboardCities)
+	}
+
+	// real-time updates
+	apiRouter.GET("/stream", streamHandler.Stream)
+
+	// admin
+	admin := apiRouter.Group("/admin")
+	{
+		// protected. admin API keys can be used too
+		protectedAdmin := admin.Use(middleware.AuthMiddleware(model.AdminScope))
+		{
+			protectedAdmin.GET("/audit-events", auditHandler.GetAuditEvents)
+			protectedAdmin.GET("/audit-events/export", auditHandler.ExportAuditEvents)
+			protectedAdmin.GET("/joints/trash", jointHandler.GetDeletedJoints)
+			protectedAdmin.PATCH("/joints/trash/:id/restore", jointHandler.RestoreJoint)
+			protectedAdmin.DELETE("/joints/trash/:id", jointHandler.PurgeJoint)
+			protectedAdmin.GET("/moderator-candidates", reputationHandler.GetModeratorCandidates)
+			protectedAdmin.PATCH("/users/:id/promote", reputationHandler.PromoteToModerator)
+			protectedAdmin.GET("/vote-reviews", voteReviewHandler.GetVoteReviews)
+			protectedAdmin.GET("/vote-reviews/:id", voteReviewHandler.GetVoteReview)
+			protectedAdmin.GET("/vote-reviews/:id/votes", voteReviewHandler.GetVoteReviewVotes)
+			protectedAdmin.POST("/vote-reviews/:id/void", voteReviewHandler.VoidVotes)
+			protectedAdmin.PATCH("/vote-reviews/:id/dismiss", voteReviewHandler.DismissVoteReview)
+			protectedAdmin.POST("/webhooks", webhookHandler.CreateWebhook)
+			protectedAdmin.GET("/webhooks", webhookHandler.GetWebhooks)
+			protectedAdmin.GET("/webhooks/:id", webhookHandler.GetWebhook)
+			protectedAdmin.PATCH("/webhooks/:id", webhookHandler.UpdateWebhook)
+			protectedAdmin.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			protectedAdmin.GET("/webhooks/:id/deliveries", webhookHandler.GetWebhookDeliveries)
+			protectedAdmin.POST("/webhooks/:id/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverWebhookDelivery)
 		}
 	}
 }
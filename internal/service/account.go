@@ -0,0 +1,287 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrDataExportNotFound          = errors.New("data export not found")
+	ErrDataExportInProgress        = errors.New("a data export is already being prepared")
+	ErrDataExportNotReady          = errors.New("data export is not ready to download")
+	ErrAccountDeletionNotScheduled = errors.New("account is not scheduled for deletion")
+)
+
+const (
+	// dataExportBatchSize is the number of exports built per claim
+	dataExportBatchSize = 5
+	// dataExportLease is how long a claimed export is left to be built before another instance may retry it
+	dataExportLease = 10 * time.Minute
+	// maxDataExportAttempts is the number of attempts after which an export is marked as failed
+	maxDataExportAttempts = 3
+	// accountDeletionBatchSize is the number of due accounts anonymised per run
+	accountDeletionBatchSize = 50
+)
+
+// AccountService lets users export their personal data and delete their account
+type AccountService struct {
+	cfg         *config.Config
+	accountRepo *repository.AccountRepository
+	jointRepo   *repository.JointRepository
+	auditRepo   *repository.AuditRepository
+	authService *AuthService
+}
+
+func NewAccountService(cfg *config.Config, accountRepo *repository.AccountRepository, jointRepo *repository.JointRepository, auditRepo *repository.AuditRepository, authService *AuthService) *AccountService {
+	return &AccountService{
+		cfg:         cfg,
+		accountRepo: accountRepo,
+		jointRepo:   jointRepo,
+		auditRepo:   auditRepo,
+		authService: authService,
+	}
+}
+
+// RequestDataExport queues an archive of the personal data of a user to be built in the background. Users are emailed when it is ready
+func (s *AccountService) RequestDataExport(ctx context.Context, userID uuid.UUID) (*model.DataExport, error) {
+	export, err := s.accountRepo.CreateExport(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExist) {
+			return nil, ErrDataExportInProgress
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	s.authService.recordSecurityEvent(ctx, &userID, model.DataExportRequested)
+	return export, nil
+}
+
+// GetDataExports returns the exports of a user, newest first
+func (s *AccountService) GetDataExports(ctx context.Context, userID uuid.UUID) ([]*model.DataExport, error) {
+	return s.accountRepo.GetExports(ctx, userID)
+}
+
+// GetDataExport returns an export of a user
+func (s *AccountService) GetDataExport(ctx context.Context, userID, id uuid.UUID) (*model.DataExport, error) {
+	export, err := s.accountRepo.GetExport(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return export, nil
+}
+
+// DownloadDataExport returns the archive of a completed export of a user. Expired exports are treated as not found
+func (s *AccountService) DownloadDataExport(ctx context.Context, userID, id uuid.UUID) (*model.DataExportArchive, error) {
+	export, err := s.GetDataExport(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if export.Status != model.DataExportCompleted {
+		return nil, ErrDataExportNotReady
+	}
+
+	archive, err := s.accountRepo.GetArchive(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return archive, nil
+}
+
+// BuildPendingExports builds the archives of the exports waiting to be built. Failed attempts are retried with a growing delay until the export is marked as failed
+func (s *AccountService) BuildPendingExports(ctx context.Context) error {
+	for {
+		exports, err := s.accountRepo.ClaimDueExports(ctx, dataExportLease, dataExportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, export := range exports {
+			if err := s.buildExport(ctx, export); err != nil && ctx.Err() == nil {
+				log.Printf("failed to record data export %s: %v", export.ID, err)
+			}
+		}
+
+		if len(exports) < dataExportBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// buildExport builds and stores the archive of an export and tells its owner that it is ready
+func (s *AccountService) buildExport(ctx context.Context, export *model.DataExport) error {
+	archive, buildErr := s.buildArchive(ctx, export)
+
+	// the lease expires and the export is retried if building was interrupted by a shutdown
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if buildErr != nil {
+		var retryAt *time.Time
+		if export.Attempts < maxDataExportAttempts {
+			next := time.Now().Add(time.Duration(export.Attempts) * time.Minute)
+			retryAt = &next
+		}
+		return s.accountRepo.FailExport(ctx, export.ID, truncate(buildErr.Error(), 2000), retryAt)
+	}
+
+	export, err := s.accountRepo.CompleteExport(ctx, export.ID, archive, time.Now().Add(s.cfg.DataExportRetention))
+	if err != nil {
+		return err
+	}
+
+	if user, err := s.authService.getUser(ctx, export.UserID); err == nil {
+		s.authService.sendEmail(ctx, user.Email, "Your Chow data export is ready",
+			fmt.Sprintf("Hi %s,\n\nThe export of your Chow data you asked for is ready. Log in to download it before %s.\n", user.Username, export.ExpiresAt.Format(time.RFC1123)))
+	}
+	return nil
+}
+
+// buildArchive gathers the personal data of the owner of an export into a zip archive with a JSON file for each kind of data
+func (s *AccountService) buildArchive(ctx context.Context, export *model.DataExport) ([]byte, error) {
+	sections, err := s.accountRepo.GetPersonalData(ctx, export.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, section := range sections {
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: section.Name + ".json", Method: zip.Deflate, Modified: export.CreatedAt})
+		if err != nil {
+			return nil, err
+		}
+		var data bytes.Buffer
+		if err := json.Indent(&data, section.Data, "", "  "); err != nil {
+			return nil, err
+		}
+		if _, err := data.WriteTo(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PurgeExpiredExports removes the exports that can no longer be downloaded
+func (s *AccountService) PurgeExpiredExports(ctx context.Context) error {
+	_, err := s.accountRepo.DeleteExpiredExports(ctx, time.Now())
+	return err
+}
+
+// DeleteAccount schedules the account of a user to be anonymised once the grace period ends, after they confirm their identity. The user can cancel it until then
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uuid.UUID, req model.DeleteAccountReq) (*model.DeleteAccountRes, error) {
+	if err := s.authService.confirmIdentity(ctx, userID, req.Password, req.Code, req.RecoveryCode); err != nil {
+		return nil, err
+	}
+
+	scheduledAt, err := s.accountRepo.ScheduleDeletion(ctx, userID, time.Now().Add(s.cfg.AccountDeletionGracePeriod))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	s.authService.recordSecurityEvent(ctx, &userID, model.AccountDeletionScheduled)
+	if user, err := s.authService.getUser(ctx, userID); err == nil {
+		s.authService.sendEmail(ctx, user.Email, "Your Chow account will be deleted",
+			fmt.Sprintf("Hi %s,\n\nYour Chow account will be deleted on %s. Your joints, votes and complaints will stay on Chow without your name, and the rest of your data will be removed.\nIf you change your mind, log in and cancel the deletion before then.\n", user.Username, scheduledAt.Format(time.RFC1123)))
+	}
+	return &model.DeleteAccountRes{DeletionScheduledAt: scheduledAt}, nil
+}
+
+// CancelAccountDeletion keeps the account of a user that was scheduled for deletion
+func (s *AccountService) CancelAccountDeletion(ctx context.Context, userID uuid.UUID) error {
+	if err := s.accountRepo.CancelDeletion(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAccountDeletionNotScheduled
+		}
+		return err
+	}
+
+	s.authService.recordSecurityEvent(ctx, &userID, model.AccountDeletionCancelled)
+	return nil
+}
+
+// DeleteDueAccounts anonymises the accounts whose grace period has ended
+func (s *AccountService) DeleteDueAccounts(ctx context.Context) error {
+	for {
+		ids, err := s.accountRepo.GetDueDeletions(ctx, time.Now(), accountDeletionBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var errs []error
+		for _, id := range ids {
+			if err := s.anonymiseAccount(ctx, id); err != nil {
+				errs = append(errs, fmt.Errorf("anonymise account %s: %w", id, err))
+			}
+		}
+		// accounts that failed stay due so they would be claimed again straight away
+		if len(errs) > 0 || len(ids) < accountDeletionBatchSize || ctx.Err() != nil {
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// anonymiseAccount removes the personal data of an account whose deletion is due and tells its owner at their old email
+func (s *AccountService) anonymiseAccount(ctx context.Context, userID uuid.UUID) error {
+	tx, err := s.accountRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// the deletion may have been cancelled since the account was found
+	email, err := s.accountRepo.LockDueDeletion(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if err := s.accountRepo.Anonymise(ctx, tx, userID, loginKey(email)); err != nil {
+		return err
+	}
+	// the votes of the account are kept but its reputation is gone, so they now count as those of an ordinary voter
+	if err := s.jointRepo.UpdateVoterScores(ctx, tx, userID, voteWeights(s.cfg)); err != nil {
+		return err
+	}
+
+	// the account is its own actor since the deletion was asked for by its owner
+	ctx = WithRequestInfo(ctx, model.RequestInfo{ActorID: &userID})
+	if err := recordAudit(ctx, tx, s.auditRepo, model.UserAnonymisedAction, model.UserTarget, userID, nil, nil); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.authService.sendEmail(ctx, email, "Your Chow account has been deleted",
+		"Hi,\n\nYour Chow account has been deleted as you asked. Your personal data has been removed and your contributions no longer show your name.\n")
+	return nil
+}
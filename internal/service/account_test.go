@@ -0,0 +1,48 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func newTestAccountService(t *testing.T) (*AccountService, sqlmock.Sqlmock, *recordingMailer) {
+	db, mock := newMockDB(t)
+	cfg := &config.Config{VerifiedVoteWeight: 1.5, TrustedVoteWeight: 2}
+	mailer := &recordingMailer{}
+	service := NewAccountService(cfg, repository.NewAccountRepository(db), repository.NewJointRepository(db), repository.NewAuditRepository(db), &AuthService{mailer: mailer})
+	return service, mock, mailer
+}
+
+func TestAnonymiseAccountUpdatesVoterScores(t *testing.T) {
+	service, mock, mailer := newTestAccountService(t)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT email\s+FROM users\s+WHERE id = \$1 AND deletion_scheduled_at <= NOW\(\)`).WithArgs(userID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"email"}).AddRow(testEmail))
+	mock.ExpectExec(`UPDATE votes SET ip_address = NULL, fingerprint = NULL`).WithArgs(userID.String()).WillReturnResult(sqlmock.NewResult(0, 3))
+	for range 13 {
+		mock.ExpectExec(`DELETE FROM \w+ WHERE user_id = \$1`).WithArgs(userID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectExec(`DELETE FROM login_attempts`).WithArgs(loginKey(testEmail)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`UPDATE users\s+SET email = 'deleted-'`).WithArgs(userID.String(), string(model.AppUser)).WillReturnResult(sqlmock.NewResult(0, 1))
+	// the reputation of the account was reset so the joints it voted on are scored again before the transaction commits
+	weights := voteWeights(service.cfg)
+	mock.ExpectExec(`UPDATE joints\s+SET score = `).WithArgs(weights.Verified, weights.Trusted, weights.TrustedReputation, userID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	expectAudit(mock, string(model.UserAnonymisedAction))
+	mock.ExpectCommit()
+
+	if err := service.anonymiseAccount(context.Background(), userID); err != nil {
+		t.Fatalf("anonymiseAccount() error = %v", err)
+	}
+	if len(mailer.subjects) != 1 {
+		t.Errorf("sent emails %v, want the account deleted email", mailer.subjects)
+	}
+}
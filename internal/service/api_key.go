@@ -0,0 +1,191 @@
+package service
+
+import (
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrInvalidAPIKey        = errors.New("invalid API key")
+	ErrExpiredAPIKey        = errors.New("API key has expired")
+	ErrInsufficientScope    = errors.New("API key does not have the scope required for this route")
+	ErrAPIKeyNotFound       = errors.New("API key not found")
+	ErrAPIKeyLimitReached   = errors.New("maximum number of API keys reached")
+	ErrInvalidAPIKeyExpiry  = errors.New("API key expiry must be in the future and within the longest lifetime allowed")
+	ErrAdminScopeNotAllowed = errors.New("only admins and moderators can create keys with the admin scope")
+)
+
+const (
+	// maxAPIKeys is the number of active API keys a user can have
+	maxAPIKeys = 20
+	// apiKeyPrefix starts every API key so that leaked keys are easy to recognise
+	apiKeyPrefix = "chow_"
+	// apiKeyUseInterval is how often the last use of a key is recorded
+	apiKeyUseInterval = time.Minute
+)
+
+// CreateAPIKey creates a personal API key for a user. The key is returned along with it and cannot be retrieved again.
+// Keys with the admin scope act with the privileges of their owner, so only admins and moderators can create them. Owners whose role requires two-factor authentication
+// must have it enabled, as the key is used without the second factor
+func (s *AuthService) CreateAPIKey(ctx context.Context, user model.AuthenticatedUser, req model.CreateAPIKeyReq) (*model.CreateAPIKeyRes, error) {
+	scopes := make([]model.APIKeyScope, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		scopes = append(scopes, model.APIKeyScope(scope))
+	}
+	if slices.Contains(scopes, model.AdminScope) && user.Role != model.Admin && user.Role != model.Moderator {
+		return nil, ErrAdminScopeNotAllowed
+	}
+	if slices.Contains(scopes, model.AdminScope) && s.RequiresTwoFactor(user.Role) {
+		if _, err := s.getEnabledTwoFactor(ctx, user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.APIKeyMaxLifetime)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) || req.ExpiresAt.After(expiresAt) {
+			return nil, ErrInvalidAPIKeyExpiry
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	prefix, key, err := generateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.apiKeyRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	count, err := s.apiKeyRepo.CountActive(ctx, tx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAPIKeys {
+		return nil, ErrAPIKeyLimitReached
+	}
+
+	data := &model.APIKey{
+		UserID:    user.ID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+	apiKey, err := s.apiKeyRepo.Create(ctx, tx, data)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.recordSecurityEvent(ctx, &user.ID, model.APIKeyCreated)
+	if owner, err := s.getUser(ctx, user.ID); err == nil {
+		s.sendEmail(ctx, owner.Email, "An API key was created for your Chow account",
+			fmt.Sprintf("Hi %s,\n\nThe API key %q (%s...) was created for your account. If it was not you, revoke it and contact us straight away.\n", owner.Username, apiKey.Name, apiKey.Prefix))
+	}
+	return &model.CreateAPIKeyRes{APIKey: apiKey, Key: key}, nil
+}
+
+// GetAPIKeys returns the API keys of a user, newest first
+func (s *AuthService) GetAPIKeys(ctx context.Context, userID uuid.UUID) ([]*model.APIKey, error) {
+	return s.apiKeyRepo.GetByUserID(ctx, userID)
+}
+
+// RevokeAPIKey stops an API key of a user from being used
+func (s *AuthService) RevokeAPIKey(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.apiKeyRepo.Revoke(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+
+	s.recordSecurityEvent(ctx, &userID, model.APIKeyRevoked)
+	return nil
+}
+
+// AuthenticateAPIKey returns the owner of an API key along with the key. The owner acts as a regular user unless the key has the admin scope.
+// Like sessions, admin keys of roles that require two-factor authentication only get the privileges of the role while the owner has it enabled
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (*model.AuthenticatedUser, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, hashToken(key))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	if !apiKey.ExpiresAt.After(time.Now()) {
+		return nil, ErrExpiredAPIKey
+	}
+
+	// the role is read from the owner on every request so that a demotion applies to their keys straight away
+	user, err := s.getUser(ctx, apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	role := user.Role
+	if !apiKey.HasScope(model.AdminScope) {
+		role = model.AppUser
+	} else if s.RequiresTwoFactor(role) {
+		// checked on every request too so that disabling two-factor authentication applies to keys created before
+		if _, err := s.getEnabledTwoFactor(ctx, user.ID); err != nil {
+			if !errors.Is(err, ErrTwoFactorNotEnabled) {
+				return nil, err
+			}
+			role = model.AppUser
+		}
+	}
+
+	// the key was used regardless of whether its use could be recorded
+	if err := s.apiKeyRepo.RecordUse(ctx, apiKey.ID, RequestInfoFromContext(ctx).IPAddress, apiKeyUseInterval); err != nil {
+		log.Printf("failed to record use of API key %s: %v", apiKey.ID, err)
+	}
+
+	return &model.AuthenticatedUser{ID: user.ID, Username: user.Username, Role: role, APIKey: apiKey}, nil
+}
+
+// generateAPIKey returns a new API key along with the prefix it is identified by
+func generateAPIKey() (prefix, key string, err error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	secret, err := generateToken()
+	if err != nil {
+		return "", "", err
+	}
+	prefix = apiKeyPrefix + hex.EncodeToString(b)
+	return prefix, prefix + "_" + secret, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+const testAPIKey = apiKeyPrefix + "0a1b2c3d4e5f_secret"
+
+func newTestAPIKeyService(t *testing.T) (*AuthService, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	cfg := &config.Config{APIKeyMaxLifetime: 90 * 24 * time.Hour, TwoFactorRequiredRoles: []string{string(model.Admin), string(model.Moderator)}}
+	service := NewAuthService(cfg, repository.NewUserRepository(db), repository.NewSecurityRepository(db), repository.NewTwoFactorRepository(db),
+		repository.NewIdentityRepository(db), repository.NewAPIKeyRepository(db), nil, &recordingMailer{})
+	return service, mock
+}
+
+// expectTwoFactor expects the two-factor authentication of the user to be read, answering with an enabled one if the user has it
+func expectTwoFactor(mock sqlmock.Sqlmock, userID uuid.UUID, enabled bool) {
+	rows := sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_used_step", "created_at"})
+	if enabled {
+		rows.AddRow(userID, "secret", time.Now(), nil, time.Now())
+	}
+	mock.ExpectQuery(`FROM user_two_factor`).WithArgs(userID.String()).WillReturnRows(rows)
+}
+
+// expectAdminAPIKey expects an admin key of the user to be looked up along with its owner
+func expectAdminAPIKey(mock sqlmock.Sqlmock, user *model.User) uuid.UUID {
+	id := uuid.New()
+	mock.ExpectQuery(`FROM api_keys WHERE key_hash = \$1`).WithArgs(hashToken(testAPIKey)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", "last_used_ip", "revoked_at", "created_at"}).
+			AddRow(id, user.ID, "ops", apiKeyPrefix+"0a1b2c3d4e5f", hashToken(testAPIKey), []byte(`["admin"]`), time.Now().Add(time.Hour), nil, nil, nil, time.Now()))
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(user.ID.String()).WillReturnRows(userRow(user, "$2a$10$hash"))
+	return id
+}
+
+func TestAuthenticateAPIKeyRequiresTwoFactorForPrivilegedRole(t *testing.T) {
+	tests := []struct {
+		name      string
+		twoFactor bool
+		want      model.UserRole
+	}{
+		// the key would otherwise act as an admin without the second factor a session of the admin needs
+		{"without two-factor", false, model.AppUser},
+		{"with two-factor", true, model.Admin},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mock := newTestAPIKeyService(t)
+			user := testOIDCUser(true)
+			user.Role = model.Admin
+
+			id := expectAdminAPIKey(mock, user)
+			expectTwoFactor(mock, user.ID, tt.twoFactor)
+			mock.ExpectExec(`UPDATE api_keys\s+SET last_used_at = NOW\(\)`).WithArgs(id.String(), nil, apiKeyUseInterval.Seconds()).WillReturnResult(sqlmock.NewResult(0, 1))
+
+			authenticated, err := service.AuthenticateAPIKey(context.Background(), testAPIKey)
+			if err != nil {
+				t.Fatalf("AuthenticateAPIKey() error = %v", err)
+			}
+			if authenticated.Role != tt.want {
+				t.Errorf("AuthenticateAPIKey() role = %s, want %s", authenticated.Role, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyRequiresTwoFactorForAdminScope(t *testing.T) {
+	service, mock := newTestAPIKeyService(t)
+	user := model.AuthenticatedUser{ID: uuid.New(), Username: "ama_m", Role: model.Moderator}
+
+	expectTwoFactor(mock, user.ID, false)
+
+	_, err := service.CreateAPIKey(context.Background(), user, model.CreateAPIKeyReq{Name: "ops", Scopes: []string{string(model.AdminScope)}})
+	if !errors.Is(err, ErrTwoFactorNotEnabled) {
+		t.Fatalf("CreateAPIKey() error = %v, want %v", err, ErrTwoFactorNotEnabled)
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// sensitiveAuditFields are never captured in audit snapshots, regardless of how the record is serialized
+var sensitiveAuditFields = []string{"password", "secret", "token", "hash"}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo attaches the request origin to the context so that changes made while handling the request can be audited
+func WithRequestInfo(ctx context.Context, info model.RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request origin attached to the context, if any
+func RequestInfoFromContext(ctx context.Context) model.RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(model.RequestInfo)
+	return info
+}
+
+type AuditService struct {
+	cfg       *config.Config
+	auditRepo *repository.AuditRepository
+}
+
+func NewAuditService(cfg *config.Config, auditRepo *repository.AuditRepository) *AuditService {
+	return &AuditService{
+		cfg:       cfg,
+		auditRepo: auditRepo,
+	}
+}
+
+// GetAuditEvents returns a page of audit events matching the filter along with the cursor of the next page, if there is one
+func (s *AuditService) GetAuditEvents(ctx context.Context, filter model.AuditEventFilter, query model.PaginationQuery) (*model.AuditEventPage, *model.Pagination, error) {
+	// the cursor is bound to the filter it was issued for
+	scope, _ := json.Marshal(filter)
+	events, pagination, err := readPage(s.cfg, query, "audit-events:"+string(scope), func(page model.Page) (*model.Paged[*model.AuditEvent], error) {
+		return s.auditRepo.GetAll(ctx, filter, page)
+	}, func(event *model.AuditEvent) []any {
+		return []any{event.CreatedAt, event.ID}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &model.AuditEventPage{Events: events, NextCursor: pagination.NextCursor}, pagination, nil
+}
+
+// ExportAuditEvents calls fn for every audit event matching the filter in chronological order
+func (s *AuditService) ExportAuditEvents(ctx context.Context, filter model.AuditEventFilter, fn func(*model.AuditEvent) error) error {
+	return s.auditRepo.Stream(ctx, filter, fn)
+}
+
+// recordAudit writes an audit event for a change within the transaction of the change. The actor, IP address and request ID are taken from the request info in the context
+func recordAudit(ctx context.Context, tx *sql.Tx, auditRepo *repository.AuditRepository, action model.AuditAction, targetType model.TargetType, targetID uuid.UUID, before, after any) error {
+	beforeJSON, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	info := RequestInfoFromContext(ctx)
+	event := &model.AuditEvent{
+		ActorID:    info.ActorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+	}
+	if info.IPAddress != "" {
+		event.IPAddress = &info.IPAddress
+	}
+	if info.RequestID != "" {
+		event.RequestID = &info.RequestID
+	}
+
+	_, err = auditRepo.Create(ctx, tx, event)
+	return err
+}
+
+// auditSnapshot serializes a record for the audit log with sensitive fields removed. nil records produce an empty snapshot
+func auditSnapshot(record any) (json.RawMessage, error) {
+	if record == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(redactSensitiveFields(fields))
+}
+
+// redactSensitiveFields removes object keys that look like credentials at any depth
+func redactSensitiveFields(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSensitiveAuditField(key) {
+				delete(v, key)
+				continue
+			}
+			v[key] = redactSensitiveFields(field)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactSensitiveFields(item)
+		}
+	}
+	return value
+}
+
+func isSensitiveAuditField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range sensitiveAuditFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
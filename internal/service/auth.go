@@ -6,76 +6,182 @@ import (
 	"chow/internal/repository"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // errors
 var (
-	ErrAlreadyExist       = errors.New("user already exists")
+	ErrUsernameTaken      = errors.New("username is already taken")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrExpiredToken       = errors.New("token has expired")
 	ErrInvalidToken       = errors.New("invalid token")
 )
 
 type AuthService struct {
-	cfg      *config.Config
-	userRepo *repository.UserRepository
+	cfg           *config.Config
+	userRepo      *repository.UserRepository
+	securityRepo  *repository.SecurityRepository
+	twoFactorRepo *repository.TwoFactorRepository
+	identityRepo  *repository.IdentityRepository
+	apiKeyRepo    *repository.APIKeyRepository
+	// signingKeyService holds the keys tokens are signed and verified with
+	signingKeyService *SigningKeyService
+	mailer            Mailer
+	oidc              *oidcClient
+	// dummyHash is compared with the passwords given for unknown emails so that they take as long to reject as wrong passwords
+	dummyHash string
 }
 
-func NewAuthService(cfg *config.Config, userRepo *repository.UserRepository) *AuthService {
+func NewAuthService(cfg *config.Config, userRepo *repository.UserRepository, securityRepo *repository.SecurityRepository, twoFactorRepo *repository.TwoFactorRepository, identityRepo *repository.IdentityRepository, apiKeyRepo *repository.APIKeyRepository, signingKeyService *SigningKeyService, mailer Mailer) *AuthService {
+	dummyHash, err := bcrypt.GenerateFromPassword([]byte(uuid.NewString()), bcrypt.DefaultCost)
+	if err != nil {
+		panic(err)
+	}
 	return &AuthService{
-		cfg:      cfg,
-		userRepo: userRepo,
+		cfg:               cfg,
+		userRepo:          userRepo,
+		securityRepo:      securityRepo,
+		twoFactorRepo:     twoFactorRepo,
+		identityRepo:      identityRepo,
+		apiKeyRepo:        apiKeyRepo,
+		oidc:              newOIDCClient(),
+		signingKeyService: signingKeyService,
+		mailer:            mailer,
+		dummyHash:         string(dummyHash),
 	}
 }
 
-// Register
-func (s *AuthService) Register(ctx context.Context, data *model.User) (*model.User, error) {
+// Register creates a user account. Registering an email that already has an account succeeds in the same way so that the response does not reveal which emails are registered.
+// The owner of the email is told by email instead
+func (s *AuthService) Register(ctx context.Context, data *model.User) error {
+	// hash password. it is done for registered emails too so that they take as long as new ones
+	password, err := s.hashPassword(data.Password)
+	if err != nil {
+		return err
+	}
+
 	// check if user exists
-	_, err := s.userRepo.GetUserByEmailOrUsername(ctx, "email", data.Email)
+	existing, err := s.userRepo.GetUserByEmailOrUsername(ctx, "email", data.Email)
 	if err == nil {
-		return nil, ErrAlreadyExist
+		s.sendEmail(ctx, existing.Email, "Someone tried to sign up with your email",
+			fmt.Sprintf("Hi %s,\n\nSomeone tried to create a Chow account with this email, which already has an account. If it was you, log in with your existing account instead.\nIf it was not you, you can ignore this email.\n", existing.Username))
+		return nil
 	}
-	if err != repository.ErrNotFound {
-		return nil, err
+	if !errors.Is(err, repository.ErrNotFound) {
+		return err
 	}
 
-	// hash password
-	data.Password, err = s.hashPassword(data.Password)
-	if err != nil {
-		return nil, err
+	// usernames are public so a taken one is reported
+	if _, err := s.userRepo.GetUserByEmailOrUsername(ctx, "username", data.Username); err == nil {
+		return ErrUsernameTaken
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
 	}
 
+	data.Password = password
+
 	// set default user role
 	data.Role = model.AppUser
 
-	// create user
-	return s.userRepo.Create(ctx, data)
+	tx, err := s.userRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// create user. the email was checked above so a conflict here is the username registered in the meantime
+	user, err := s.userRepo.Create(ctx, tx, data)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExist) {
+			return ErrUsernameTaken
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.sendEmail(ctx, user.Email, "Welcome to Chow",
+		fmt.Sprintf("Hi %s,\n\nYour Chow account is ready. Log in to start discovering food spots near you.\n", user.Username))
+	return nil
 }
 
-// Login authenticates a user and generate their access token
-func (s *AuthService) Login(ctx context.Context, email, password string) (*model.User, string, error) {
+// Login authenticates a user and generate their access token. Users with two-factor authentication get a pre-auth token to verify with a code instead.
+// Repeated failures delay further attempts and eventually lock the email, whether or not it has an account
+func (s *AuthService) Login(ctx context.Context, email, password string) (*model.LoginUserRes, error) {
+	now := time.Now()
+	key := loginKey(email)
+	if err := s.checkLoginAllowed(ctx, key, now); err != nil {
+		return nil, err
+	}
+
 	// check if user exists
 	user, err := s.userRepo.GetUserByEmailOrUsername(ctx, "email", email)
-	if err != nil {
-		return nil, "", ErrInvalidCredentials
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	// verify password. unknown emails and users without a password are checked against a dummy hash so that they take as long as wrong passwords
+	hashedPassword := s.dummyHash
+	if user != nil && user.HasPassword {
+		hashedPassword = user.Password
 	}
+	if err := s.verifyPassword(password, hashedPassword); err != nil || user == nil || !user.HasPassword {
+		if err := s.recordFailedLogin(ctx, key, user, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.signIn(ctx, user)
+}
+
+// signIn logs in a user who has proven their identity with a password or provider. Users with two-factor authentication get a pre-auth token to verify with a code instead
+func (s *AuthService) signIn(ctx context.Context, user *model.User) (*model.LoginUserRes, error) {
+	// failed logins are only cleared once the second factor is verified so that codes cannot be guessed indefinitely
+	if _, err := s.getEnabledTwoFactor(ctx, user.ID); err == nil {
+		token, err := s.signToken(user, preAuthTokenType, s.cfg.PreAuthTokenExpiry, nil)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginUserRes{TwoFactorRequired: true, PreAuthToken: token}, nil
+	} else if !errors.Is(err, ErrTwoFactorNotEnabled) {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, false)
+}
 
-	// verify password
-	if err := s.verifyPassword(password, user.Password); err != nil {
-		return nil, "", ErrInvalidCredentials
+// completeLogin clears the failed logins of a user who has proven their identity and generates their access token
+func (s *AuthService) completeLogin(ctx context.Context, user *model.User, twoFactor bool) (*model.LoginUserRes, error) {
+	if err := s.securityRepo.ClearLoginAttempts(ctx, loginKey(user.Email)); err != nil {
+		return nil, err
 	}
+	s.recordSecurityEvent(ctx, &user.ID, model.LoginSucceeded)
 
 	// generate access token
-	token, err := s.generateAccessToken(user)
+	token, err := s.generateAccessToken(user, twoFactor)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
+	return &model.LoginUserRes{User: user, AccessToken: token}, nil
+}
 
-	return user, token, err
+// getUser returns a user by ID or ErrUserNotFound
+func (s *AuthService) getUser(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
 }
 
 // forgot password
@@ -85,41 +191,83 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (*model
 
 // token helpers (generate and validate)
 
-func (s *AuthService) generateAccessToken(user *model.User) (string, error) {
-	now := time.Now()
-	expiry := now.Add(s.cfg.JWTExpiryMinutes)
+// token types. pre-auth tokens only prove the password of a user with two-factor authentication and cannot be used as access tokens
+const (
+	accessTokenType  = "access"
+	preAuthTokenType = "pre_auth"
+)
 
-	// create token with claims
-	claims := jwt.MapClaims{
-		"sub":      user.ID.String(),
+// generateAccessToken returns the access token of a user. twoFactor records whether the user completed two-factor authentication
+func (s *AuthService) generateAccessToken(user *model.User, twoFactor bool) (string, error) {
+	return s.signToken(user, accessTokenType, s.cfg.JWTExpiryMinutes, jwt.MapClaims{
 		"username": user.Username,
 		"email":    user.Email,
 		"role":     user.Role,
-		"iat":      now.Unix(),
-		"exp":      expiry.Unix(),
+		"mfa":      twoFactor,
+	})
+}
+
+// signToken returns a token of the type for a user that expires after the duration. It is signed with the newest signing key, which is named in the kid header
+func (s *AuthService) signToken(user *model.User, tokenType string, expiresIn time.Duration, extra jwt.MapClaims) (string, error) {
+	key, err := s.signingKeyService.signingKey()
+	if err != nil {
+		return "", err
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	// sign token with secret key
-	tokenString, err := token.SignedString([]byte(s.cfg.JWTSecret))
+	now := time.Now()
+	expiry := now.Add(expiresIn)
+
+	// create token with claims
+	claims := jwt.MapClaims{
+		"iss": s.cfg.JWTIssuer,
+		"aud": s.cfg.JWTAudience,
+		"sub": user.ID.String(),
+		"typ": tokenType,
+		"iat": now.Unix(),
+		"exp": expiry.Unix(),
+	}
+	for key, value := range extra {
+		claims[key] = value
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.kid
+
+	// sign token with private key
+	tokenString, err := token.SignedString(key.private)
 	if err != nil {
 		return "", err
 	}
 	return tokenString, nil
 }
 
-func (s *AuthService) ValidateToken(token string) (jwt.MapClaims, error) {
+// ValidateToken verifies an access token and returns its claims
+func (s *AuthService) ValidateToken(ctx context.Context, token string) (jwt.MapClaims, error) {
+	return s.parseToken(ctx, token, accessTokenType)
+}
+
+// parseToken verifies a token of the given type and returns its claims
+func (s *AuthService) parseToken(ctx context.Context, token, tokenType string) (jwt.MapClaims, error) {
 	// parse token
 	parsedToken, err := jwt.Parse(token, func(parsedToken *jwt.Token) (any, error) {
-		// validate signing method
-		if _, ok := parsedToken.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := parsedToken.Header["kid"].(string)
+		key, err := s.signingKeyService.verificationKey(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		// validate signing method against the key rather than trusting the token
+		if parsedToken.Method.Alg() != key.method.Alg() {
 			return nil, ErrInvalidToken
 		}
-		return []byte(s.cfg.JWTSecret), nil
-	})
+		return key.public, nil
+	},
+		jwt.WithValidMethods([]string{config.EdDSAAlgorithm, config.RS256Algorithm}),
+		jwt.WithIssuer(s.cfg.JWTIssuer),
+		jwt.WithAudience(s.cfg.JWTAudience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
 		// check for expiry
-		if err == jwt.ErrTokenExpired {
+		if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, ErrExpiredToken
 		}
 		return nil, ErrInvalidToken
@@ -130,10 +278,23 @@ func (s *AuthService) ValidateToken(token string) (jwt.MapClaims, error) {
 	if !ok || !parsedToken.Valid {
 		return nil, ErrInvalidToken
 	}
+	if typ, _ := claims["typ"].(string); typ != tokenType {
+		return nil, ErrInvalidToken
+	}
 
 	return claims, nil
 }
 
+// claimUserID returns the user ID in the subject of a token
+func claimUserID(claims jwt.MapClaims) (uuid.UUID, error) {
+	sub, _ := claims["sub"].(string)
+	userID, err := uuid.Parse(sub)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return userID, nil
+}
+
 // password utils
 
 func (s *AuthService) hashPassword(password string) (string, error) {
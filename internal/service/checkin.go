@@ -0,0 +1,124 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrCheckinTooFar  = errors.New("too far from the joint to check in")
+	ErrCheckinTooSoon = errors.New("already checked in at this joint recently")
+)
+
+// CheckinCooldownError is returned when a user checks in at a joint again before the cooldown has elapsed
+type CheckinCooldownError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CheckinCooldownError) Error() string {
+	return fmt.Sprintf("%s, try again in %s", ErrCheckinTooSoon, e.RetryAfter.Round(time.Second))
+}
+
+func (e *CheckinCooldownError) Is(target error) bool {
+	return target == ErrCheckinTooSoon
+}
+
+type CheckinService struct {
+	cfg         *config.Config
+	checkinRepo *repository.CheckinRepository
+	jointRepo   *repository.JointRepository
+	voteRepo    *repository.VoteRepository
+	outboxRepo  *repository.OutboxRepository
+}
+
+func NewCheckinService(cfg *config.Config, checkinRepo *repository.CheckinRepository, jointRepo *repository.JointRepository, voteRepo *repository.VoteRepository, outboxRepo *repository.OutboxRepository) *CheckinService {
+	return &CheckinService{
+		cfg:         cfg,
+		checkinRepo: checkinRepo,
+		jointRepo:   jointRepo,
+		voteRepo:    voteRepo,
+		outboxRepo:  outboxRepo,
+	}
+}
+
+// CreateCheckin records a visit to a joint when the provided coordinates are within the configured tolerance of it.
+// An existing vote of the user on the joint is flagged as verified and the joint score is updated to reflect its new weight
+func (s *CheckinService) CreateCheckin(ctx context.Context, userID, jointID uuid.UUID, coord model.Coordinate) (*model.Checkin, error) {
+	// check-ins are only allowed on publicly listed joints
+	joint, err := s.jointRepo.GetByID(ctx, jointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+	if !joint.IsApproved || joint.Visibility != model.VisibleJoint {
+		return nil, ErrJointNotFound
+	}
+
+	tx, err := s.checkinRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// enforce the cooldown under a lock so that concurrent requests cannot both pass it
+	if err := s.checkinRepo.LockUserJoint(ctx, tx, userID, jointID); err != nil {
+		return nil, err
+	}
+	latest, err := s.checkinRepo.GetLatestUserJointCheckin(ctx, tx, userID, jointID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if latest != nil {
+		if elapsed := time.Since(latest.CreatedAt); elapsed < s.cfg.CheckinCooldown {
+			return nil, &CheckinCooldownError{RetryAfter: s.cfg.CheckinCooldown - elapsed}
+		}
+	}
+
+	data := &model.Checkin{UserID: userID, JointID: jointID, Latitude: coord.Latitude, Longitude: coord.Longitude}
+	checkin, err := s.checkinRepo.Create(ctx, tx, data, s.cfg.CheckinTolerance)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrCheckinTooFar
+		}
+		return nil, err
+	}
+
+	// votes cast before the first visit are verified retroactively
+	verified, err := s.voteRepo.MarkVerified(ctx, tx, userID, jointID)
+	if err != nil {
+		return nil, err
+	}
+	if verified {
+		if _, err := s.jointRepo.UpdateScore(ctx, tx, jointID, voteWeights(s.cfg)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.CheckinCreatedEvent, checkin.ID, checkin); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return checkin, err
+}
+
+// GetVisitHistory retrieves the check-ins of a user, newest first
+func (s *CheckinService) GetVisitHistory(ctx context.Context, userID uuid.UUID, query model.PaginationQuery) ([]*model.Visit, *model.Pagination, error) {
+	return readPage(s.cfg, query, "visits:"+userID.String(), func(page model.Page) (*model.Paged[*model.Visit], error) {
+		return s.checkinRepo.GetUserCheckins(ctx, userID, page)
+	}, func(visit *model.Visit) []any {
+		return []any{visit.CreatedAt, visit.ID}
+	})
+}
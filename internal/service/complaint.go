@@ -5,7 +5,13 @@ import (
 	"chow/internal/model"
 	"chow/internal/repository"
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -16,22 +22,70 @@ var (
 	ErrComplaintNotFound     = errors.New("complaint not found")
 )
 
+// hiddenJointBatchSize is the number of joints under review read at a time when the moderation rules are applied again
+const hiddenJointBatchSize = 100
+
+// moderationRule hides a joint once the number of distinct users with open complaints in a category reaches the threshold
+type moderationRule struct {
+	Category  model.ComplaintCategory
+	Threshold int
+}
+
 type ComplaintService struct {
 	cfg           *config.Config
 	complaintRepo *repository.ComplaintRepository
+	jointRepo     *repository.JointRepository
+	auditRepo     *repository.AuditRepository
+	outboxRepo    *repository.OutboxRepository
+	notifier      Notifier
+	rules         []moderationRule
 }
 
-func NewComplaintService(cfg *config.Config, complaintRepo *repository.ComplaintRepository) *ComplaintService {
+func NewComplaintService(cfg *config.Config, complaintRepo *repository.ComplaintRepository, jointRepo *repository.JointRepository, auditRepo *repository.AuditRepository, outboxRepo *repository.OutboxRepository, notifier Notifier) *ComplaintService {
+	// build moderation rules from config. non-positive thresholds disable the rule for that category
+	rules := make([]moderationRule, 0, len(cfg.ComplaintHideThresholds))
+	for category, threshold := range cfg.ComplaintHideThresholds {
+		if threshold <= 0 {
+			continue
+		}
+		rules = append(rules, moderationRule{Category: model.ComplaintCategory(category), Threshold: threshold})
+	}
+	slices.SortFunc(rules, func(a, b moderationRule) int { return strings.Compare(string(a.Category), string(b.Category)) })
+
 	return &ComplaintService{
 		cfg:           cfg,
 		complaintRepo: complaintRepo,
+		jointRepo:     jointRepo,
+		auditRepo:     auditRepo,
+		outboxRepo:    outboxRepo,
+		notifier:      notifier,
+		rules:         rules,
 	}
 }
 
 func (s *ComplaintService) CreateComplaint(ctx context.Context, data *model.Complaint) (*model.Complaint, error) {
 	// set complaint status to open
 	data.Status = model.OpenComplaint
-	complaint, err := s.complaintRepo.Create(ctx, data)
+	if data.Category == "" {
+		data.Category = model.OtherComplaint
+	}
+
+	tx, err := s.complaintRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// complaints can only be made against joints that have not been deleted. the joint stays locked until the moderation rules are applied
+	joint, err := s.jointRepo.LockByID(ctx, tx, data.JointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	complaint, err := s.complaintRepo.Create(ctx, tx, data)
 	if err != nil {
 		if errors.Is(err, repository.ErrAlreadyExist) {
 			return nil, ErrComplaintAlreadyExist
@@ -39,27 +93,56 @@ func (s *ComplaintService) CreateComplaint(ctx context.Context, data *model.Comp
 		return nil, err
 	}
 
-	// TODO: notify admins that new complaint has been submitted
+	if err := recordAudit(ctx, tx, s.auditRepo, model.ComplaintCreatedAction, model.ComplaintTarget, complaint.ID, nil, complaint); err != nil {
+		return nil, err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.ComplaintFiledEvent, complaint.ID, complaint); err != nil {
+		return nil, err
+	}
+	notification, err := s.applyModerationRules(ctx, tx, joint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.notifyVisibilityChange(ctx, joint, notification)
 	return complaint, err
 }
 
-func (s *ComplaintService) GetAllComplaints(ctx context.Context, offset, limit int) ([]*model.Complaint, error) {
-	return s.complaintRepo.GetAll(ctx, offset, limit)
+// GetAllComplaints retrieves the complaints on joints that have not been deleted, newest first
+func (s *ComplaintService) GetAllComplaints(ctx context.Context, query model.PaginationQuery) ([]*model.Complaint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "complaints", func(page model.Page) (*model.Paged[*model.Complaint], error) {
+		return s.complaintRepo.GetAll(ctx, page)
+	}, complaintKey)
 }
 
 // GetUserJointComplaints retrieves all complaints made against a joint by a specific user
-func (s *ComplaintService) GetUserJointComplaints(ctx context.Context, userID, jointID uuid.UUID, offset, limit int) ([]*model.Complaint, error) {
-	return s.complaintRepo.GetUserJointComplaints(ctx, userID, jointID, offset, limit)
+func (s *ComplaintService) GetUserJointComplaints(ctx context.Context, userID, jointID uuid.UUID, query model.PaginationQuery) ([]*model.Complaint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "user-joint-complaints:"+userID.String()+":"+jointID.String(), func(page model.Page) (*model.Paged[*model.Complaint], error) {
+		return s.complaintRepo.GetUserJointComplaints(ctx, userID, jointID, page)
+	}, complaintKey)
 }
 
 // GetJointComplaints retrieves all complaints made against a joint
-func (s *ComplaintService) GetJointComplaints(ctx context.Context, jointID uuid.UUID, offset, limit int) ([]*model.Complaint, error) {
-	return s.complaintRepo.GetJointComplaints(ctx, jointID, offset, limit)
+func (s *ComplaintService) GetJointComplaints(ctx context.Context, jointID uuid.UUID, query model.PaginationQuery) ([]*model.Complaint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "joint-complaints:"+jointID.String(), func(page model.Page) (*model.Paged[*model.Complaint], error) {
+		return s.complaintRepo.GetJointComplaints(ctx, jointID, page)
+	}, complaintKey)
 }
 
 // GetUserComplaints retrieves all complaints made by a user
-func (s *ComplaintService) GetUserComplaints(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*model.Complaint, error) {
-	return s.complaintRepo.GetUserComplaints(ctx, userID, offset, limit)
+func (s *ComplaintService) GetUserComplaints(ctx context.Context, userID uuid.UUID, query model.PaginationQuery) ([]*model.Complaint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "user-complaints:"+userID.String(), func(page model.Page) (*model.Paged[*model.Complaint], error) {
+		return s.complaintRepo.GetUserComplaints(ctx, userID, page)
+	}, complaintKey)
+}
+
+// complaintKey returns the sort key of a complaint in complaint lists
+func complaintKey(complaint *model.Complaint) []any {
+	return []any{complaint.CreatedAt, complaint.ID}
 }
 
 func (s *ComplaintService) GetComplaintByID(ctx context.Context, id uuid.UUID) (*model.Complaint, error) {
@@ -74,8 +157,9 @@ func (s *ComplaintService) GetComplaintByID(ctx context.Context, id uuid.UUID) (
 	return complaint, err
 }
 
+// UpdateComplaintStatusByID changes the status of a complaint and re-applies the moderation rules on the joint, restoring its visibility once enough complaints are closed
 func (s *ComplaintService) UpdateComplaintStatusByID(ctx context.Context, id uuid.UUID, status model.ComplaintStatus) (*model.Complaint, error) {
-	complaint, err := s.complaintRepo.UpdateComplaintStatus(ctx, id, status)
+	current, err := s.complaintRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrComplaintNotFound
@@ -83,5 +167,206 @@ func (s *ComplaintService) UpdateComplaintStatusByID(ctx context.Context, id uui
 		return nil, err
 	}
 
-	return complaint, err
+	tx, err := s.complaintRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the joint is locked before the complaint, as when complaints are filed or joints purged, so that they cannot wait on each other.
+	// complaints on deleted joints can still be closed but no longer affect visibility
+	joint, err := s.jointRepo.LockByID(ctx, tx, current.JointID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	existing, err := s.complaintRepo.LockByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrComplaintNotFound
+		}
+		return nil, err
+	}
+
+	complaint, err := s.complaintRepo.UpdateComplaintStatus(ctx, tx, id, status)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrComplaintNotFound
+		}
+		return nil, err
+	}
+
+	action, eventType := model.ComplaintResolvedAction, model.ComplaintResolvedEvent
+	if status == model.RejectedComplaint {
+		action, eventType = model.ComplaintRejectedAction, model.ComplaintRejectedEvent
+	}
+	if err := recordAudit(ctx, tx, s.auditRepo, action, model.ComplaintTarget, id, existing, complaint); err != nil {
+		return nil, err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, eventType, id, complaint); err != nil {
+		return nil, err
+	}
+
+	var notification *model.Notification
+	if joint != nil {
+		if notification, err = s.applyModerationRules(ctx, tx, joint); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.notifyVisibilityChange(ctx, joint, notification)
+	return complaint, nil
+}
+
+// CreateComplaintReply adds a reply to a complaint and notifies the other party of the conversation
+func (s *ComplaintService) CreateComplaintReply(ctx context.Context, complaint *model.Complaint, data *model.ComplaintReply) (*model.ComplaintReply, error) {
+	tx, err := s.complaintRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	data.ComplaintID = complaint.ID
+	reply, err := s.complaintRepo.CreateReply(ctx, tx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := model.ComplaintRepliedPayload{Reply: reply, Complaint: complaint}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.ComplaintRepliedEvent, complaint.ID, payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return reply, err
+}
+
+// GetComplaintReplies retrieves the replies on a complaint in the order they were made
+func (s *ComplaintService) GetComplaintReplies(ctx context.Context, complaintID uuid.UUID, query model.PaginationQuery) ([]*model.ComplaintReply, *model.Pagination, error) {
+	return readPage(s.cfg, query, "complaint-replies:"+complaintID.String(), func(page model.Page) (*model.Paged[*model.ComplaintReply], error) {
+		return s.complaintRepo.GetReplies(ctx, complaintID, page)
+	}, func(reply *model.ComplaintReply) []any {
+		return []any{reply.CreatedAt, reply.ID}
+	})
+}
+
+// applyModerationRules hides a joint when any moderation rule is triggered by its open complaints within the configured window, and restores it once none is.
+// The joint must be locked by the transaction that changed its complaints so that the visibility is decided one change at a time and committed along with it.
+// It returns the notification to send once the transaction commits, or nil when the visibility did not change
+func (s *ComplaintService) applyModerationRules(ctx context.Context, tx *sql.Tx, joint *model.Joint) (*model.Notification, error) {
+	since := time.Now().Add(-s.cfg.ComplaintHideWindow)
+	counts, err := s.complaintRepo.CountOpenComplainantsByCategory(ctx, tx, joint.ID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var triggered *moderationRule
+	for i, rule := range s.rules {
+		if counts[rule.Category] >= rule.Threshold {
+			triggered = &s.rules[i]
+			break
+		}
+	}
+
+	switch {
+	case triggered != nil && joint.Visibility == model.VisibleJoint:
+		if err := s.updateJointVisibility(ctx, tx, joint, model.UnderReviewJoint); err != nil {
+			return nil, err
+		}
+		return &model.Notification{
+			Title: "Joint hidden pending review",
+			Body:  fmt.Sprintf("%q has been hidden after %d users reported it as %s", joint.Name, counts[triggered.Category], triggered.Category),
+		}, nil
+
+	case triggered == nil && joint.Visibility == model.UnderReviewJoint:
+		if err := s.updateJointVisibility(ctx, tx, joint, model.VisibleJoint); err != nil {
+			return nil, err
+		}
+		return &model.Notification{
+			Title: "Joint visible again",
+			Body:  fmt.Sprintf("%q is listed again after the complaints against it were reviewed", joint.Name),
+		}, nil
+	}
+	return nil, nil
+}
+
+// notifyVisibilityChange tells the moderators and the creator of a joint that its visibility changed. Nothing is sent when there is no notification
+func (s *ComplaintService) notifyVisibilityChange(ctx context.Context, joint *model.Joint, notification *model.Notification) {
+	if notification == nil {
+		return
+	}
+	notification.Type = model.JointVisibilityChangedNotification
+	notification.TargetType = targetType(model.JointTarget)
+	notification.TargetID = &joint.ID
+	if err := s.notifier.NotifyModerators(ctx, notification); err != nil {
+		log.Println(err)
+	}
+	if err := s.notifier.NotifyUser(ctx, joint.CreatorID, notification); err != nil {
+		log.Println(err)
+	}
+}
+
+// reviewJoint applies the moderation rules to a joint on its own, such as once its complaints have left the window
+func (s *ComplaintService) reviewJoint(ctx context.Context, jointID uuid.UUID) error {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	joint, err := s.jointRepo.LockByID(ctx, tx, jointID)
+	if err != nil {
+		return err
+	}
+	notification, err := s.applyModerationRules(ctx, tx, joint)
+	if err != nil || notification == nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.notifyVisibilityChange(ctx, joint, notification)
+	return nil
+}
+
+// ReviewHiddenJoints applies the moderation rules again to the joints under review, restoring those whose complaints have since left the window
+func (s *ComplaintService) ReviewHiddenJoints(ctx context.Context) error {
+	var errs []error
+	after := uuid.Nil
+	for {
+		ids, err := s.jointRepo.GetUnderReview(ctx, after, hiddenJointBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			if err := s.reviewJoint(ctx, id); err != nil && !errors.Is(err, repository.ErrNotFound) {
+				errs = append(errs, fmt.Errorf("review joint %s: %w", id, err))
+			}
+		}
+
+		if len(ids) < hiddenJointBatchSize || ctx.Err() != nil {
+			return errors.Join(errs...)
+		}
+		after = ids[len(ids)-1]
+	}
+}
+
+// updateJointVisibility changes the visibility of a joint locked by the transaction and records it in the audit log
+func (s *ComplaintService) updateJointVisibility(ctx context.Context, tx *sql.Tx, joint *model.Joint, visibility model.JointVisibility) error {
+	updated, err := s.jointRepo.UpdateVisibility(ctx, tx, joint.ID, visibility)
+	if err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointVisibilityChangedAction, model.JointTarget, joint.ID, joint, updated); err != nil {
+		return err
+	}
+	return publishEvent(ctx, tx, s.outboxRepo, model.JointVisibilityChangedEvent, joint.ID, updated)
 }
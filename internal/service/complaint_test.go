@@ -0,0 +1,126 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+// recordingNotifier keeps the titles of the notifications it is asked to send
+type recordingNotifier struct {
+	titles []string
+}
+
+func (n *recordingNotifier) NotifyModerators(ctx context.Context, notification *model.Notification) error {
+	n.titles = append(n.titles, notification.Title)
+	return nil
+}
+
+func (n *recordingNotifier) NotifyUser(ctx context.Context, userID uuid.UUID, notification *model.Notification) error {
+	n.titles = append(n.titles, notification.Title)
+	return nil
+}
+
+func newTestComplaintService(t *testing.T) (*ComplaintService, sqlmock.Sqlmock, *recordingNotifier) {
+	db, mock := newMockDB(t)
+	cfg := &config.Config{ComplaintHideThresholds: map[string]int{string(model.PermanentlyClosedComplaint): 3}, ComplaintHideWindow: 24 * time.Hour}
+	notifier := &recordingNotifier{}
+	service := NewComplaintService(cfg, repository.NewComplaintRepository(db), repository.NewJointRepository(db), repository.NewAuditRepository(db),
+		repository.NewOutboxRepository(db), notifier)
+	return service, mock, notifier
+}
+
+var jointColumns = []string{"id", "name", "latitude", "longitude", "description", "is_approved", "visibility", "category", "creator_id", "photo_url", "upvotes", "downvotes", "score", "created_at", "updated_at"}
+
+func jointRow(joint *model.Joint, visibility model.JointVisibility) *sqlmock.Rows {
+	return sqlmock.NewRows(jointColumns).AddRow(joint.ID, joint.Name, 5.6, -0.18, nil, true, string(visibility), "chop_bar", joint.CreatorID, nil, 4, 0, 4.0, time.Now(), time.Now())
+}
+
+func expectEvent(mock sqlmock.Sqlmock, eventType model.EventType) {
+	mock.ExpectQuery(`INSERT INTO outbox_events`).WithArgs(string(eventType), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "actor_id", "payload", "created_at"}).
+			AddRow(uuid.New(), string(eventType), uuid.New(), nil, []byte(`{}`), time.Now()))
+}
+
+// expectComplaintCreated expects a complaint on the locked joint to be recorded
+func expectComplaintCreated(mock sqlmock.Sqlmock, joint *model.Joint, visibility model.JointVisibility, complaint *model.Complaint) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM joints\s+WHERE id = \$1 AND deleted_at IS NULL\s+FOR UPDATE`).WithArgs(joint.ID.String()).WillReturnRows(jointRow(joint, visibility))
+	mock.ExpectQuery(`INSERT INTO complaints`).WithArgs(joint.ID.String(), complaint.UserID.String(), complaint.Reason, string(complaint.Category), string(model.OpenComplaint)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "joint_id", "user_id", "reason", "category", "status", "created_at", "updated_at"}).
+			AddRow(uuid.New(), joint.ID, complaint.UserID, complaint.Reason, string(complaint.Category), string(model.OpenComplaint), time.Now(), time.Now()))
+	expectAudit(mock, string(model.ComplaintCreatedAction))
+	expectEvent(mock, model.ComplaintFiledEvent)
+}
+
+func expectComplainants(mock sqlmock.Sqlmock, joint *model.Joint, count int) {
+	mock.ExpectQuery(`SELECT category, COUNT\(DISTINCT user_id\)\s+FROM complaints`).WithArgs(joint.ID.String(), string(model.OpenComplaint), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"category", "count"}).AddRow(string(model.PermanentlyClosedComplaint), count))
+}
+
+func testComplaint(joint *model.Joint) *model.Complaint {
+	return &model.Complaint{JointID: joint.ID, UserID: uuid.New(), Reason: "Closed for good", Category: model.PermanentlyClosedComplaint}
+}
+
+func TestCreateComplaintHidesJointInSameTransaction(t *testing.T) {
+	service, mock, notifier := newTestComplaintService(t)
+	joint := &model.Joint{ID: uuid.New(), Name: "Auntie Muni", CreatorID: uuid.New()}
+	complaint := testComplaint(joint)
+
+	expectComplaintCreated(mock, joint, model.VisibleJoint, complaint)
+	expectComplainants(mock, joint, 3)
+	mock.ExpectQuery(`UPDATE joints\s+SET visibility = \$1`).WithArgs(string(model.UnderReviewJoint), joint.ID.String()).WillReturnRows(jointRow(joint, model.UnderReviewJoint))
+	expectAudit(mock, string(model.JointVisibilityChangedAction))
+	expectEvent(mock, model.JointVisibilityChangedEvent)
+	mock.ExpectCommit()
+
+	if _, err := service.CreateComplaint(context.Background(), complaint); err != nil {
+		t.Fatalf("CreateComplaint() error = %v", err)
+	}
+	if len(notifier.titles) != 2 {
+		t.Errorf("sent notifications %v, want the moderators and creator told the joint was hidden", notifier.titles)
+	}
+}
+
+func TestCreateComplaintRollsBackWhenRulesFail(t *testing.T) {
+	service, mock, notifier := newTestComplaintService(t)
+	joint := &model.Joint{ID: uuid.New(), Name: "Auntie Muni", CreatorID: uuid.New()}
+	complaint := testComplaint(joint)
+
+	expectComplaintCreated(mock, joint, model.VisibleJoint, complaint)
+	expectComplainants(mock, joint, 3)
+	// the complaint is not kept without the joint being hidden, which would otherwise stay visible with nothing to hide it later
+	mock.ExpectQuery(`UPDATE joints\s+SET visibility = \$1`).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	if _, err := service.CreateComplaint(context.Background(), complaint); err == nil {
+		t.Fatal("CreateComplaint() succeeded, want the error from hiding the joint")
+	}
+	if len(notifier.titles) != 0 {
+		t.Errorf("sent notifications %v, want none", notifier.titles)
+	}
+}
+
+func TestCreateComplaintBelowThresholdKeepsJointVisible(t *testing.T) {
+	service, mock, notifier := newTestComplaintService(t)
+	joint := &model.Joint{ID: uuid.New(), Name: "Auntie Muni", CreatorID: uuid.New()}
+	complaint := testComplaint(joint)
+
+	expectComplaintCreated(mock, joint, model.VisibleJoint, complaint)
+	expectComplainants(mock, joint, 2)
+	mock.ExpectCommit()
+
+	if _, err := service.CreateComplaint(context.Background(), complaint); err != nil {
+		t.Fatalf("CreateComplaint() error = %v", err)
+	}
+	if len(notifier.titles) != 0 {
+		t.Errorf("sent notifications %v, want none", notifier.titles)
+	}
+}
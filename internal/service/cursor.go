@@ -0,0 +1,136 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	// ErrInvalidCursor is the repository error so that cursors that no longer match the sort keys of their list are reported the same way as tampered ones
+	ErrInvalidCursor = repository.ErrInvalidCursor
+)
+
+// cursor is the position of the last record of a page. Scope identifies the list and filters it was issued for and Key holds the values the list is sorted by
+type cursor struct {
+	Scope string   `json:"s"`
+	Key   []string `json:"k"`
+}
+
+// pager resolves the page requested by pagination params and describes the page read
+type pager struct {
+	secret []byte
+	scope  string
+	query  model.PaginationQuery
+	page   model.Page
+}
+
+// newPager resolves the page requested by the pagination params of a list identified by scope. The scope should include the filters of the list so that a cursor cannot be replayed against another list
+func newPager(cfg *config.Config, query model.PaginationQuery, scope string) (*pager, error) {
+	p := &pager{secret: []byte(cfg.CursorSecret), scope: scope, query: query}
+
+	offset, limit := p.query.GetOffsetAndLimit()
+	// one more record than the page size is read to know whether there is a next page
+	p.page = model.Page{Offset: offset, Limit: limit + 1, IncludeTotal: query.IncludeTotal}
+	if query.Cursor != "" {
+		position, err := p.decode(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		p.page.After = position.Key
+	}
+	return p, nil
+}
+
+// readPage reads the page of a list identified by scope that the pagination params ask for and describes it. key returns the values a record is sorted by, in the order of the sort keys of the list
+func readPage[T any](cfg *config.Config, query model.PaginationQuery, scope string, read func(page model.Page) (*model.Paged[T], error), key func(T) []any) ([]T, *model.Pagination, error) {
+	p, err := newPager(cfg, query, scope)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := read(p.page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items := result.Items
+	pagination := &model.Pagination{PageSize: p.query.PageSize, Total: result.Total}
+	if p.query.Cursor == "" {
+		pagination.Page = p.query.Page
+	}
+	// the extra record read only tells that there is a next page
+	if len(items) > p.query.PageSize {
+		items = items[:p.query.PageSize]
+		next := p.encode(key(items[len(items)-1]))
+		pagination.NextCursor = &next
+		pagination.HasMore = true
+	}
+	return items, pagination, nil
+}
+
+// encode returns the signed, opaque cursor of a record positioned at key
+func (p *pager) encode(key []any) string {
+	position := cursor{Scope: p.scope, Key: make([]string, len(key))}
+	for i, value := range key {
+		position.Key[i] = formatKey(value)
+	}
+
+	data, _ := json.Marshal(position)
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(p.sign(payload))
+}
+
+// decode verifies the signature and scope of a cursor previously returned by encode
+func (p *pager) decode(token string) (*cursor, error) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, p.sign(payload)) {
+		return nil, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var position cursor
+	if err := json.Unmarshal(data, &position); err != nil || position.Scope != p.scope {
+		return nil, ErrInvalidCursor
+	}
+	return &position, nil
+}
+
+func (p *pager) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// formatKey formats a sort key value the way postgres parses it back
+func formatKey(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case uuid.UUID:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
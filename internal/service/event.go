@@ -0,0 +1,228 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// outboxBatchSize is the maximum number of events claimed by the dispatcher at once
+	outboxBatchSize = 50
+	// maxOutboxBackoff caps the delay between delivery attempts of a failing event
+	maxOutboxBackoff = time.Hour
+	// outboxLease is how long claimed events are kept from other dispatchers. It is longer than a batch takes to handle so that events are rarely handled twice
+	outboxLease = 5 * time.Minute
+)
+
+// EventHandler handles a domain event. Events are delivered at least once so handlers must be idempotent
+type EventHandler func(ctx context.Context, event *model.Event) error
+
+type subscription struct {
+	name    string
+	types   []model.EventType
+	handler EventHandler
+}
+
+// wants reports whether the subscription is interested in the event type. Subscriptions without types receive every event
+func (s subscription) wants(eventType model.EventType) bool {
+	return len(s.types) == 0 || slices.Contains(s.types, eventType)
+}
+
+// EventDispatcher delivers events from the outbox to registered subscribers
+type EventDispatcher struct {
+	cfg           *config.Config
+	outboxRepo    *repository.OutboxRepository
+	subscriptions []subscription
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewEventDispatcher(cfg *config.Config, outboxRepo *repository.OutboxRepository) *EventDispatcher {
+	return &EventDispatcher{
+		cfg:        cfg,
+		outboxRepo: outboxRepo,
+	}
+}
+
+// Subscribe registers a handler for the given event types, or for every event if none is given. The name identifies the subscriber across retries and must be unique and stable. Subscribers must be registered before the dispatcher is started
+func (d *EventDispatcher) Subscribe(name string, handler EventHandler, types ...model.EventType) {
+	d.subscriptions = append(d.subscriptions, subscription{name: name, types: types, handler: handler})
+}
+
+// Start polls the outbox in the background until Shutdown is called
+func (d *EventDispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stop != nil {
+		return
+	}
+
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	go d.run()
+}
+
+// Shutdown stops polling and drains the events that are currently due, returning when the outbox is empty or the context is done
+func (d *EventDispatcher) Shutdown(ctx context.Context) error {
+	d.mu.Lock()
+	if d.stop != nil {
+		close(d.stop)
+		<-d.done
+		d.stop = nil
+	}
+	d.mu.Unlock()
+
+	for {
+		n, err := d.dispatchBatch(ctx)
+		if err != nil {
+			return err
+		}
+		if n < outboxBatchSize {
+			return nil
+		}
+	}
+}
+
+func (d *EventDispatcher) run() {
+	defer close(d.done)
+
+	interval := d.cfg.OutboxPollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// stop in-flight deliveries when the dispatcher is stopped
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-d.stop
+		cancel()
+	}()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+
+		// keep dispatching while full batches are being claimed
+		for {
+			n, err := d.dispatchBatch(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("failed to dispatch events: %v", err)
+				}
+				break
+			}
+			if n < outboxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+// dispatchBatch delivers a batch of due events and returns the number of events claimed. Subscribers are called outside of any transaction and the outcome of each
+// event is recorded on its own, so that failing to record one does not make the subscribers of the others handle them again
+func (d *EventDispatcher) dispatchBatch(ctx context.Context) (int, error) {
+	events, err := d.outboxRepo.ClaimDue(ctx, d.cfg.OutboxMaxAttempts, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	// events whose outcome could not be recorded are attempted again once their lease expires
+	var errs []error
+	for _, event := range events {
+		// events not attempted before the dispatcher was stopped are released so that Shutdown can drain them
+		if ctx.Err() != nil {
+			if err := d.outboxRepo.Release(context.WithoutCancel(ctx), event.ID); err != nil {
+				errs = append(errs, fmt.Errorf("release event %s: %w", event.ID, err))
+			}
+			continue
+		}
+		if err := d.deliver(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("record outcome of event %s: %w", event.ID, err))
+		}
+	}
+	return len(events), errors.Join(errs...)
+}
+
+// deliver calls every interested subscriber that has not handled the event yet and records the outcome
+func (d *EventDispatcher) deliver(ctx context.Context, event *model.OutboxEvent) error {
+	delivered := event.DeliveredTo
+	var failures []string
+
+	for _, sub := range d.subscriptions {
+		if !sub.wants(event.Type) || slices.Contains(delivered, sub.name) {
+			continue
+		}
+		if err := callHandler(ctx, sub.handler, &event.Event); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sub.name, err))
+			continue
+		}
+		delivered = append(delivered, sub.name)
+	}
+
+	// the outcome is recorded even when the dispatcher is being stopped so that the subscribers that handled the event are not called again
+	ctx = context.WithoutCancel(ctx)
+	if len(failures) == 0 {
+		return d.outboxRepo.MarkDispatched(ctx, event.ID, delivered)
+	}
+
+	// retry with exponential backoff
+	backoff := min(time.Duration(1<<min(event.Attempts, 12))*time.Second, maxOutboxBackoff)
+	lastError := strings.Join(failures, "; ")
+	if event.Attempts+1 >= d.cfg.OutboxMaxAttempts {
+		log.Printf("giving up on event %s (%s) after %d attempts: %s", event.ID, event.Type, event.Attempts+1, lastError)
+	}
+	return d.outboxRepo.MarkFailed(ctx, event.ID, delivered, lastError, backoff)
+}
+
+// callHandler runs a handler, converting panics into errors so that one subscriber cannot stop the dispatcher
+func callHandler(ctx context.Context, handler EventHandler, event *model.Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
+
+// PruneDispatchedEvents removes dispatched events older than the outbox retention period
+func (d *EventDispatcher) PruneDispatchedEvents(ctx context.Context) error {
+	_, err := d.outboxRepo.DeleteDispatchedBefore(ctx, time.Now().Add(-d.cfg.OutboxRetention))
+	return err
+}
+
+// publishEvent writes a domain event to the outbox within the transaction of the state change. The actor is taken from the request info in the context
+func publishEvent(ctx context.Context, tx *sql.Tx, outboxRepo *repository.OutboxRepository, eventType model.EventType, aggregateID uuid.UUID, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = outboxRepo.Create(ctx, tx, &model.Event{
+		Type:        eventType,
+		AggregateID: aggregateID,
+		ActorID:     RequestInfoFromContext(ctx).ActorID,
+		Payload:     data,
+	})
+	return err
+}
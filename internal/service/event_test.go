@@ -0,0 +1,99 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func newTestEventDispatcher(t *testing.T) (*EventDispatcher, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	cfg := &config.Config{OutboxMaxAttempts: 10}
+	return NewEventDispatcher(cfg, repository.NewOutboxRepository(db)), mock
+}
+
+// expectClaimEvents expects the events to be leased without holding locks while they are handled
+func expectClaimEvents(mock sqlmock.Sqlmock, events ...*model.OutboxEvent) {
+	rows := sqlmock.NewRows([]string{"id", "event_type", "aggregate_id", "actor_id", "payload", "created_at", "attempts", "delivered_to"})
+	for _, event := range events {
+		rows.AddRow(event.ID, string(event.Type), event.AggregateID, nil, []byte(`{}`), event.CreatedAt, event.Attempts, []byte(`[]`))
+	}
+	mock.ExpectQuery(`UPDATE outbox_events e\s+SET next_attempt_at = NOW\(\) \+ make_interval`).
+		WithArgs(10, outboxBatchSize, outboxLease.Seconds()).WillReturnRows(rows)
+}
+
+func testOutboxEvent(age time.Duration) *model.OutboxEvent {
+	return &model.OutboxEvent{Event: model.Event{ID: uuid.New(), Type: model.JointApprovedEvent, AggregateID: uuid.New(), CreatedAt: time.Now().Add(-age)}}
+}
+
+func TestDispatchBatchRecordsEachEventOnItsOwn(t *testing.T) {
+	dispatcher, mock := newTestEventDispatcher(t)
+	first, second := testOutboxEvent(2*time.Minute), testOutboxEvent(time.Minute)
+
+	var handled []uuid.UUID
+	dispatcher.Subscribe("notifications", func(ctx context.Context, event *model.Event) error {
+		handled = append(handled, event.ID)
+		return nil
+	})
+	dispatcher.Subscribe("webhooks", func(ctx context.Context, event *model.Event) error {
+		if event.ID == second.ID {
+			return errors.New("endpoint down")
+		}
+		return nil
+	})
+
+	// the events come back out of order from the update and are handled oldest first
+	expectClaimEvents(mock, second, first)
+	mock.ExpectExec(`UPDATE outbox_events\s+SET dispatched_at = NOW\(\)`).WithArgs(`["notifications","webhooks"]`, first.ID.String()).
+		WillReturnError(errors.New("connection reset"))
+	// failing to record the first event does not undo the second
+	mock.ExpectExec(`UPDATE outbox_events\s+SET delivered_to = \$1, attempts = attempts \+ 1, last_error = \$2`).
+		WithArgs(`["notifications"]`, "webhooks: endpoint down", time.Second.Seconds(), second.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	n, err := dispatcher.dispatchBatch(context.Background())
+	if n != 2 {
+		t.Errorf("dispatchBatch() claimed %d events, want 2", n)
+	}
+	if err == nil {
+		t.Error("dispatchBatch() error = nil, want the error recording the first event")
+	}
+	if len(handled) != 2 || handled[0] != first.ID || handled[1] != second.ID {
+		t.Errorf("handled events %v, want %v then %v", handled, first.ID, second.ID)
+	}
+}
+
+func TestDispatchBatchReleasesEventsAfterStop(t *testing.T) {
+	dispatcher, mock := newTestEventDispatcher(t)
+	first, second := testOutboxEvent(2*time.Minute), testOutboxEvent(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var handled int
+	dispatcher.Subscribe("notifications", func(ctx context.Context, event *model.Event) error {
+		handled++
+		// the dispatcher is stopped while the first event is being handled
+		cancel()
+		return nil
+	})
+
+	expectClaimEvents(mock, first, second)
+	mock.ExpectExec(`UPDATE outbox_events\s+SET dispatched_at = NOW\(\)`).WithArgs(`["notifications"]`, first.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE outbox_events SET next_attempt_at = NOW\(\) WHERE id = \$1`).WithArgs(second.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if _, err := dispatcher.dispatchBatch(ctx); err != nil {
+		t.Fatalf("dispatchBatch() error = %v", err)
+	}
+	if handled != 1 {
+		t.Errorf("handled %d events, want only the one started before the stop", handled)
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+// newMockDB returns a database whose queries are answered by the expectations set on the mock. Expectations that were not met fail the test
+func newMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+		db.Close()
+	})
+	return db, mock
+}
+
+// expectAudit expects an audit event to be recorded for the given action
+func expectAudit(mock sqlmock.Sqlmock, action string) {
+	columns := []string{"id", "actor_id", "action", "target_type", "target_id", "before", "after", "ip_address", "request_id", "created_at"}
+	mock.ExpectQuery(`INSERT INTO audit_events`).
+		WithArgs(sqlmock.AnyArg(), action, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(uuid.New(), nil, action, "", uuid.New(), nil, nil, nil, nil, time.Now()))
+}
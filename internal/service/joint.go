@@ -2,10 +2,14 @@ package service
 
 import (
 	"chow/internal/config"
+	"chow/internal/i18n"
 	"chow/internal/model"
 	"chow/internal/repository"
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -15,24 +19,62 @@ var (
 	ErrJointAlreadyExist       = errors.New("joint already exists")
 	ErrJointNotFound           = errors.New("joint not found")
 	ErrMaxSearchRadiusExceeded = errors.New("maximum search radius exceeded")
+	ErrJointCannotDelete       = errors.New("joint cannot be deleted as other records depend on it")
+	ErrJointAlreadyApproved    = errors.New("joint has already been approved")
+	ErrVoteNotFound            = errors.New("vote not found")
+	ErrVoteVoided              = errors.New("vote was voided by moderators and can no longer be changed")
 )
 
+// voteWeights returns the weights applied to votes when computing joint scores
+func voteWeights(cfg *config.Config) model.VoteWeights {
+	return model.VoteWeights{
+		Verified:          cfg.VerifiedVoteWeight,
+		Trusted:           cfg.TrustedVoteWeight,
+		TrustedReputation: model.TrustLevelThresholds[model.AbilityTrustLevels[model.WeightedVoteAbility]],
+	}
+}
+
+// purgeBatchSize is the maximum number of expired joints purged in a single run of the purge job
+const purgeBatchSize = 100
+
+// reconcileBatchSize is the maximum number of joints with drifted vote counts repaired in a single run of the reconcile job
+const reconcileBatchSize = 500
+
 type JointService struct {
-	cfg       *config.Config
-	jointRepo *repository.JointRepository
-	voteRepo  *repository.VoteRepository
+	cfg         *config.Config
+	jointRepo   *repository.JointRepository
+	voteRepo    *repository.VoteRepository
+	checkinRepo *repository.CheckinRepository
+	auditRepo   *repository.AuditRepository
+	outboxRepo  *repository.OutboxRepository
 }
 
-func NewJointService(cfg *config.Config, jointRepo *repository.JointRepository, voteRepo *repository.VoteRepository) *JointService {
+func NewJointService(cfg *config.Config, jointRepo *repository.JointRepository, voteRepo *repository.VoteRepository, checkinRepo *repository.CheckinRepository, auditRepo *repository.AuditRepository, outboxRepo *repository.OutboxRepository) *JointService {
 	return &JointService{
-		cfg:       cfg,
-		jointRepo: jointRepo,
-		voteRepo:  voteRepo,
+		cfg:         cfg,
+		jointRepo:   jointRepo,
+		voteRepo:    voteRepo,
+		checkinRepo: checkinRepo,
+		auditRepo:   auditRepo,
+		outboxRepo:  outboxRepo,
 	}
 }
 
 func (s *JointService) CreateJoint(ctx context.Context, data *model.Joint) (*model.Joint, error) {
-	joint, err := s.jointRepo.Create(ctx, data)
+	if data.Category == "" {
+		data.Category = model.OtherJointCategory
+	}
+	if data.DefaultLocale == "" {
+		data.DefaultLocale = i18n.DefaultLocale
+	}
+
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	joint, err := s.jointRepo.Create(ctx, tx, data)
 	if err != nil {
 		if errors.Is(err, repository.ErrAlreadyExist) {
 			return nil, ErrJointAlreadyExist
@@ -40,40 +82,112 @@ func (s *JointService) CreateJoint(ctx context.Context, data *model.Joint) (*mod
 		return nil, err
 	}
 
-	// TODO: notify admins that joint has been created
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointCreatedAction, model.JointTarget, joint.ID, nil, joint); err != nil {
+		return nil, err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.JointCreatedEvent, joint.ID, joint); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return joint, err
 }
 
 // VoteForJoint updates the vote metrics for a given joint.
-// If an attempt is made to upvote an upvoted joint by the same user or vice versa, the request returns immediately with no error or joint information
+// If an attempt is made to upvote an upvoted joint by the same user or vice versa, the request returns immediately with no error or joint information.
+// Votes voided by moderators after an integrity review cannot be changed and ErrVoteVoided is returned.
+// The vote counts are maintained by the database from the votes table so concurrent votes cannot make them drift
 func (s *JointService) VoteForJoint(ctx context.Context, id uuid.UUID, data *model.Vote) (*model.Joint, error) {
-	// get previous vote record if present
-	vote, err := s.voteRepo.GetUserJointVote(ctx, data.UserID, data.JointID)
+	// votes of users who have checked in at the joint are weighted higher
+	verified, err := s.checkinRepo.HasCheckedIn(ctx, data.UserID, data.JointID)
+	if err != nil {
+		return nil, err
+	}
+	data.Verified = verified
+	data.IPAddress, data.Fingerprint = voteOrigin(ctx)
+
+	tx, err := s.voteRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// serialize votes of the user on the joint so that the previous direction read below is still current when the vote is written
+	if err := s.voteRepo.LockUserJoint(ctx, tx, data.UserID, data.JointID); err != nil {
+		return nil, err
+	}
+	vote, err := s.voteRepo.GetUserJointVote(ctx, tx, data.UserID, data.JointID)
 	if err != nil && !errors.Is(err, repository.ErrNotFound) {
 		return nil, err
 	}
 
 	// skip processing if directions are the same
-	voteExists := vote != nil
-	if voteExists && data.Direction == vote.Direction {
-		return nil, nil
+	var previousDirection *model.VoteDirection
+	if vote != nil {
+		if vote.Voided {
+			return nil, ErrVoteVoided
+		}
+		if data.Direction == vote.Direction {
+			return nil, nil
+		}
+		previousDirection = &vote.Direction
 	}
 
-	// start outer transaction
-	tx, err := s.jointRepo.GetTx(ctx)
+	vote, err = s.voteRepo.Upsert(ctx, tx, data)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		if errors.Is(err, repository.ErrVoided) {
+			return nil, ErrVoteVoided
+		}
+		return nil, err
+	}
+
+	joint, err := s.jointRepo.UpdateScore(ctx, tx, id, voteWeights(s.cfg))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	payload := model.VoteCastPayload{Vote: vote, PreviousDirection: previousDirection, UpVotes: joint.UpVotes, DownVotes: joint.DownVotes}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.VoteCastEvent, joint.ID, payload); err != nil {
+		return nil, err
+	}
+
+	// finally commit transaction
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return joint, err
+}
+
+// RetractVote removes the vote of a user on a joint. The vote count of its direction is decremented by the database.
+// Votes voided by moderators cannot be retracted so that the review evidence is kept and the user cannot vote again in full
+func (s *JointService) RetractVote(ctx context.Context, userID, jointID uuid.UUID) (*model.Joint, error) {
+	tx, err := s.voteRepo.GetTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	// upsert vote record
-	_, err = s.voteRepo.Upsert(ctx, tx, data)
+	vote, err := s.voteRepo.Delete(ctx, tx, userID, jointID)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVoteNotFound
+		}
+		if errors.Is(err, repository.ErrVoided) {
+			return nil, ErrVoteVoided
+		}
 		return nil, err
 	}
 
-	// apply vote. if it's an existing vote, the direction vote count is incremented while its opposite direction vote count is decremented
-	joint, err := s.jointRepo.UpdateVotes(ctx, tx, id, data.Direction, voteExists)
+	joint, err := s.jointRepo.UpdateScore(ctx, tx, jointID, voteWeights(s.cfg))
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrJointNotFound
@@ -81,23 +195,52 @@ func (s *JointService) VoteForJoint(ctx context.Context, id uuid.UUID, data *mod
 		return nil, err
 	}
 
-	// finally commit transaction
-	if err := tx.Commit(); err != nil {
+	payload := model.VoteRetractedPayload{Vote: vote, UpVotes: joint.UpVotes, DownVotes: joint.DownVotes}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.VoteRetractedEvent, joint.ID, payload); err != nil {
 		return nil, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return joint, err
 }
 
+// GetJointVoters retrieves the users who have voted on a joint, most recent first
+func (s *JointService) GetJointVoters(ctx context.Context, jointID uuid.UUID, query model.PaginationQuery) ([]*model.JointVoter, *model.Pagination, error) {
+	if _, err := s.GetJointByID(ctx, jointID); err != nil {
+		return nil, nil, err
+	}
+	return readPage(s.cfg, query, "joint-voters:"+jointID.String(), func(page model.Page) (*model.Paged[*model.JointVoter], error) {
+		return s.voteRepo.GetVotersByJointID(ctx, jointID, page)
+	}, func(voter *model.JointVoter) []any {
+		return []any{voter.CreatedAt, voter.UserID}
+	})
+}
+
+// GetUserVotes retrieves the votes cast by a user
+func (s *JointService) GetUserVotes(ctx context.Context, userID uuid.UUID, query model.PaginationQuery) ([]*model.UserVote, *model.Pagination, error) {
+	return readPage(s.cfg, query, "user-votes:"+userID.String(), func(page model.Page) (*model.Paged[*model.UserVote], error) {
+		return s.voteRepo.GetUserVotes(ctx, userID, page)
+	}, func(vote *model.UserVote) []any {
+		return []any{vote.UpdatedAt, vote.ID}
+	})
+}
+
 // GetNearbyJoints returns the closest joints from the provided coordinates with the specified radius
-func (s *JointService) GetNearbyJoints(ctx context.Context, coord model.Coordinate, radius float64, offset, limit int) ([]*model.Joint, error) {
+func (s *JointService) GetNearbyJoints(ctx context.Context, coord model.Coordinate, radius float64, query model.PaginationQuery) ([]*model.Joint, *model.Pagination, error) {
 	// validate max radius
 	if radius > s.cfg.MaxNearbyRadius {
-		return nil, ErrMaxSearchRadiusExceeded
+		return nil, nil, ErrMaxSearchRadiusExceeded
 	}
 
-	// perform search
-	return s.jointRepo.GetNearby(ctx, coord, radius, offset, limit)
+	// perform search. distances are only comparable from the same point so the cursor is bound to it
+	scope := fmt.Sprintf("nearby-joints:%g,%g,%g", coord.Latitude, coord.Longitude, radius)
+	return readPage(s.cfg, query, scope, func(page model.Page) (*model.Paged[*model.Joint], error) {
+		return s.jointRepo.GetNearby(ctx, coord, radius, page)
+	}, func(joint *model.Joint) []any {
+		return []any{*joint.Distance, joint.ID}
+	})
 }
 
 func (s *JointService) GetJointByID(ctx context.Context, id uuid.UUID) (*model.Joint, error) {
@@ -112,16 +255,69 @@ func (s *JointService) GetJointByID(ctx context.Context, id uuid.UUID) (*model.J
 	return joint, err
 }
 
-func (s *JointService) SearchForJointByNameOrDescription(ctx context.Context, query string, offset, limit int) ([]*model.Joint, error) {
-	return s.jointRepo.Search(ctx, query, offset, limit)
+// PersonalizeJoints populates the details of joints that are specific to the given user
+func (s *JointService) PersonalizeJoints(ctx context.Context, userID uuid.UUID, joints ...*model.Joint) error {
+	if len(joints) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(joints))
+	for _, joint := range joints {
+		ids = append(ids, joint.ID)
+	}
+	visited, err := s.checkinRepo.GetVisitedJointIDs(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+	directions, err := s.voteRepo.GetUserVoteDirections(ctx, userID, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, joint := range joints {
+		joint.Visited = new(bool)
+		*joint.Visited = visited[joint.ID]
+		joint.UserVote = nil
+		if direction, ok := directions[joint.ID]; ok {
+			joint.UserVote = &direction
+		}
+	}
+	return nil
 }
 
-func (s *JointService) GetAllJoints(ctx context.Context, offset, limit int) ([]*model.Joint, error) {
-	return s.jointRepo.GetAll(ctx, offset, limit)
+func (s *JointService) SearchForJointByNameOrDescription(ctx context.Context, q string, query model.PaginationQuery) ([]*model.Joint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "joint-search:"+q, func(page model.Page) (*model.Paged[*model.Joint], error) {
+		return s.jointRepo.Search(ctx, q, page)
+	}, func(joint *model.Joint) []any {
+		return []any{joint.Name, joint.ID}
+	})
+}
+
+func (s *JointService) GetAllJoints(ctx context.Context, query model.PaginationQuery) ([]*model.Joint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "joints", func(page model.Page) (*model.Paged[*model.Joint], error) {
+		return s.jointRepo.GetAll(ctx, page)
+	}, func(joint *model.Joint) []any {
+		return []any{joint.CreatedAt, joint.ID}
+	})
 }
 
 func (s *JointService) UpdateJointByID(ctx context.Context, id uuid.UUID, data *model.Joint) (*model.Joint, error) {
-	joint, err := s.jointRepo.UpdateByID(ctx, id, data)
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the state recorded as before the change is read under the lock taken by the transaction that makes it
+	existing, err := s.jointRepo.LockByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	joint, err := s.jointRepo.UpdateByID(ctx, tx, id, data)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, ErrJointNotFound
@@ -129,16 +325,288 @@ func (s *JointService) UpdateJointByID(ctx context.Context, id uuid.UUID, data *
 		return nil, err
 	}
 
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointUpdatedAction, model.JointTarget, id, existing, joint); err != nil {
+		return nil, err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.JointUpdatedEvent, id, joint); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return joint, err
 }
 
-func (s *JointService) DeleteJointByID(ctx context.Context, id uuid.UUID) error {
-	if err := s.jointRepo.DeleteByID(ctx, id); err != nil {
+// ApproveJoint marks a joint as approved so that it appears in public listings
+func (s *JointService) ApproveJoint(ctx context.Context, id uuid.UUID) (*model.Joint, error) {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.jointRepo.LockByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	joint, err := s.jointRepo.UpdateApproval(ctx, tx, id, true)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointApprovedAction, model.JointTarget, id, existing, joint); err != nil {
+		return nil, err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.JointApprovedEvent, id, joint); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return joint, err
+}
+
+// RejectJoint declines a submitted joint. Rejected joints are moved to the trash with the rejection reason
+func (s *JointService) RejectJoint(ctx context.Context, id uuid.UUID, rejectedBy uuid.UUID, reason *string) error {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.jointRepo.LockByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrJointNotFound
+		}
+		return err
+	}
+	if existing.IsApproved {
+		return ErrJointAlreadyApproved
+	}
+
+	if err := s.jointRepo.DeleteByID(ctx, tx, id, rejectedBy, reason); err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return ErrJointNotFound
 		}
 		return err
 	}
 
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointRejectedAction, model.JointTarget, id, existing, nil); err != nil {
+		return err
+	}
+	payload := model.JointRejectedPayload{Joint: existing, Reason: reason}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.JointRejectedEvent, id, payload); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteJointByID soft deletes a joint. It can be restored until it is purged after the retention period
+func (s *JointService) DeleteJointByID(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID, reason *string) error {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.jointRepo.LockByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrJointNotFound
+		}
+		return err
+	}
+
+	if err := s.jointRepo.DeleteByID(ctx, tx, id, deletedBy, reason); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrJointNotFound
+		}
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointDeletedAction, model.JointTarget, id, existing, nil); err != nil {
+		return err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.JointDeletedEvent, id, existing); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDeletedJoints returns the joints in the trash
+func (s *JointService) GetDeletedJoints(ctx context.Context, query model.PaginationQuery) ([]*model.Joint, *model.Pagination, error) {
+	return readPage(s.cfg, query, "deleted-joints", func(page model.Page) (*model.Paged[*model.Joint], error) {
+		return s.jointRepo.GetDeleted(ctx, page)
+	}, func(joint *model.Joint) []any {
+		return []any{*joint.DeletedAt, joint.ID}
+	})
+}
+
+// RestoreJoint brings back a soft deleted joint
+func (s *JointService) RestoreJoint(ctx context.Context, id uuid.UUID) (*model.Joint, error) {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.jointRepo.LockDeletedByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	joint, err := s.jointRepo.Restore(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointRestoredAction, model.JointTarget, id, existing, joint); err != nil {
+		return nil, err
+	}
+	if err := publishEvent(ctx, tx, s.outboxRepo, model.JointRestoredEvent, id, joint); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return joint, err
+}
+
+// PurgeJoint permanently removes a soft deleted joint and its dependents
+func (s *JointService) PurgeJoint(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.jointRepo.LockDeletedByID(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrJointNotFound
+		}
+		return err
+	}
+
+	if err := s.jointRepo.Purge(ctx, tx, id); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return ErrJointNotFound
+		case errors.Is(err, repository.ErrCannotDelete):
+			return ErrJointCannotDelete
+		}
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointPurgedAction, model.JointTarget, id, existing, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PurgeExpiredJoints permanently removes joints that have been in the trash for longer than the retention period. Joints that cannot be purged are skipped and retried on the next run
+func (s *JointService) PurgeExpiredJoints(ctx context.Context) error {
+	ids, err := s.jointRepo.GetPurgeable(ctx, time.Now().Add(-s.cfg.JointRetention), purgeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.PurgeJoint(ctx, id); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Printf("failed to purge joint %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// ReconcileVoteCounts compares the vote counts of joints with the votes recorded for them and returns the joints that differ.
+// When fix is set, the counts and scores of those joints are recomputed and the repair is recorded in the audit log
+func (s *JointService) ReconcileVoteCounts(ctx context.Context, fix bool) ([]*model.VoteCountDiscrepancy, error) {
+	discrepancies, err := s.jointRepo.GetVoteCountDiscrepancies(ctx, reconcileBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if !fix {
+		return discrepancies, nil
+	}
+
+	for _, discrepancy := range discrepancies {
+		if err := s.repairVoteCounts(ctx, discrepancy.JointID); err != nil {
+			return nil, err
+		}
+	}
+	return discrepancies, nil
+}
+
+// RepairVoteCounts repairs the vote counts that have drifted from the votes table and reports them
+func (s *JointService) RepairVoteCounts(ctx context.Context) error {
+	discrepancies, err := s.ReconcileVoteCounts(ctx, true)
+	if err != nil {
+		return err
+	}
+	for _, d := range discrepancies {
+		log.Printf("repaired vote counts of joint %s: upvotes %d -> %d, downvotes %d -> %d", d.JointID, d.UpVotes, d.ActualUpVotes, d.DownVotes, d.ActualDownVotes)
+	}
 	return nil
 }
+
+// repairVoteCounts recomputes the vote counts and score of a joint and records it in the audit log
+func (s *JointService) repairVoteCounts(ctx context.Context, id uuid.UUID) error {
+	tx, err := s.jointRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// joints in the trash are repaired too, and purged joints have nothing left to repair
+	before, err := s.jointRepo.LockByID(ctx, tx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		before, err = s.jointRepo.LockDeletedByID(ctx, tx, id)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	joint, err := s.jointRepo.ReconcileVoteCounts(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if after, err := s.jointRepo.UpdateScore(ctx, tx, id, voteWeights(s.cfg)); err == nil {
+		joint = after
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointVotesReconciledAction, model.JointTarget, id, before, joint); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
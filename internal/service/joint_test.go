@@ -0,0 +1,92 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func newTestJointService(t *testing.T) (*JointService, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	cfg := &config.Config{VerifiedVoteWeight: 1.5, TrustedVoteWeight: 2}
+	return NewJointService(cfg, repository.NewJointRepository(db), repository.NewVoteRepository(db), repository.NewCheckinRepository(db),
+		repository.NewAuditRepository(db), repository.NewOutboxRepository(db)), mock
+}
+
+// expectVoteLookup expects the vote of the user on the joint to be read after locking it, answering with the vote if there is one
+func expectVoteLookup(mock sqlmock.Sqlmock, userID, jointID uuid.UUID, direction model.VoteDirection, voided bool) {
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM checkins`).WithArgs(userID.String(), jointID.String()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).WithArgs(userID.String(), jointID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id, user_id, joint_id, direction, verified, created_at, updated_at, voided_at IS NOT NULL AS voided\s+FROM votes`).
+		WithArgs(userID.String(), jointID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "joint_id", "direction", "verified", "created_at", "updated_at", "voided"}).
+			AddRow(uuid.New(), userID, jointID, string(direction), false, time.Now(), time.Now(), voided))
+}
+
+func TestVoteForJointRejectsVoidedVote(t *testing.T) {
+	service, mock := newTestJointService(t)
+	userID, jointID := uuid.New(), uuid.New()
+
+	// the voter flipping a vote voided by moderators would otherwise be told it counted while it is still ignored
+	expectVoteLookup(mock, userID, jointID, model.DownVote, true)
+	mock.ExpectRollback()
+
+	_, err := service.VoteForJoint(context.Background(), jointID, &model.Vote{UserID: userID, JointID: jointID, Direction: model.UpVote})
+	if !errors.Is(err, ErrVoteVoided) {
+		t.Fatalf("VoteForJoint() error = %v, want %v", err, ErrVoteVoided)
+	}
+}
+
+func TestVoteForJointRejectsVoteVoidedDuringChange(t *testing.T) {
+	service, mock := newTestJointService(t)
+	userID, jointID := uuid.New(), uuid.New()
+
+	expectVoteLookup(mock, userID, jointID, model.DownVote, false)
+	// moderators voided the vote after it was read so the upsert leaves it unchanged
+	mock.ExpectQuery(`ON CONFLICT\(user_id, joint_id\)[\s\S]+WHERE votes.voided_at IS NULL`).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err := service.VoteForJoint(context.Background(), jointID, &model.Vote{UserID: userID, JointID: jointID, Direction: model.UpVote})
+	if !errors.Is(err, ErrVoteVoided) {
+		t.Fatalf("VoteForJoint() error = %v, want %v", err, ErrVoteVoided)
+	}
+}
+
+func TestRetractVoteKeepsVoidedVote(t *testing.T) {
+	service, mock := newTestJointService(t)
+	userID, jointID := uuid.New(), uuid.New()
+
+	// deleting a voided vote would cascade to the review that voided it and let the user vote again in full
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM votes\s+WHERE user_id = \$1 AND joint_id = \$2 AND voided_at IS NULL`).WithArgs(userID.String(), jointID.String()).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM votes`).WithArgs(userID.String(), jointID.String()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	if _, err := service.RetractVote(context.Background(), userID, jointID); !errors.Is(err, ErrVoteVoided) {
+		t.Fatalf("RetractVote() error = %v, want %v", err, ErrVoteVoided)
+	}
+}
+
+func TestRetractVoteWithoutVote(t *testing.T) {
+	service, mock := newTestJointService(t)
+	userID, jointID := uuid.New(), uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM votes`).WithArgs(userID.String(), jointID.String()).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM votes`).WithArgs(userID.String(), jointID.String()).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectRollback()
+
+	if _, err := service.RetractVote(context.Background(), userID, jointID); !errors.Is(err, ErrVoteNotFound) {
+		t.Fatalf("RetractVote() error = %v, want %v", err, ErrVoteNotFound)
+	}
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"chow/internal/config"
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// Mailer sends plain text emails to users
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// NewMailer returns an SMTP mailer, or a mailer that only logs emails when no mail server is configured
+func NewMailer(cfg *config.Config) Mailer {
+	if cfg.SMTPHost == "" {
+		return &logMailer{}
+	}
+	return &smtpMailer{cfg: cfg}
+}
+
+// logMailer writes emails to the log. It is used in development where no mail server is available
+type logMailer struct{}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("email to %s: %s\n%s", to, subject, body)
+	return nil
+}
+
+type smtpMailer struct {
+	cfg *config.Config
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to, subject, body string) error {
+	from, err := mail.ParseAddress(m.cfg.MailFrom)
+	if err != nil {
+		return err
+	}
+
+	// header values must not contain line breaks
+	subject = strings.NewReplacer("\r", "", "\n", " ").Replace(subject)
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s", from.String(), to, subject, body)
+
+	var auth smtp.Auth
+	if m.cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.cfg.SMTPUsername, m.cfg.SMTPPassword, m.cfg.SMTPHost)
+	}
+	addr := net.JoinHostPort(m.cfg.SMTPHost, strconv.Itoa(m.cfg.SMTPPort))
+	return smtp.SendMail(addr, auth, from.Address, []string{to}, []byte(message))
+}
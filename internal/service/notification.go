@@ -0,0 +1,241 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+// pendingComplaintsGroup aggregates new complaint notifications for moderators into a single unread notification
+const pendingComplaintsGroup = "pending_complaints"
+
+type NotificationService struct {
+	cfg              *config.Config
+	notificationRepo *repository.NotificationRepository
+	userRepo         *repository.UserRepository
+}
+
+func NewNotificationService(cfg *config.Config, notificationRepo *repository.NotificationRepository, userRepo *repository.UserRepository) *NotificationService {
+	return &NotificationService{
+		cfg:              cfg,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// RegisterSubscribers subscribes the notification producers to the domain events they react to
+func (s *NotificationService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("notifications", s.handleEvent,
+		model.JointApprovedEvent,
+		model.JointRejectedEvent,
+		model.ComplaintFiledEvent,
+		model.ComplaintResolvedEvent,
+		model.ComplaintRejectedEvent,
+		model.ComplaintRepliedEvent,
+	)
+}
+
+// GetNotifications returns a page of a user's notifications along with the cursor of the next page, if there is one
+func (s *NotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool, query model.PaginationQuery) (*model.NotificationPage, *model.Pagination, error) {
+	scope := fmt.Sprintf("notifications:%s:%t", userID, unreadOnly)
+	notifications, pagination, err := readPage(s.cfg, query, scope, func(page model.Page) (*model.Paged[*model.Notification], error) {
+		return s.notificationRepo.GetUserNotifications(ctx, userID, unreadOnly, page)
+	}, func(notification *model.Notification) []any {
+		return []any{notification.CreatedAt, notification.ID}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &model.NotificationPage{Notifications: notifications, NextCursor: pagination.NextCursor}, pagination, nil
+}
+
+func (s *NotificationService) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+func (s *NotificationService) MarkNotificationRead(ctx context.Context, userID, id uuid.UUID) (*model.Notification, error) {
+	notification, err := s.notificationRepo.MarkRead(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	return notification, nil
+}
+
+// MarkAllNotificationsRead marks every unread notification of a user as read and returns how many were updated
+func (s *NotificationService) MarkAllNotificationsRead(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.notificationRepo.MarkAllRead(ctx, userID)
+}
+
+// GetPreferences returns the preference of a user for every notification type
+func (s *NotificationService) GetPreferences(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error) {
+	saved, err := s.notificationRepo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled := make(map[model.NotificationType]bool, len(saved))
+	for _, preference := range saved {
+		enabled[preference.Type] = preference.Enabled
+	}
+
+	preferences := make([]model.NotificationPreference, 0, len(model.NotificationTypes))
+	for _, notificationType := range model.NotificationTypes {
+		value, ok := enabled[notificationType]
+		preferences = append(preferences, model.NotificationPreference{Type: notificationType, Enabled: !ok || value})
+	}
+	return preferences, nil
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, preferences []model.NotificationPreference) ([]model.NotificationPreference, error) {
+	if err := s.notificationRepo.UpsertPreferences(ctx, userID, preferences); err != nil {
+		return nil, err
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+// NotifyUser adds a notification to a user's inbox unless they have disabled its type
+func (s *NotificationService) NotifyUser(ctx context.Context, userID uuid.UUID, notification *model.Notification) error {
+	enabled, err := s.notificationRepo.IsEnabled(ctx, userID, notification.Type)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	data := *notification
+	data.UserID = userID
+	if _, err := s.notificationRepo.Create(ctx, &data); err != nil && !errors.Is(err, repository.ErrAlreadyExist) {
+		return err
+	}
+	return nil
+}
+
+// NotifyModerators notifies every admin and moderator. Moderators already notified about the event are skipped when it is retried after another one failed
+func (s *NotificationService) NotifyModerators(ctx context.Context, notification *model.Notification) error {
+	moderators, err := s.userRepo.GetByRoles(ctx, model.Admin, model.Moderator)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, moderator := range moderators {
+		if err := s.NotifyUser(ctx, moderator.ID, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PruneProcessedEvents removes the record of events that were turned into notifications once they can no longer be redelivered
+func (s *NotificationService) PruneProcessedEvents(ctx context.Context) error {
+	_, err := s.notificationRepo.DeleteProcessedEvents(ctx)
+	return err
+}
+
+// handleEvent produces the notifications for a domain event
+func (s *NotificationService) handleEvent(ctx context.Context, event *model.Event) error {
+	switch event.Type {
+	case model.JointApprovedEvent:
+		var joint model.Joint
+		if err := json.Unmarshal(event.Payload, &joint); err != nil {
+			return err
+		}
+		return s.NotifyUser(ctx, joint.CreatorID, &model.Notification{
+			Type:       model.JointApprovedNotification,
+			Title:      "Joint approved",
+			Body:       fmt.Sprintf("%q is now listed for everyone to discover", joint.Name),
+			TargetType: targetType(model.JointTarget),
+			TargetID:   &joint.ID,
+			EventID:    &event.ID,
+		})
+
+	case model.JointRejectedEvent:
+		var payload model.JointRejectedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		body := fmt.Sprintf("%q was not approved", payload.Joint.Name)
+		if payload.Reason != nil {
+			body = fmt.Sprintf("%s: %s", body, *payload.Reason)
+		}
+		return s.NotifyUser(ctx, payload.Joint.CreatorID, &model.Notification{
+			Type:       model.JointRejectedNotification,
+			Title:      "Joint rejected",
+			Body:       body,
+			TargetType: targetType(model.JointTarget),
+			TargetID:   &payload.Joint.ID,
+			EventID:    &event.ID,
+		})
+
+	case model.ComplaintFiledEvent:
+		var complaint model.Complaint
+		if err := json.Unmarshal(event.Payload, &complaint); err != nil {
+			return err
+		}
+		group := pendingComplaintsGroup
+		return s.NotifyModerators(ctx, &model.Notification{
+			Type:       model.PendingComplaintsNotification,
+			Title:      "New complaints awaiting review",
+			Body:       fmt.Sprintf("Latest complaint: %s", complaint.Reason),
+			TargetType: targetType(model.ComplaintTarget),
+			TargetID:   &complaint.ID,
+			GroupKey:   &group,
+			EventID:    &event.ID,
+		})
+
+	case model.ComplaintResolvedEvent, model.ComplaintRejectedEvent:
+		var complaint model.Complaint
+		if err := json.Unmarshal(event.Payload, &complaint); err != nil {
+			return err
+		}
+		return s.NotifyUser(ctx, complaint.UserID, &model.Notification{
+			Type:       model.ComplaintStatusNotification,
+			Title:      "Complaint " + string(complaint.Status),
+			Body:       fmt.Sprintf("Your complaint %q has been %s", complaint.Reason, complaint.Status),
+			TargetType: targetType(model.ComplaintTarget),
+			TargetID:   &complaint.ID,
+			EventID:    &event.ID,
+		})
+
+	case model.ComplaintRepliedEvent:
+		var payload model.ComplaintRepliedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		// replies are aggregated per complaint so a conversation only produces one unread notification
+		group := "complaint_reply:" + payload.Complaint.ID.String()
+		notification := &model.Notification{
+			Type:       model.ComplaintReplyNotification,
+			Title:      "New reply on complaint",
+			Body:       payload.Reply.Body,
+			TargetType: targetType(model.ComplaintTarget),
+			TargetID:   &payload.Complaint.ID,
+			GroupKey:   &group,
+			EventID:    &event.ID,
+		}
+		if payload.Reply.UserID == payload.Complaint.UserID {
+			return s.NotifyModerators(ctx, notification)
+		}
+		return s.NotifyUser(ctx, payload.Complaint.UserID, notification)
+	}
+	return nil
+}
+
+func targetType(t model.TargetType) *model.TargetType {
+	return &t
+}
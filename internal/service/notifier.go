@@ -0,0 +1,16 @@
+package service
+
+import (
+	"chow/internal/model"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Notifier delivers notifications to users of the platform
+type Notifier interface {
+	// NotifyModerators sends a notification to every admin and moderator
+	NotifyModerators(ctx context.Context, notification *model.Notification) error
+	// NotifyUser sends a notification to a single user
+	NotifyUser(ctx context.Context, userID uuid.UUID, notification *model.Notification) error
+}
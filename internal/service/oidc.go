@@ -0,0 +1,652 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrOIDCProviderNotFound = errors.New("sign in provider not found")
+	ErrInvalidOIDCState     = errors.New("sign in is invalid or has expired")
+	ErrOIDCProviderDown     = errors.New("sign in provider is unavailable")
+	ErrOIDCLoginFailed      = errors.New("sign in with the provider failed")
+	ErrOIDCEmailNotVerified = errors.New("the provider has not verified the email of the account")
+	ErrOIDCAccountExists    = errors.New("an account with this email already exists, sign in to it and link the provider from the account")
+	ErrIdentityNotFound     = errors.New("identity not found")
+	ErrIdentityLinked       = errors.New("the identity is already linked to an account")
+	ErrLastSignInMethod     = errors.New("the only way to sign in to the account cannot be removed")
+)
+
+const (
+	// oidcDiscoveryTTL is how long the endpoints of a provider are cached
+	oidcDiscoveryTTL = time.Hour
+	// oidcKeyRefreshInterval limits how often ID tokens with an unknown kid fetch the keys of a provider again
+	oidcKeyRefreshInterval = time.Minute
+	oidcRequestTimeout     = 10 * time.Second
+	// maxOIDCResponseSize caps the responses read from providers
+	maxOIDCResponseSize = 1 << 20
+	// usernames derived from a profile are kept short enough to add a suffix when they are taken
+	maxUsernameLength = 24
+	minUsernameLength = 5
+)
+
+// oidcIDTokenAlgorithms are the algorithms ID tokens may be signed with. Symmetric algorithms are left out since the client secret is not a signing key here
+var oidcIDTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// GetOIDCProviders returns the providers users can sign in with
+func (s *AuthService) GetOIDCProviders() []model.OIDCProvider {
+	providers := make([]model.OIDCProvider, 0, len(s.cfg.OIDCProviders))
+	for _, provider := range s.cfg.OIDCProviders {
+		providers = append(providers, model.OIDCProvider{Name: provider.Name})
+	}
+	return providers
+}
+
+// StartOIDCLogin starts a sign in with a provider using the authorization code flow with PKCE. The user is sent to the returned URL and comes back to the
+// redirect URL with the code and state to complete it
+func (s *AuthService) StartOIDCLogin(ctx context.Context, providerName string) (*model.OIDCAuthorizationRes, error) {
+	return s.startOIDC(ctx, providerName, nil)
+}
+
+// StartOIDCLink starts linking a provider to the account of a signed in user. It is completed with CompleteOIDCLink by the same user
+func (s *AuthService) StartOIDCLink(ctx context.Context, userID uuid.UUID, providerName string) (*model.OIDCAuthorizationRes, error) {
+	return s.startOIDC(ctx, providerName, &userID)
+}
+
+// startOIDC stores the state, nonce and PKCE verifier of a sign in or link with a provider and returns the URL of the provider to send the user to
+func (s *AuthService) startOIDC(ctx context.Context, providerName string, userID *uuid.UUID) (*model.OIDCAuthorizationRes, error) {
+	provider, err := s.getOIDCProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := s.oidc.discover(ctx, provider)
+	if err != nil {
+		log.Printf("failed to discover the %s provider: %v", provider.Name, err)
+		return nil, ErrOIDCProviderDown
+	}
+
+	state, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	data := &model.OIDCState{
+		StateHash:    hashToken(state),
+		Provider:     provider.Name,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		UserID:       userID,
+		ExpiresAt:    time.Now().Add(s.cfg.OIDCStateExpiry),
+	}
+	if err := s.identityRepo.CreateState(ctx, data); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.ClientID},
+		"redirect_uri":          {s.cfg.OIDCRedirectURL},
+		"scope":                 {strings.Join(provider.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(endpoints.authorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return &model.OIDCAuthorizationRes{AuthorizationURL: endpoints.authorizationEndpoint + separator + query.Encode(), State: state}, nil
+}
+
+// CompleteOIDCLogin exchanges the code a provider sent the user back with for their identity and logs them in. Identities seen for the first time get a new account
+// without a password as long as the provider has verified the email and no account has it. Existing accounts link providers with CompleteOIDCLink instead
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, req model.OIDCCallbackReq) (*model.LoginUserRes, error) {
+	provider, claims, err := s.completeOIDC(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveIdentity(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.signIn(ctx, user)
+}
+
+// CompleteOIDCLink exchanges the code a provider sent the user back with for their identity and links it to their account. The email at the provider does not
+// have to match since the user proved they own both by signing in to each
+func (s *AuthService) CompleteOIDCLink(ctx context.Context, userID uuid.UUID, req model.OIDCCallbackReq) (*model.UserIdentity, error) {
+	provider, claims, err := s.completeOIDC(ctx, req, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identityRepo.GetBySubject(ctx, provider, claims.Subject)
+	if err == nil {
+		if identity.UserID == userID {
+			return identity, nil
+		}
+		return nil, ErrIdentityLinked
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.linkIdentity(ctx, user, provider, claims)
+}
+
+// completeOIDC takes the state of a sign in or link so that it is only used once and returns the provider and verified claims of the user. A state can only be
+// completed by the flow that started it, so links started by a user cannot be turned into sign ins or completed by someone else
+func (s *AuthService) completeOIDC(ctx context.Context, req model.OIDCCallbackReq, userID *uuid.UUID) (string, *idTokenClaims, error) {
+	state, err := s.identityRepo.TakeState(ctx, hashToken(req.State))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", nil, ErrInvalidOIDCState
+		}
+		return "", nil, err
+	}
+	if (state.UserID == nil) != (userID == nil) || (userID != nil && *state.UserID != *userID) {
+		return "", nil, ErrInvalidOIDCState
+	}
+	provider, err := s.getOIDCProvider(state.Provider)
+	if err != nil {
+		return "", nil, ErrInvalidOIDCState
+	}
+
+	claims, err := s.oidc.authenticate(ctx, provider, s.cfg.OIDCRedirectURL, req.Code, state)
+	if err != nil {
+		log.Printf("sign in with the %s provider failed: %v", provider.Name, err)
+		return "", nil, ErrOIDCLoginFailed
+	}
+	return provider.Name, claims, nil
+}
+
+// GetIdentities returns the identities linked to a user
+func (s *AuthService) GetIdentities(ctx context.Context, userID uuid.UUID) ([]model.UserIdentity, error) {
+	return s.identityRepo.GetByUserID(ctx, userID)
+}
+
+// UnlinkIdentity removes an identity from a user. The last identity of a user without a password cannot be removed since they could no longer sign in
+func (s *AuthService) UnlinkIdentity(ctx context.Context, userID, identityID uuid.UUID) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.identityRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	remaining, err := s.identityRepo.Delete(ctx, tx, userID, identityID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrIdentityNotFound
+		}
+		return err
+	}
+	if remaining == 0 && !user.HasPassword {
+		return ErrLastSignInMethod
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.recordSecurityEvent(ctx, &userID, model.IdentityUnlinked)
+	return nil
+}
+
+// resolveIdentity returns the user an identity belongs to, linking or creating one when the identity is new
+func (s *AuthService) resolveIdentity(ctx context.Context, provider string, claims *idTokenClaims) (*model.User, error) {
+	identity, err := s.identityRepo.GetBySubject(ctx, provider, claims.Subject)
+	if err == nil {
+		if err := s.identityRepo.RecordLogin(ctx, identity.ID, optionalString(claims.Email)); err != nil {
+			return nil, err
+		}
+		return s.getUser(ctx, identity.UserID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	// anyone can put any email on their account at some providers so only verified emails may create an account
+	if claims.Email == "" || !bool(claims.EmailVerified) {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	// identities are never linked to an account by email. Registering with a password does not prove the email is the user's, so the owner of an account
+	// with the email has to sign in to it and link the provider themselves
+	if _, err := s.userRepo.GetUserByEmailOrUsername(ctx, "email", claims.Email); err == nil {
+		return nil, ErrOIDCAccountExists
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	return s.createIdentityUser(ctx, provider, claims)
+}
+
+// linkIdentity links a new identity to a user. Their password and two-factor authentication are kept, so signing in with the identity still asks for the second factor
+func (s *AuthService) linkIdentity(ctx context.Context, user *model.User, provider string, claims *idTokenClaims) (*model.UserIdentity, error) {
+	tx, err := s.identityRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	data := &model.UserIdentity{UserID: user.ID, Provider: provider, Subject: claims.Subject, Email: optionalString(claims.Email)}
+	identity, err := s.identityRepo.Create(ctx, tx, data)
+	if err != nil {
+		if errors.Is(err, repository.ErrAlreadyExist) {
+			return nil, ErrIdentityLinked
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.recordSecurityEvent(ctx, &user.ID, model.IdentityLinked)
+	s.sendEmail(ctx, user.Email, "A sign in method was added to your Chow account",
+		fmt.Sprintf("Hi %s,\n\nYou can now sign in to Chow with %s. If it was not you, contact us straight away.\n", user.Username, provider))
+	return identity, nil
+}
+
+// createIdentityUser creates an account without a password for a new identity. The username is derived from the profile at the provider
+func (s *AuthService) createIdentityUser(ctx context.Context, provider string, claims *idTokenClaims) (*model.User, error) {
+	username, err := s.availableUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.userRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	user, err := s.userRepo.Create(ctx, tx, &model.User{Email: claims.Email, Username: username, Role: model.AppUser, EmailVerifiedAt: &now})
+	if err != nil {
+		return nil, err
+	}
+	data := &model.UserIdentity{UserID: user.ID, Provider: provider, Subject: claims.Subject, Email: &claims.Email}
+	if _, err := s.identityRepo.Create(ctx, tx, data); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.recordSecurityEvent(ctx, &user.ID, model.IdentityLinked)
+	s.sendEmail(ctx, user.Email, "Welcome to Chow",
+		fmt.Sprintf("Hi %s,\n\nYour Chow account is ready. Sign in with %s to start discovering food spots near you.\n", user.Username, provider))
+	return user, nil
+}
+
+// availableUsername returns a username for a new identity that is not taken. A random suffix is added when the name from the profile is taken or too short
+func (s *AuthService) availableUsername(ctx context.Context, claims *idTokenClaims) (string, error) {
+	base := "user"
+	for _, candidate := range []string{claims.PreferredUsername, claims.Name, strings.Split(claims.Email, "@")[0]} {
+		if candidate = sanitizeUsername(candidate); candidate != "" {
+			base = candidate
+			break
+		}
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		username := base
+		if attempt > 0 || len(username) < minUsernameLength {
+			suffix, err := generateToken()
+			if err != nil {
+				return "", err
+			}
+			username = base + "_" + suffix[:6]
+		}
+
+		if _, err := s.userRepo.GetUserByEmailOrUsername(ctx, "username", username); errors.Is(err, repository.ErrNotFound) {
+			return username, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("no available username for the identity")
+}
+
+// getOIDCProvider returns the configuration of a provider by name
+func (s *AuthService) getOIDCProvider(name string) (config.OIDCProvider, error) {
+	for _, provider := range s.cfg.OIDCProviders {
+		if provider.Name == name {
+			return provider, nil
+		}
+	}
+	return config.OIDCProvider{}, ErrOIDCProviderNotFound
+}
+
+// sanitizeUsername keeps the lowercase letters, digits, dots and underscores of a name, with spaces turned into underscores
+func sanitizeUsername(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '_':
+			return r
+		case r == ' ':
+			return '_'
+		}
+		return -1
+	}, strings.ToLower(strings.TrimSpace(name)))
+	return strings.Trim(truncate(name, maxUsernameLength), "._")
+}
+
+// idTokenClaims are the claims of an ID token used to sign a user in
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string    `json:"nonce"`
+	Email             string    `json:"email"`
+	EmailVerified     claimBool `json:"email_verified"`
+	Name              string    `json:"name"`
+	PreferredUsername string    `json:"preferred_username"`
+}
+
+// claimBool is a boolean claim that some providers send as a string
+type claimBool bool
+
+func (b *claimBool) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case bool:
+		*b = claimBool(v)
+	case string:
+		*b = claimBool(v == "true")
+	default:
+		*b = false
+	}
+	return nil
+}
+
+// oidcEndpoints are the endpoints of a provider found through discovery
+type oidcEndpoints struct {
+	issuer                string
+	authorizationEndpoint string
+	tokenEndpoint         string
+	jwksURI               string
+}
+
+// oidcProviderCache holds the discovered endpoints and keys of a provider
+type oidcProviderCache struct {
+	endpoints     oidcEndpoints
+	discoveredAt  time.Time
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// oidcClient talks to OpenID Connect providers. The endpoints and keys of providers are cached
+type oidcClient struct {
+	client    *http.Client
+	mu        sync.Mutex
+	providers map[string]*oidcProviderCache
+}
+
+func newOIDCClient() *oidcClient {
+	return &oidcClient{
+		client:    &http.Client{Timeout: oidcRequestTimeout},
+		providers: make(map[string]*oidcProviderCache),
+	}
+}
+
+// discover returns the endpoints of a provider from its OpenID configuration
+func (c *oidcClient) discover(ctx context.Context, provider config.OIDCProvider) (oidcEndpoints, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.providers[provider.Name]; ok && time.Since(cached.discoveredAt) < oidcDiscoveryTTL {
+		return cached.endpoints, nil
+	}
+
+	var document struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+	if err := c.getJSON(ctx, provider.Issuer+"/.well-known/openid-configuration", &document); err != nil {
+		return oidcEndpoints{}, err
+	}
+	// the issuer must be the one configured so that another provider cannot be substituted
+	if strings.TrimSuffix(document.Issuer, "/") != provider.Issuer {
+		return oidcEndpoints{}, fmt.Errorf("discovered issuer %q does not match %q", document.Issuer, provider.Issuer)
+	}
+	if document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return oidcEndpoints{}, errors.New("openid configuration is missing endpoints")
+	}
+
+	endpoints := oidcEndpoints{
+		issuer:                document.Issuer,
+		authorizationEndpoint: document.AuthorizationEndpoint,
+		tokenEndpoint:         document.TokenEndpoint,
+		jwksURI:               document.JWKSURI,
+	}
+	c.providers[provider.Name] = &oidcProviderCache{endpoints: endpoints, discoveredAt: time.Now(), keys: make(map[string]crypto.PublicKey)}
+	return endpoints, nil
+}
+
+// authenticate exchanges an authorization code for the ID token of the user and verifies it against the sign in it was issued for
+func (c *oidcClient) authenticate(ctx context.Context, provider config.OIDCProvider, redirectURL, code string, state *model.OIDCState) (*idTokenClaims, error) {
+	endpoints, err := c.discover(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {provider.ClientID},
+		"code_verifier": {state.CodeVerifier},
+	}
+	if provider.ClientSecret != "" {
+		form.Set("client_secret", provider.ClientSecret)
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoints.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := c.doJSON(request, &tokens); err != nil {
+		return nil, err
+	}
+	if tokens.IDToken == "" {
+		return nil, errors.New("token endpoint returned no ID token")
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(tokens.IDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.publicKey(ctx, provider.Name, endpoints, kid)
+	},
+		jwt.WithValidMethods(oidcIDTokenAlgorithms),
+		jwt.WithIssuer(endpoints.issuer),
+		jwt.WithAudience(provider.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Nonce != state.Nonce {
+		return nil, errors.New("ID token nonce does not match")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("ID token has no subject")
+	}
+	return claims, nil
+}
+
+// publicKey returns the key of a provider with the kid. The keys are fetched again when the kid is unknown in case the provider rotated them.
+// Tokens without a kid are accepted from providers with a single key
+func (c *oidcClient) publicKey(ctx context.Context, provider string, endpoints oidcEndpoints, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.providers[provider]
+	if !ok {
+		return nil, errors.New("provider has not been discovered")
+	}
+
+	if key := findPublicKey(cached.keys, kid); key != nil {
+		return key, nil
+	}
+	if time.Since(cached.keysFetchedAt) < oidcKeyRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	var jwks model.JWKS
+	if err := c.getJSON(ctx, endpoints.jwksURI, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(jwk)
+		if err != nil {
+			// keys of unsupported types are skipped rather than failing every sign in
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	cached.keys = keys
+	cached.keysFetchedAt = time.Now()
+
+	if key := findPublicKey(cached.keys, kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+func (c *oidcClient) getJSON(ctx context.Context, url string, v any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+	return c.doJSON(request, v)
+}
+
+// doJSON sends a request and decodes its JSON response
+func (c *oidcClient) doJSON(request *http.Request, v any) error {
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxOIDCResponseSize))
+	if err != nil {
+		return err
+	}
+	// error responses describe what went wrong, such as a code that was already used
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d: %s", request.URL.Redacted(), response.StatusCode, truncate(string(body), 500))
+	}
+	return json.Unmarshal(body, v)
+}
+
+func findPublicKey(keys map[string]crypto.PublicKey, kid string) crypto.PublicKey {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key
+		}
+	}
+	return keys[kid]
+}
+
+// parseJWK returns the public key of a JSON Web Key
+func parseJWK(jwk model.JWK) (crypto.PublicKey, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decode(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+		curve, ok := curves[jwk.Crv]
+		if !ok {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decode(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		x, err := decode(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if jwk.Crv != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, ErrUnsupportedAlgorithm
+}
@@ -0,0 +1,499 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	testProviderName = "test"
+	testClientID     = "chow-app"
+	testRedirectURL  = "https://chow.test/auth/callback"
+	testState        = "state-1"
+	testVerifier     = "verifier-1"
+	testNonce        = "nonce-1"
+	testSubject      = "subject-1"
+	testEmail        = "ama@example.com"
+)
+
+// oidcProviderStub is a local OpenID Connect provider serving discovery, its keys and a token endpoint that issues an ID token with the claims set on it
+type oidcProviderStub struct {
+	*httptest.Server
+	key *ecdsa.PrivateKey
+
+	mu sync.Mutex
+	// issuer is the issuer in the discovery document, the URL of the server unless set
+	issuer string
+	// claims are signed into the ID token returned by the token endpoint
+	claims jwt.MapClaims
+	// verifiers are the PKCE verifiers sent to the token endpoint
+	verifiers []string
+}
+
+func newOIDCProviderStub(t *testing.T) *oidcProviderStub {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate provider key: %v", err)
+	}
+	provider := &oidcProviderStub{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		provider.mu.Lock()
+		issuer := provider.issuer
+		provider.mu.Unlock()
+		if issuer == "" {
+			issuer = provider.URL
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": provider.URL + "/authorize",
+			"token_endpoint":         provider.URL + "/token",
+			"jwks_uri":               provider.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		encode := func(n interface{ FillBytes([]byte) []byte }) string {
+			return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, 32)))
+		}
+		json.NewEncoder(w).Encode(model.JWKS{Keys: []model.JWK{
+			{Kty: "EC", Use: "sig", Alg: "ES256", Kid: "provider-key", Crv: "P-256", X: encode(key.X), Y: encode(key.Y)},
+		}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" ||
+			r.PostForm.Get("client_id") != testClientID || r.PostForm.Get("redirect_uri") != testRedirectURL {
+			http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+			return
+		}
+		provider.mu.Lock()
+		provider.verifiers = append(provider.verifiers, r.PostForm.Get("code_verifier"))
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, provider.claims)
+		provider.mu.Unlock()
+
+		token.Header["kid"] = "provider-key"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+	provider.Server = httptest.NewServer(mux)
+	t.Cleanup(provider.Close)
+
+	provider.claims = jwt.MapClaims{
+		"iss":            provider.URL,
+		"aud":            testClientID,
+		"sub":            testSubject,
+		"nonce":          testNonce,
+		"email":          testEmail,
+		"email_verified": true,
+		"name":           "Ama Mensah",
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+	}
+	return provider
+}
+
+// setClaim changes a claim of the ID tokens the provider issues
+func (p *oidcProviderStub) setClaim(name string, value any) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims[name] = value
+}
+
+func (p *oidcProviderStub) receivedVerifiers() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.verifiers...)
+}
+
+// recordingMailer keeps the subjects of the emails it is asked to send
+type recordingMailer struct {
+	subjects []string
+}
+
+func (m *recordingMailer) Send(ctx context.Context, to, subject, body string) error {
+	m.subjects = append(m.subjects, subject)
+	return nil
+}
+
+func newTestOIDCService(t *testing.T) (*AuthService, sqlmock.Sqlmock, *oidcProviderStub, *recordingMailer) {
+	db, mock := newMockDB(t)
+	provider := newOIDCProviderStub(t)
+	cfg := &config.Config{
+		OIDCProviders:      []config.OIDCProvider{{Name: testProviderName, Issuer: provider.URL, ClientID: testClientID, Scopes: []string{"openid", "email", "profile"}}},
+		OIDCRedirectURL:    testRedirectURL,
+		OIDCStateExpiry:    10 * time.Minute,
+		JWTExpiryMinutes:   15 * time.Minute,
+		PreAuthTokenExpiry: 5 * time.Minute,
+		JWTIssuer:          "chow",
+		JWTAudience:        "chow",
+	}
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	keys := NewSigningKeyService(cfg, nil)
+	keys.signing = &loadedKey{kid: "test", method: jwt.SigningMethodEdDSA, private: private, public: private.Public()}
+
+	mailer := &recordingMailer{}
+	service := NewAuthService(cfg, repository.NewUserRepository(db), repository.NewSecurityRepository(db), repository.NewTwoFactorRepository(db),
+		repository.NewIdentityRepository(db), repository.NewAPIKeyRepository(db), keys, mailer)
+	return service, mock, provider, mailer
+}
+
+// captureArg matches any string argument and keeps it
+type captureArg struct {
+	value *string
+}
+
+func (c captureArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*c.value = s
+	return ok
+}
+
+var (
+	userColumns     = []string{"id", "email", "username", "password", "role", "email_verified_at", "deletion_scheduled_at", "created_at", "updated_at"}
+	identityColumns = []string{"id", "user_id", "provider", "subject", "email", "created_at", "last_login_at"}
+)
+
+func userRow(user *model.User, password any) *sqlmock.Rows {
+	return sqlmock.NewRows(userColumns).AddRow(user.ID, user.Email, user.Username, password, string(user.Role), user.EmailVerifiedAt, nil, user.CreatedAt, user.UpdatedAt)
+}
+
+// expectTakeState expects the state to be taken, returning the stored sign in or link for the user
+func expectTakeState(mock sqlmock.Sqlmock, userID *uuid.UUID) {
+	var owner any
+	if userID != nil {
+		owner = *userID
+	}
+	mock.ExpectQuery(`DELETE FROM oidc_states`).WithArgs(hashToken(testState)).
+		WillReturnRows(sqlmock.NewRows([]string{"state_hash", "provider", "code_verifier", "nonce", "user_id", "expires_at", "created_at"}).
+			AddRow(hashToken(testState), testProviderName, testVerifier, testNonce, owner, time.Now().Add(5*time.Minute), time.Now()))
+}
+
+func expectNoIdentity(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT (.+) FROM user_identities\s+WHERE provider = \$1 AND subject = \$2`).
+		WithArgs(testProviderName, testSubject).WillReturnRows(sqlmock.NewRows(identityColumns))
+}
+
+// expectLinkIdentity expects the identity to be linked to the user in a transaction
+func expectLinkIdentity(mock sqlmock.Sqlmock, userID uuid.UUID) {
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO user_identities`).WithArgs(userID.String(), testProviderName, testSubject, testEmail).
+		WillReturnRows(sqlmock.NewRows(identityColumns).AddRow(uuid.New(), userID, testProviderName, testSubject, testEmail, time.Now(), time.Now()))
+	mock.ExpectCommit()
+}
+
+func expectSecurityEvent(mock sqlmock.Sqlmock, eventType model.SecurityEventType) {
+	mock.ExpectQuery(`INSERT INTO security_events`).WithArgs(sqlmock.AnyArg(), string(eventType), nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "ip_address", "user_agent", "device_id", "created_at"}).
+			AddRow(uuid.New(), nil, string(eventType), nil, nil, nil, time.Now()))
+}
+
+// expectSignIn expects a user without two-factor authentication to be signed in
+func expectSignIn(mock sqlmock.Sqlmock, user *model.User) {
+	mock.ExpectQuery(`FROM user_two_factor`).WithArgs(user.ID.String()).WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectExec(`DELETE FROM login_attempts`).WithArgs(user.Email).WillReturnResult(sqlmock.NewResult(0, 0))
+	expectSecurityEvent(mock, model.LoginSucceeded)
+}
+
+func testOIDCUser(verified bool) *model.User {
+	now := time.Now()
+	user := &model.User{ID: uuid.New(), Email: testEmail, Username: "ama_m", Role: model.AppUser, CreatedAt: now, UpdatedAt: now}
+	if verified {
+		user.EmailVerifiedAt = &now
+	}
+	return user
+}
+
+func TestStartOIDCLoginUsesPKCE(t *testing.T) {
+	service, mock, provider, _ := newTestOIDCService(t)
+
+	var stateHash, verifier, nonce string
+	mock.ExpectExec(`INSERT INTO oidc_states`).
+		WithArgs(captureArg{&stateHash}, testProviderName, captureArg{&verifier}, captureArg{&nonce}, nil, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	res, err := service.StartOIDCLogin(context.Background(), testProviderName)
+	if err != nil {
+		t.Fatalf("StartOIDCLogin() error = %v", err)
+	}
+
+	authorization, err := url.Parse(res.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("invalid authorization URL %q: %v", res.AuthorizationURL, err)
+	}
+	if got := authorization.Scheme + "://" + authorization.Host + authorization.Path; got != provider.URL+"/authorize" {
+		t.Errorf("authorization endpoint = %q, want %q", got, provider.URL+"/authorize")
+	}
+	query := authorization.Query()
+	challenge := sha256.Sum256([]byte(verifier))
+	if got, want := query.Get("code_challenge"), base64.RawURLEncoding.EncodeToString(challenge[:]); verifier == "" || got != want {
+		t.Errorf("code_challenge = %q, want the S256 challenge of the stored verifier %q", got, want)
+	}
+	if got := query.Get("code_challenge_method"); got != "S256" {
+		t.Errorf("code_challenge_method = %q, want S256", got)
+	}
+	if query.Get("code_verifier") != "" {
+		t.Error("verifier was sent to the authorization endpoint")
+	}
+	if query.Get("state") != res.State || hashToken(res.State) != stateHash {
+		t.Errorf("state %q is not the one returned or stored", query.Get("state"))
+	}
+	if nonce == "" || query.Get("nonce") != nonce {
+		t.Errorf("nonce = %q, want the stored nonce %q", query.Get("nonce"), nonce)
+	}
+	if query.Get("client_id") != testClientID || query.Get("redirect_uri") != testRedirectURL || query.Get("response_type") != "code" {
+		t.Errorf("unexpected authorization request %v", query)
+	}
+}
+
+func TestStartOIDCLoginRejectsOtherIssuer(t *testing.T) {
+	service, _, provider, _ := newTestOIDCService(t)
+	provider.issuer = "https://attacker.test"
+
+	if _, err := service.StartOIDCLogin(context.Background(), testProviderName); !errors.Is(err, ErrOIDCProviderDown) {
+		t.Fatalf("StartOIDCLogin() error = %v, want %v", err, ErrOIDCProviderDown)
+	}
+}
+
+func TestCompleteOIDCLoginStateIsSingleUse(t *testing.T) {
+	service, mock, provider, _ := newTestOIDCService(t)
+	user := testOIDCUser(true)
+	req := model.OIDCCallbackReq{State: testState, Code: "code"}
+
+	expectTakeState(mock, nil)
+	identityID := uuid.New()
+	mock.ExpectQuery(`FROM user_identities\s+WHERE provider = \$1 AND subject = \$2`).WithArgs(testProviderName, testSubject).
+		WillReturnRows(sqlmock.NewRows(identityColumns).AddRow(identityID, user.ID, testProviderName, testSubject, testEmail, time.Now(), time.Now()))
+	mock.ExpectExec(`UPDATE user_identities`).WithArgs(identityID.String(), testEmail).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(user.ID.String()).WillReturnRows(userRow(user, nil))
+	expectSignIn(mock, user)
+
+	if _, err := service.CompleteOIDCLogin(context.Background(), req); err != nil {
+		t.Fatalf("first CompleteOIDCLogin() error = %v", err)
+	}
+
+	// the state was deleted when it was taken so it is no longer found
+	mock.ExpectQuery(`DELETE FROM oidc_states`).WithArgs(hashToken(testState)).
+		WillReturnRows(sqlmock.NewRows([]string{"state_hash", "provider", "code_verifier", "nonce", "user_id", "expires_at", "created_at"}))
+
+	if _, err := service.CompleteOIDCLogin(context.Background(), req); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("second CompleteOIDCLogin() error = %v, want %v", err, ErrInvalidOIDCState)
+	}
+	if verifiers := provider.receivedVerifiers(); len(verifiers) != 1 || verifiers[0] != testVerifier {
+		t.Errorf("token endpoint received verifiers %v, want only %q", verifiers, testVerifier)
+	}
+}
+
+func TestCompleteOIDCLoginRejectsInvalidIDToken(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim string
+		value any
+	}{
+		{"nonce mismatch", "nonce", "another-nonce"},
+		{"other issuer", "iss", "https://attacker.test"},
+		{"other audience", "aud", "another-client"},
+		{"expired", "exp", time.Now().Add(-time.Minute).Unix()},
+		{"no subject", "sub", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mock, provider, _ := newTestOIDCService(t)
+			provider.setClaim(tt.claim, tt.value)
+			expectTakeState(mock, nil)
+
+			_, err := service.CompleteOIDCLogin(context.Background(), model.OIDCCallbackReq{State: testState, Code: "code"})
+			if !errors.Is(err, ErrOIDCLoginFailed) {
+				t.Fatalf("CompleteOIDCLogin() error = %v, want %v", err, ErrOIDCLoginFailed)
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLoginRejectsLinkState(t *testing.T) {
+	service, mock, provider, _ := newTestOIDCService(t)
+	userID := uuid.New()
+	expectTakeState(mock, &userID)
+
+	_, err := service.CompleteOIDCLogin(context.Background(), model.OIDCCallbackReq{State: testState, Code: "code"})
+	if !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("CompleteOIDCLogin() error = %v, want %v", err, ErrInvalidOIDCState)
+	}
+	if verifiers := provider.receivedVerifiers(); len(verifiers) != 0 {
+		t.Errorf("code was exchanged for a link started by a user")
+	}
+}
+
+func TestCompleteOIDCLoginRefusesExistingAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     *model.User
+		password any
+	}{
+		{"registered with a password", testOIDCUser(false), "$2a$10$hash"},
+		// an account created by another provider is not linked either as its owner can sign in with it and link the provider
+		{"created by another provider", testOIDCUser(true), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mock, _, mailer := newTestOIDCService(t)
+			expectTakeState(mock, nil)
+			expectNoIdentity(mock)
+			// the account is neither linked nor has its password or two-factor authentication removed
+			mock.ExpectQuery(`FROM users\s+WHERE email = \$1`).WithArgs(testEmail).WillReturnRows(userRow(tt.user, tt.password))
+
+			_, err := service.CompleteOIDCLogin(context.Background(), model.OIDCCallbackReq{State: testState, Code: "code"})
+			if !errors.Is(err, ErrOIDCAccountExists) {
+				t.Fatalf("CompleteOIDCLogin() error = %v, want %v", err, ErrOIDCAccountExists)
+			}
+			if len(mailer.subjects) != 0 {
+				t.Errorf("sent emails %v, want none", mailer.subjects)
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLoginKeepsTwoFactorOfLinkedUser(t *testing.T) {
+	service, mock, _, _ := newTestOIDCService(t)
+	user := testOIDCUser(true)
+
+	expectTakeState(mock, nil)
+	identityID := uuid.New()
+	mock.ExpectQuery(`FROM user_identities\s+WHERE provider = \$1 AND subject = \$2`).WithArgs(testProviderName, testSubject).
+		WillReturnRows(sqlmock.NewRows(identityColumns).AddRow(identityID, user.ID, testProviderName, testSubject, testEmail, time.Now(), time.Now()))
+	mock.ExpectExec(`UPDATE user_identities`).WithArgs(identityID.String(), testEmail).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(user.ID.String()).WillReturnRows(userRow(user, "$2a$10$hash"))
+	mock.ExpectQuery(`FROM user_two_factor`).WithArgs(user.ID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "enabled_at", "last_used_step", "created_at"}).AddRow(user.ID, "secret", time.Now(), nil, time.Now()))
+
+	res, err := service.CompleteOIDCLogin(context.Background(), model.OIDCCallbackReq{State: testState, Code: "code"})
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin() error = %v", err)
+	}
+	if !res.TwoFactorRequired || res.PreAuthToken == "" || res.AccessToken != "" {
+		t.Errorf("CompleteOIDCLogin() = %+v, want a pre-auth token for the second factor", res)
+	}
+}
+
+func TestCompleteOIDCLoginRefusesUnverifiedEmail(t *testing.T) {
+	service, mock, provider, _ := newTestOIDCService(t)
+	provider.setClaim("email_verified", "false")
+	expectTakeState(mock, nil)
+	expectNoIdentity(mock)
+
+	_, err := service.CompleteOIDCLogin(context.Background(), model.OIDCCallbackReq{State: testState, Code: "code"})
+	if !errors.Is(err, ErrOIDCEmailNotVerified) {
+		t.Fatalf("CompleteOIDCLogin() error = %v, want %v", err, ErrOIDCEmailNotVerified)
+	}
+}
+
+func TestCompleteOIDCLoginCreatesPasswordlessAccount(t *testing.T) {
+	service, mock, _, mailer := newTestOIDCService(t)
+	user := testOIDCUser(true)
+	user.Username = "ama_mensah"
+
+	expectTakeState(mock, nil)
+	expectNoIdentity(mock)
+	mock.ExpectQuery(`FROM users\s+WHERE email = \$1`).WithArgs(testEmail).WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectQuery(`FROM users\s+WHERE username = \$1`).WithArgs("ama_mensah").WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).WithArgs(testEmail, "ama_mensah", nil, string(model.AppUser), sqlmock.AnyArg()).WillReturnRows(userRow(user, nil))
+	mock.ExpectQuery(`INSERT INTO user_identities`).WithArgs(user.ID.String(), testProviderName, testSubject, testEmail).
+		WillReturnRows(sqlmock.NewRows(identityColumns).AddRow(uuid.New(), user.ID, testProviderName, testSubject, testEmail, time.Now(), time.Now()))
+	mock.ExpectCommit()
+	expectSecurityEvent(mock, model.IdentityLinked)
+	expectSignIn(mock, user)
+
+	res, err := service.CompleteOIDCLogin(context.Background(), model.OIDCCallbackReq{State: testState, Code: "code"})
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin() error = %v", err)
+	}
+	if res.AccessToken == "" || res.User == nil || res.User.HasPassword {
+		t.Errorf("CompleteOIDCLogin() = %+v, want an access token for a user without a password", res)
+	}
+	if len(mailer.subjects) != 1 {
+		t.Errorf("sent emails %v, want the welcome email", mailer.subjects)
+	}
+}
+
+func TestCompleteOIDCLinkLinksSignedInUser(t *testing.T) {
+	service, mock, provider, _ := newTestOIDCService(t)
+	// the provider email does not need to be verified or match since the user signed in to both
+	provider.setClaim("email_verified", false)
+	user := testOIDCUser(false)
+
+	expectTakeState(mock, &user.ID)
+	expectNoIdentity(mock)
+	mock.ExpectQuery(`FROM users\s+WHERE id = \$1`).WithArgs(user.ID.String()).WillReturnRows(userRow(user, "$2a$10$hash"))
+	expectLinkIdentity(mock, user.ID)
+	expectSecurityEvent(mock, model.IdentityLinked)
+
+	identity, err := service.CompleteOIDCLink(context.Background(), user.ID, model.OIDCCallbackReq{State: testState, Code: "code"})
+	if err != nil {
+		t.Fatalf("CompleteOIDCLink() error = %v", err)
+	}
+	if identity.UserID != user.ID || identity.Provider != testProviderName {
+		t.Errorf("CompleteOIDCLink() = %+v, want an identity of the user", identity)
+	}
+}
+
+func TestCompleteOIDCLinkRejectsOtherUsersState(t *testing.T) {
+	for name, owner := range map[string]*uuid.UUID{"sign in": nil, "other user": ptr(uuid.New())} {
+		t.Run(name, func(t *testing.T) {
+			service, mock, _, _ := newTestOIDCService(t)
+			expectTakeState(mock, owner)
+
+			_, err := service.CompleteOIDCLink(context.Background(), uuid.New(), model.OIDCCallbackReq{State: testState, Code: "code"})
+			if !errors.Is(err, ErrInvalidOIDCState) {
+				t.Fatalf("CompleteOIDCLink() error = %v, want %v", err, ErrInvalidOIDCState)
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLinkRejectsIdentityOfAnotherUser(t *testing.T) {
+	service, mock, _, _ := newTestOIDCService(t)
+	userID := uuid.New()
+
+	expectTakeState(mock, &userID)
+	mock.ExpectQuery(`FROM user_identities\s+WHERE provider = \$1 AND subject = \$2`).WithArgs(testProviderName, testSubject).
+		WillReturnRows(sqlmock.NewRows(identityColumns).AddRow(uuid.New(), uuid.New(), testProviderName, testSubject, testEmail, time.Now(), time.Now()))
+
+	_, err := service.CompleteOIDCLink(context.Background(), userID, model.OIDCCallbackReq{State: testState, Code: "code"})
+	if !errors.Is(err, ErrIdentityLinked) {
+		t.Fatalf("CompleteOIDCLink() error = %v, want %v", err, ErrIdentityLinked)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
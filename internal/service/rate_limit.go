@@ -0,0 +1,132 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrRateLimited = errors.New("too many requests")
+
+// RateLimitError is returned when a client has used up its quota under a rate limit policy
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s, try again in %s", ErrRateLimited, e.RetryAfter.Round(time.Second))
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimitStore keeps the token buckets of rate limited clients
+type RateLimitStore interface {
+	// UpdateBucket applies the update to the bucket with the key while no other update of the bucket can run. Buckets that do not exist start as the initial bucket
+	UpdateBucket(ctx context.Context, key string, initial model.TokenBucket, update func(bucket *model.TokenBucket)) error
+	// DeleteIdleBuckets removes the buckets that have not been updated since the given time
+	DeleteIdleBuckets(ctx context.Context, before time.Time) error
+}
+
+type RateLimitService struct {
+	cfg   *config.Config
+	store RateLimitStore
+}
+
+func NewRateLimitService(cfg *config.Config, store RateLimitStore) *RateLimitService {
+	return &RateLimitService{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+// Take uses up a request from the quota of a client under the policy. The result is nil when the policy is not limited
+func (s *RateLimitService) Take(ctx context.Context, policy model.RateLimitPolicy, client string) (*model.RateLimitResult, error) {
+	limit, ok := s.cfg.RateLimits[string(policy)]
+	if !ok {
+		return nil, nil
+	}
+
+	// buckets hold up to a full period of requests and refill at the rate that makes them full again after a period
+	capacity := float64(limit.Limit)
+	rate := capacity / limit.Period.Seconds()
+	now := time.Now()
+
+	result := &model.RateLimitResult{Limit: limit.Limit, Period: limit.Period}
+	initial := model.TokenBucket{Tokens: capacity, UpdatedAt: now}
+	err := s.store.UpdateBucket(ctx, string(policy)+":"+client, initial, func(bucket *model.TokenBucket) {
+		// clocks of other instances may be slightly ahead
+		if elapsed := now.Sub(bucket.UpdatedAt); elapsed > 0 {
+			bucket.Tokens = math.Min(capacity, bucket.Tokens+elapsed.Seconds()*rate)
+			bucket.UpdatedAt = now
+		}
+
+		if bucket.Tokens >= 1 {
+			bucket.Tokens--
+			result.Allowed = true
+		} else {
+			result.RetryAfter = refillTime(1-bucket.Tokens, rate)
+		}
+		result.Remaining = int(bucket.Tokens)
+		result.Reset = refillTime(capacity-bucket.Tokens, rate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PurgeIdleBuckets removes the buckets that have refilled completely. They are recreated full on the next request
+func (s *RateLimitService) PurgeIdleBuckets(ctx context.Context) error {
+	var longest time.Duration
+	for _, limit := range s.cfg.RateLimits {
+		longest = max(longest, limit.Period)
+	}
+	return s.store.DeleteIdleBuckets(ctx, time.Now().Add(-longest))
+}
+
+// refillTime returns how long it takes for the tokens to be added to a bucket
+func refillTime(tokens, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+// MemoryRateLimitStore keeps token buckets in memory. The limits only apply to the requests served by this instance
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*model.TokenBucket
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*model.TokenBucket)}
+}
+
+func (s *MemoryRateLimitStore) UpdateBucket(ctx context.Context, key string, initial model.TokenBucket, update func(bucket *model.TokenBucket)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &initial
+		s.buckets[key] = bucket
+	}
+	update(bucket)
+	return nil
+}
+
+func (s *MemoryRateLimitStore) DeleteIdleBuckets(ctx context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, bucket := range s.buckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/i18n"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// minSimilaritySupport is the number of users who must have interacted with two joints for them to be considered similar
+const minSimilaritySupport = 2
+
+type RecommendationService struct {
+	cfg                *config.Config
+	recommendationRepo *repository.RecommendationRepository
+}
+
+func NewRecommendationService(cfg *config.Config, recommendationRepo *repository.RecommendationRepository) *RecommendationService {
+	return &RecommendationService{
+		cfg:                cfg,
+		recommendationRepo: recommendationRepo,
+	}
+}
+
+// GetRecommendedJoints recommends joints within the radius that the user has neither voted on nor visited. Joints are ranked by their similarity to the joints the user liked
+// and the user's affinity for their category. Users who have not liked enough joints get the most popular joints near them instead
+func (s *RecommendationService) GetRecommendedJoints(ctx context.Context, userID uuid.UUID, coord model.Coordinate, radius float64, query model.PaginationQuery) ([]*model.RecommendedJoint, *model.Pagination, error) {
+	if radius > s.cfg.MaxNearbyRadius {
+		return nil, nil, ErrMaxSearchRadiusExceeded
+	}
+
+	liked, err := s.recommendationRepo.CountLikedJoints(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the two rankings are sorted differently so a cursor is only valid for the ranking it was issued for
+	area := fmt.Sprintf("%s:%g,%g,%g", userID, coord.Latitude, coord.Longitude, radius)
+	var joints []*model.RecommendedJoint
+	var pagination *model.Pagination
+	if liked < s.cfg.RecommendationMinInteractions {
+		joints, pagination, err = readPage(s.cfg, query, "popular-joints:"+area, func(page model.Page) (*model.Paged[*model.RecommendedJoint], error) {
+			return s.recommendationRepo.GetPopularNearby(ctx, userID, coord, radius, page)
+		}, func(joint *model.RecommendedJoint) []any {
+			return []any{joint.Score, *joint.Distance, joint.ID}
+		})
+	} else {
+		joints, pagination, err = readPage(s.cfg, query, "recommended-joints:"+area, func(page model.Page) (*model.Paged[*model.RecommendedJoint], error) {
+			return s.recommendationRepo.GetRecommended(ctx, userID, coord, radius, s.cfg.CategoryAffinityWeight, page)
+		}, func(joint *model.RecommendedJoint) []any {
+			return []any{joint.Relevance, joint.Score, joint.ID}
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	locale := i18n.Preferred(RequestInfoFromContext(ctx).Locales)
+	for _, joint := range joints {
+		explainRecommendation(joint, s.cfg.CategoryAffinityWeight, locale)
+	}
+	return joints, pagination, nil
+}
+
+// RecomputeSimilarities refreshes the similarities between joints from the latest votes and check-ins
+func (s *RecommendationService) RecomputeSimilarities(ctx context.Context) error {
+	count, computed, err := s.recommendationRepo.RecomputeSimilarities(ctx, s.cfg.RecommendationNeighbours, minSimilaritySupport)
+	if err != nil {
+		return err
+	}
+	if computed {
+		log.Printf("recomputed %d joint similarities", count)
+	}
+	return nil
+}
+
+// explainRecommendation sets the reason of a recommendation from whichever of its scores contributed the most. The explanation is written in the locale
+func explainRecommendation(joint *model.RecommendedJoint, affinityWeight float64, locale string) {
+	switch {
+	case joint.BecauseOfName != nil && joint.Similarity >= affinityWeight*joint.CategoryAffinity:
+		joint.Reason = model.SimilarJointRecommendation
+		joint.Explanation = i18n.Sprintf(locale, "Because you liked %s", *joint.BecauseOfName)
+	case joint.CategoryAffinity > 0:
+		joint.Reason = model.CategoryRecommendation
+		category := i18n.Translate(locale, strings.ReplaceAll(string(joint.Category), "_", " "))
+		joint.Explanation = i18n.Sprintf(locale, "Because you like %s spots", category)
+		joint.BecauseOfID, joint.BecauseOfName = nil, nil
+	default:
+		joint.Reason = model.PopularRecommendation
+		joint.Explanation = i18n.Translate(locale, "Popular near you")
+	}
+}
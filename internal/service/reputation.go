@@ -0,0 +1,326 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrUserNotFound            = errors.New("user not found")
+	ErrCityNotFound            = errors.New("city not found")
+	ErrNotEligibleForModerator = errors.New("user is not eligible for moderator nomination")
+)
+
+// badgeMilestone is a badge awarded once a user reaches a number of contributions
+type badgeMilestone struct {
+	count int
+	badge model.Badge
+}
+
+var (
+	jointBadges   = []badgeMilestone{{1, model.FirstJointBadge}, {10, model.TenJointsBadge}}
+	checkinBadges = []badgeMilestone{{1, model.FirstCheckinBadge}, {100, model.HundredCheckinsBadge}}
+)
+
+type ReputationService struct {
+	cfg            *config.Config
+	reputationRepo *repository.ReputationRepository
+	userRepo       *repository.UserRepository
+	jointRepo      *repository.JointRepository
+	auditRepo      *repository.AuditRepository
+	notifier       Notifier
+}
+
+func NewReputationService(cfg *config.Config, reputationRepo *repository.ReputationRepository, userRepo *repository.UserRepository, jointRepo *repository.JointRepository, auditRepo *repository.AuditRepository, notifier Notifier) *ReputationService {
+	return &ReputationService{
+		cfg:            cfg,
+		reputationRepo: reputationRepo,
+		userRepo:       userRepo,
+		jointRepo:      jointRepo,
+		auditRepo:      auditRepo,
+		notifier:       notifier,
+	}
+}
+
+// RegisterSubscribers awards reputation and badges as contributions are moderated
+func (s *ReputationService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("reputation", s.handleEvent,
+		model.JointApprovedEvent,
+		model.JointRejectedEvent,
+		model.ComplaintResolvedEvent,
+		model.CheckinCreatedEvent,
+	)
+}
+
+// GetTrustLevel returns the trust level of a user
+func (s *ReputationService) GetTrustLevel(ctx context.Context, userID uuid.UUID) (model.TrustLevel, error) {
+	reputation, err := s.reputationRepo.GetReputation(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return model.NewTrustLevel, ErrUserNotFound
+		}
+		return model.NewTrustLevel, err
+	}
+	return model.TrustLevelFor(reputation), nil
+}
+
+// GetProfile retrieves the public profile of a user
+func (s *ReputationService) GetProfile(ctx context.Context, userID uuid.UUID) (*model.UserProfile, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	reputation, err := s.reputationRepo.GetReputation(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	badges, err := s.reputationRepo.GetUserBadges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	joints, err := s.reputationRepo.CountApprovedJoints(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	checkins, err := s.reputationRepo.CountCheckins(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	level := model.TrustLevelFor(reputation)
+	return &model.UserProfile{
+		ID:             user.ID,
+		Username:       user.Username,
+		Role:           user.Role,
+		Reputation:     reputation,
+		TrustLevel:     level,
+		TrustLevelName: level.String(),
+		Abilities:      level.Abilities(),
+		Badges:         badges,
+		ApprovedJoints: joints,
+		Checkins:       checkins,
+		JoinedAt:       user.CreatedAt,
+	}, nil
+}
+
+// GetLedger retrieves the reputation ledger of a user, most recent first
+func (s *ReputationService) GetLedger(ctx context.Context, userID uuid.UUID, query model.PaginationQuery) ([]*model.ReputationEntry, *model.Pagination, error) {
+	return readPage(s.cfg, query, "reputation-ledger:"+userID.String(), func(page model.Page) (*model.Paged[*model.ReputationEntry], error) {
+		return s.reputationRepo.GetUserEntries(ctx, userID, page)
+	}, func(entry *model.ReputationEntry) []any {
+		return []any{entry.CreatedAt, entry.ID}
+	})
+}
+
+// GetLeaderboard ranks users by reputation, or by the points earned for joints in a city when one is given
+func (s *ReputationService) GetLeaderboard(ctx context.Context, city string, query model.PaginationQuery) ([]*model.LeaderboardEntry, *model.Pagination, error) {
+	if city == "" {
+		return readPage(s.cfg, query, "leaderboard", func(page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+			return s.reputationRepo.GetLeaderboard(ctx, page)
+		}, leaderboardKey)
+	}
+
+	exists, err := s.reputationRepo.CityExists(ctx, city)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exists {
+		return nil, nil, ErrCityNotFound
+	}
+	return readPage(s.cfg, query, "leaderboard:"+city, func(page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+		return s.reputationRepo.GetCityLeaderboard(ctx, city, page)
+	}, leaderboardKey)
+}
+
+// GetCities retrieves the cities leaderboards are available for
+func (s *ReputationService) GetCities(ctx context.Context) ([]*model.City, error) {
+	return s.reputationRepo.GetCities(ctx)
+}
+
+// GetModeratorCandidates ranks the users whose trust level allows them to be nominated as moderators
+func (s *ReputationService) GetModeratorCandidates(ctx context.Context, query model.PaginationQuery) ([]*model.LeaderboardEntry, *model.Pagination, error) {
+	minReputation := model.TrustLevelThresholds[model.AbilityTrustLevels[model.ModeratorNominationAbility]]
+	return readPage(s.cfg, query, "moderator-candidates", func(page model.Page) (*model.Paged[*model.LeaderboardEntry], error) {
+		return s.reputationRepo.GetModeratorCandidates(ctx, minReputation, page)
+	}, leaderboardKey)
+}
+
+// leaderboardKey returns the sort key of an entry in leaderboards
+func leaderboardKey(entry *model.LeaderboardEntry) []any {
+	return []any{entry.Points, entry.Username, entry.UserID}
+}
+
+// PromoteToModerator makes a regular user a moderator. Only users whose trust level allows moderator nomination can be promoted
+func (s *ReputationService) PromoteToModerator(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	existing, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	level, err := s.GetTrustLevel(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Role != model.AppUser || !level.Can(model.ModeratorNominationAbility) {
+		return nil, ErrNotEligibleForModerator
+	}
+
+	tx, err := s.reputationRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	user, err := s.userRepo.UpdateRole(ctx, tx, userID, model.Moderator)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, s.auditRepo, model.UserPromotedAction, model.UserTarget, userID, existing, user); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// handleEvent records the reputation earned or lost for a domain event and awards the badges reached.
+// Points are recorded at most once per source record so redelivered events are harmless
+func (s *ReputationService) handleEvent(ctx context.Context, event *model.Event) error {
+	var userID uuid.UUID
+	var entry *model.ReputationEntry
+	var milestones []badgeMilestone
+	var count func(context.Context, uuid.UUID) (int, error)
+
+	switch event.Type {
+	case model.JointApprovedEvent:
+		var joint model.Joint
+		if err := json.Unmarshal(event.Payload, &joint); err != nil {
+			return err
+		}
+		userID = joint.CreatorID
+		entry = &model.ReputationEntry{Reason: model.JointApprovedReputation, JointID: &joint.ID, SourceID: joint.ID}
+		milestones, count = jointBadges, s.reputationRepo.CountApprovedJoints
+
+	case model.JointRejectedEvent:
+		var payload model.JointRejectedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		userID = payload.Joint.CreatorID
+		entry = &model.ReputationEntry{Reason: model.JointRejectedReputation, JointID: &payload.Joint.ID, SourceID: payload.Joint.ID}
+
+	case model.ComplaintResolvedEvent:
+		var complaint model.Complaint
+		if err := json.Unmarshal(event.Payload, &complaint); err != nil {
+			return err
+		}
+		userID = complaint.UserID
+		entry = &model.ReputationEntry{Reason: model.ComplaintUpheldReputation, JointID: &complaint.JointID, SourceID: complaint.ID}
+
+	case model.CheckinCreatedEvent:
+		var checkin model.Checkin
+		if err := json.Unmarshal(event.Payload, &checkin); err != nil {
+			return err
+		}
+		userID = checkin.UserID
+		milestones, count = checkinBadges, s.reputationRepo.CountCheckins
+
+	default:
+		return nil
+	}
+
+	tx, err := s.reputationRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var candidates []model.Badge
+	if entry != nil {
+		entry.UserID = userID
+		entry.Points = model.ReputationPoints[entry.Reason]
+		before, after, applied, err := s.reputationRepo.AddEntry(ctx, tx, entry)
+		if err != nil {
+			// the user has since been removed
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		// votes of the user are weighted differently once they cross the trusted threshold in either direction
+		trusted := model.TrustLevelThresholds[model.AbilityTrustLevels[model.WeightedVoteAbility]]
+		if applied && (before < trusted) != (after < trusted) {
+			if err := s.jointRepo.UpdateVoterScores(ctx, tx, userID, voteWeights(s.cfg)); err != nil {
+				return err
+			}
+		}
+		if after >= trusted {
+			candidates = append(candidates, model.TrustedBadge)
+		}
+	}
+	if count != nil {
+		n, err := count(ctx, userID)
+		if err != nil {
+			return err
+		}
+		for _, milestone := range milestones {
+			if n >= milestone.count {
+				candidates = append(candidates, milestone.badge)
+			}
+		}
+	}
+
+	var awarded []string
+	for _, badge := range candidates {
+		created, err := s.reputationRepo.AwardBadge(ctx, tx, userID, badge)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if created {
+			awarded = append(awarded, model.BadgeNames[badge])
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	if len(awarded) > 0 {
+		notification := &model.Notification{
+			Type:       model.BadgeAwardedNotification,
+			Title:      "Badge earned",
+			Body:       fmt.Sprintf("You earned the %s badge", strings.Join(awarded, ", ")),
+			TargetType: targetType(model.UserTarget),
+			TargetID:   &userID,
+			EventID:    &event.ID,
+		}
+		if err := s.notifier.NotifyUser(ctx, userID, notification); err != nil {
+			log.Println(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrSavedSearchNotFound     = errors.New("saved search not found")
+	ErrSavedSearchLimitReached = errors.New("maximum number of saved searches reached")
+)
+
+// maxSavedSearches is the number of saved searches a user can have
+const maxSavedSearches = 10
+
+type SavedSearchService struct {
+	cfg             *config.Config
+	savedSearchRepo *repository.SavedSearchRepository
+	notifier        Notifier
+	mailer          Mailer
+}
+
+func NewSavedSearchService(cfg *config.Config, savedSearchRepo *repository.SavedSearchRepository, notifier Notifier, mailer Mailer) *SavedSearchService {
+	return &SavedSearchService{
+		cfg:             cfg,
+		savedSearchRepo: savedSearchRepo,
+		notifier:        notifier,
+		mailer:          mailer,
+	}
+}
+
+// RegisterSubscribers matches newly approved joints against saved searches as they are approved
+func (s *SavedSearchService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("saved-searches", s.handleJointApproved, model.JointApprovedEvent)
+}
+
+func (s *SavedSearchService) CreateSavedSearch(ctx context.Context, data *model.SavedSearch) (*model.SavedSearch, error) {
+	if data.Radius != nil && *data.Radius > s.cfg.MaxNearbyRadius {
+		return nil, ErrMaxSearchRadiusExceeded
+	}
+	if data.Delivery == "" {
+		data.Delivery = model.NotificationDelivery
+	}
+
+	count, err := s.savedSearchRepo.CountUserSearches(ctx, data.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxSavedSearches {
+		return nil, ErrSavedSearchLimitReached
+	}
+
+	return s.savedSearchRepo.Create(ctx, data)
+}
+
+// GetSavedSearches retrieves the saved searches of a user
+func (s *SavedSearchService) GetSavedSearches(ctx context.Context, userID uuid.UUID) ([]*model.SavedSearch, error) {
+	return s.savedSearchRepo.GetUserSearches(ctx, userID)
+}
+
+// GetSavedSearchMatches retrieves the joints matched by a saved search of a user, newest first
+func (s *SavedSearchService) GetSavedSearchMatches(ctx context.Context, userID, id uuid.UUID, query model.PaginationQuery) ([]*model.SavedSearchMatch, *model.Pagination, error) {
+	if _, err := s.savedSearchRepo.GetByID(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrSavedSearchNotFound
+		}
+		return nil, nil, err
+	}
+	return readPage(s.cfg, query, "saved-search-matches:"+id.String(), func(page model.Page) (*model.Paged[*model.SavedSearchMatch], error) {
+		return s.savedSearchRepo.GetMatches(ctx, id, page)
+	}, func(match *model.SavedSearchMatch) []any {
+		return []any{match.MatchedAt, match.Joint.ID}
+	})
+}
+
+func (s *SavedSearchService) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	if err := s.savedSearchRepo.DeleteByID(ctx, userID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrSavedSearchNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SendEmailDigests emails every user with email digest searches a summary of the joints matched since their last digest
+func (s *SavedSearchService) SendEmailDigests(ctx context.Context) error {
+	cutoff := time.Now()
+	matches, err := s.savedSearchRepo.GetPendingDigestMatches(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	// matches are ordered by user so each run of matches forms one digest
+	var errs []error
+	for start := 0; start < len(matches); {
+		end := start
+		for end < len(matches) && matches[end].UserID == matches[start].UserID {
+			end++
+		}
+		if err := s.sendDigest(ctx, matches[start:end], cutoff); err != nil {
+			errs = append(errs, err)
+		}
+		start = end
+	}
+	return errors.Join(errs...)
+}
+
+// sendDigest emails a user the matches of their saved searches and marks them as sent
+func (s *SavedSearchService) sendDigest(ctx context.Context, matches []*model.DigestMatch, cutoff time.Time) error {
+	user := matches[0]
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\nNew joints have been added that match your saved searches.\n", user.Username)
+	for i, match := range matches {
+		if i == 0 || match.SavedSearchID != matches[i-1].SavedSearchID {
+			fmt.Fprintf(&body, "\n%s\n", match.SavedSearchName)
+		}
+		fmt.Fprintf(&body, "- %s\n", match.JointName)
+	}
+	body.WriteString("\nYou can manage your saved searches in the app.\n")
+
+	subject := fmt.Sprintf("%d new joints matching your saved searches", len(matches))
+	if len(matches) == 1 {
+		subject = "A new joint matching your saved searches"
+	}
+	if err := s.mailer.Send(ctx, user.Email, subject, body.String()); err != nil {
+		return err
+	}
+	return s.savedSearchRepo.MarkDigestSent(ctx, user.UserID, cutoff)
+}
+
+// handleJointApproved records the joint against every matching saved search and notifies the users who asked for instant alerts. Users with email digests are alerted by the digest job
+func (s *SavedSearchService) handleJointApproved(ctx context.Context, event *model.Event) error {
+	var joint model.Joint
+	if err := json.Unmarshal(event.Payload, &joint); err != nil {
+		return err
+	}
+	if joint.Visibility != model.VisibleJoint {
+		return nil
+	}
+
+	searches, err := s.savedSearchRepo.GetMatchingSearches(ctx, joint.ID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, search := range searches {
+		instant := search.Delivery == model.NotificationDelivery
+		// a redelivered event finds the match already recorded, but the notification is still sent in case the previous attempt failed before it
+		if err := s.savedSearchRepo.CreateMatch(ctx, search.ID, joint.ID, instant); err != nil && !errors.Is(err, repository.ErrAlreadyExist) {
+			errs = append(errs, err)
+			continue
+		}
+		if !instant {
+			continue
+		}
+
+		if err := s.notifier.NotifyUser(ctx, search.UserID, &model.Notification{
+			Type:       model.SavedSearchMatchNotification,
+			Title:      "New joint near you",
+			Body:       fmt.Sprintf("%q matches your saved search %q", joint.Name, search.Name),
+			TargetType: targetType(model.JointTarget),
+			TargetID:   &joint.ID,
+			EventID:    &event.ID,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
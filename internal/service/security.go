@@ -0,0 +1,236 @@
+package service
+
+import (
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrLoginThrottled     = errors.New("too many failed logins, try again later")
+	ErrAccountLocked      = errors.New("account is temporarily locked after too many failed logins")
+	ErrInvalidUnlockToken = errors.New("invalid or expired unlock token")
+)
+
+// LoginThrottledError is returned when logins are refused for a while after repeated failures. It wraps ErrLoginThrottled or ErrAccountLocked
+type LoginThrottledError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%s, try again in %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// longest user agent and device ID kept with security events
+const (
+	maxUserAgentLength = 500
+	maxDeviceIDLength  = 255
+)
+
+// GetSecurityEvents returns a page of the logins and other security events of a user, newest first
+func (s *AuthService) GetSecurityEvents(ctx context.Context, userID uuid.UUID, query model.PaginationQuery) ([]*model.SecurityEvent, *model.Pagination, error) {
+	return readPage(s.cfg, query, "security-events:"+userID.String(), func(page model.Page) (*model.Paged[*model.SecurityEvent], error) {
+		return s.securityRepo.GetUserEvents(ctx, userID, page)
+	}, func(event *model.SecurityEvent) []any {
+		return []any{event.CreatedAt, event.ID}
+	})
+}
+
+// UnlockAccount lifts the lockout of the account the unlock token was emailed for
+func (s *AuthService) UnlockAccount(ctx context.Context, token string) error {
+	email, err := s.securityRepo.Unlock(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrInvalidUnlockToken
+		}
+		return err
+	}
+
+	// tokens are only issued for registered emails
+	user, err := s.userRepo.GetUserByEmailOrUsername(ctx, "email", email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	s.recordSecurityEvent(ctx, &user.ID, model.AccountUnlocked)
+	return nil
+}
+
+// PurgeSecurityRecords removes the failed logins that no longer count, the expired sign ins with providers and the security events past their retention
+func (s *AuthService) PurgeSecurityRecords(ctx context.Context) error {
+	now := time.Now()
+	if err := s.securityRepo.DeleteStaleLoginAttempts(ctx, now.Add(-s.cfg.LoginAttemptWindow)); err != nil {
+		return err
+	}
+	if _, err := s.identityRepo.DeleteExpiredStates(ctx, now); err != nil {
+		return err
+	}
+	return s.securityRepo.DeleteEventsBefore(ctx, now.Add(-s.cfg.SecurityEventRetention))
+}
+
+// checkLoginAllowed refuses logins from IP addresses with too many recent failures and to emails that are locked or must wait after their last failure
+func (s *AuthService) checkLoginAllowed(ctx context.Context, key string, now time.Time) error {
+	windowStart := now.Add(-s.cfg.LoginAttemptWindow)
+
+	if ip := RequestInfoFromContext(ctx).IPAddress; ip != "" {
+		count, earliest, err := s.securityRepo.CountFailedLoginsByIP(ctx, ip, windowStart)
+		if err != nil {
+			return err
+		}
+		// the address can try again once its earliest failure leaves the window
+		if count >= s.cfg.LoginIPThreshold && earliest != nil {
+			return &LoginThrottledError{Err: ErrLoginThrottled, RetryAfter: earliest.Sub(windowStart)}
+		}
+	}
+
+	attempt, err := s.securityRepo.GetLoginAttempt(ctx, key)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if attempt.LockedUntil != nil {
+		if attempt.LockedUntil.After(now) {
+			return &LoginThrottledError{Err: ErrAccountLocked, RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+		// the next failure starts the count over
+		return nil
+	}
+	if attempt.LastFailedAt.Before(windowStart) {
+		return nil
+	}
+	if wait := attempt.LastFailedAt.Add(s.loginDelay(attempt.FailedAttempts)).Sub(now); wait > 0 {
+		return &LoginThrottledError{Err: ErrLoginThrottled, RetryAfter: wait}
+	}
+	return nil
+}
+
+// loginDelay returns how long to wait after the given number of consecutive failed logins
+func (s *AuthService) loginDelay(failures int) time.Duration {
+	if failures < s.cfg.LoginFreeAttempts {
+		return 0
+	}
+	delay := s.cfg.LoginDelay
+	for i := s.cfg.LoginFreeAttempts; i < failures && delay < s.cfg.LoginMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, s.cfg.LoginMaxDelay)
+}
+
+// recordFailedLogin counts a failed login to the email and locks it once the threshold is reached. The user is nil for unknown emails
+func (s *AuthService) recordFailedLogin(ctx context.Context, key string, user *model.User, now time.Time) error {
+	var userID *uuid.UUID
+	if user != nil {
+		userID = &user.ID
+	}
+	s.recordSecurityEvent(ctx, userID, model.LoginFailed)
+
+	attempt, err := s.securityRepo.RecordFailedLogin(ctx, key, now, now.Add(-s.cfg.LoginAttemptWindow))
+	if err != nil {
+		return err
+	}
+	if attempt.FailedAttempts < s.cfg.LoginLockoutThreshold || attempt.LockedUntil != nil {
+		return nil
+	}
+
+	// unknown emails are locked the same way but have no owner to unlock them
+	lockedUntil := now.Add(s.cfg.LoginLockoutDuration)
+	if user == nil {
+		_, err := s.securityRepo.Lock(ctx, key, lockedUntil, nil)
+		return err
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+	tokenHash := hashToken(token)
+	locked, err := s.securityRepo.Lock(ctx, key, lockedUntil, &tokenHash)
+	if err != nil || !locked {
+		return err
+	}
+	s.recordSecurityEvent(ctx, userID, model.AccountLocked)
+
+	link := fmt.Sprintf("%s/unlock?token=%s", strings.TrimRight(s.cfg.AppURL, "/"), url.QueryEscape(token))
+	s.sendEmail(ctx, user.Email, "Your Chow account has been locked",
+		fmt.Sprintf("Hi %s,\n\nYour account was locked after %d failed login attempts. It unlocks automatically at %s.\nIf these attempts were yours, you can unlock it now: %s\nIf they were not, someone may be trying to guess your password and you should choose a stronger one once you are back in.\n",
+			user.Username, attempt.FailedAttempts, lockedUntil.UTC().Format(time.RFC1123), link))
+	return nil
+}
+
+// recordSecurityEvent logs a security event with the origin of the request. Failures are only logged so that they do not block logins
+func (s *AuthService) recordSecurityEvent(ctx context.Context, userID *uuid.UUID, eventType model.SecurityEventType) {
+	info := RequestInfoFromContext(ctx)
+	event := &model.SecurityEvent{
+		UserID:    userID,
+		Type:      eventType,
+		IPAddress: optionalString(info.IPAddress),
+		UserAgent: optionalString(truncate(info.UserAgent, maxUserAgentLength)),
+		DeviceID:  optionalString(truncate(info.DeviceID, maxDeviceIDLength)),
+	}
+	if _, err := s.securityRepo.CreateEvent(ctx, event); err != nil {
+		log.Printf("failed to record %s security event: %v", eventType, err)
+	}
+}
+
+// sendEmail sends an account email. Failures are only logged as the request succeeded regardless
+func (s *AuthService) sendEmail(ctx context.Context, to, subject, body string) {
+	if err := s.mailer.Send(ctx, to, subject, body); err != nil {
+		log.Printf("failed to send %q email: %v", subject, err)
+	}
+}
+
+// optionalString returns nil for an empty string so that it is stored as NULL
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// truncate shortens a string to at most n bytes without splitting a character
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
+
+// loginKey normalises an email so that failed logins are counted regardless of its case
+func loginKey(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// generateToken returns a random single use token such as an unlock token or the state of a sign in with a provider
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken returns the hash single use tokens are stored as so that a leaked database cannot use them
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// errors
+var (
+	ErrNoSigningKey         = errors.New("no signing key is loaded")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+)
+
+const (
+	rsaKeyBits = 2048
+	// unknownKeyRefreshInterval limits how often tokens with an unknown kid reload the keys so that forged tokens cannot flood the database
+	unknownKeyRefreshInterval = 10 * time.Second
+)
+
+// loadedKey is a signing key decoded for use
+type loadedKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// SigningKeyService manages the keys tokens are signed with. Keys are kept in the database so that every instance signs with the same key and verifies the tokens of the others
+type SigningKeyService struct {
+	cfg            *config.Config
+	signingKeyRepo *repository.SigningKeyRepository
+
+	mu          sync.RWMutex
+	signing     *loadedKey
+	keys        map[string]*loadedKey
+	jwks        model.JWKS
+	refreshedAt time.Time
+}
+
+func NewSigningKeyService(cfg *config.Config, signingKeyRepo *repository.SigningKeyRepository) *SigningKeyService {
+	return &SigningKeyService{
+		cfg:            cfg,
+		signingKeyRepo: signingKeyRepo,
+		keys:           make(map[string]*loadedKey),
+		jwks:           model.JWKS{Keys: []model.JWK{}},
+	}
+}
+
+// Init loads the signing keys, creating one with the configured algorithm when there is no active key
+func (s *SigningKeyService) Init(ctx context.Context) error {
+	if err := s.Refresh(ctx); err != nil {
+		return err
+	}
+	if _, err := s.signingKey(); err == nil {
+		return nil
+	}
+
+	key, err := s.generateKey(s.cfg.JWTAlgorithm)
+	if err != nil {
+		return err
+	}
+	// another instance may have created one in the meantime
+	created, err := s.signingKeyRepo.CreateIfNoneActive(ctx, key)
+	if err != nil {
+		return err
+	}
+	if created {
+		log.Printf("created %s signing key %s", key.Algorithm, key.KID)
+	}
+	return s.Refresh(ctx)
+}
+
+// Refresh reloads the signing keys so that keys rotated by another instance or chowctl are picked up
+func (s *SigningKeyService) Refresh(ctx context.Context) error {
+	keys, err := s.signingKeyRepo.GetUsable(ctx, time.Now().Add(-s.retention()))
+	if err != nil {
+		return err
+	}
+
+	var signing *loadedKey
+	loaded := make(map[string]*loadedKey, len(keys))
+	jwks := model.JWKS{Keys: []model.JWK{}}
+	for _, key := range keys {
+		decoded, err := s.decodeKey(&key)
+		if err != nil {
+			return fmt.Errorf("failed to decode signing key %s: %w", key.KID, err)
+		}
+		jwk, err := toJWK(decoded)
+		if err != nil {
+			return fmt.Errorf("failed to decode signing key %s: %w", key.KID, err)
+		}
+		loaded[decoded.kid] = decoded
+		jwks.Keys = append(jwks.Keys, jwk)
+
+		// keys are ordered newest first so the first active key signs
+		if signing == nil && key.RetiredAt == nil {
+			signing = decoded
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.signing = signing
+	s.keys = loaded
+	s.jwks = jwks
+	s.refreshedAt = time.Now()
+	return nil
+}
+
+// Rotate retires the active keys and creates a key with the algorithm to sign new tokens. Retired keys keep verifying the tokens they signed until those expire and are then removed
+func (s *SigningKeyService) Rotate(ctx context.Context, algorithm string) (*model.SigningKey, error) {
+	key, err := s.generateKey(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.signingKeyRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := s.signingKeyRepo.RetireActive(ctx, tx); err != nil {
+		return nil, err
+	}
+	created, err := s.signingKeyRepo.Create(ctx, tx, key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.signingKeyRepo.DeleteRetiredBefore(ctx, tx, time.Now().Add(-s.retention())); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// JWKS returns the public keys that verify the tokens in use
+func (s *SigningKeyService) JWKS() model.JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jwks
+}
+
+// signingKey returns the key new tokens are signed with
+func (s *SigningKeyService) signingKey() (*loadedKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.signing == nil {
+		return nil, ErrNoSigningKey
+	}
+	return s.signing, nil
+}
+
+// verificationKey returns the key with the kid. Unknown kids reload the keys in case they were rotated by another instance since the last refresh
+func (s *SigningKeyService) verificationKey(ctx context.Context, kid string) (*loadedKey, error) {
+	if kid == "" {
+		return nil, ErrInvalidToken
+	}
+
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	if ok {
+		s.mu.Unlock()
+		return key, nil
+	}
+	if time.Since(s.refreshedAt) < unknownKeyRefreshInterval {
+		s.mu.Unlock()
+		return nil, ErrInvalidToken
+	}
+	// claim the refresh so that concurrent requests with unknown kids do not all reload
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+
+	if err := s.Refresh(ctx); err != nil {
+		log.Printf("failed to refresh signing keys: %v", err)
+		return nil, ErrInvalidToken
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrInvalidToken
+}
+
+// retention is how long retired keys are kept. Tokens they signed stay valid until they expire and instances that have not refreshed yet may keep signing with them for up to the refresh interval
+func (s *SigningKeyService) retention() time.Duration {
+	return max(s.cfg.JWTExpiryMinutes, s.cfg.PreAuthTokenExpiry) + s.cfg.SigningKeyRefreshInterval
+}
+
+// generateKey creates a key pair with the algorithm. The private key is encrypted for storage and the kid is derived from the public key
+func (s *SigningKeyService) generateKey(algorithm string) (*model.SigningKey, error) {
+	var private crypto.Signer
+	switch algorithm {
+	case config.EdDSAAlgorithm:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	case config.RS256Algorithm:
+		key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, err
+		}
+		private = key
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encrypt(s.cfg.EncryptionKey, privateDER)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(publicDER)
+	return &model.SigningKey{
+		KID:        base64.RawURLEncoding.EncodeToString(sum[:16]),
+		Algorithm:  algorithm,
+		PrivateKey: encrypted,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+	}, nil
+}
+
+// decodeKey decrypts the private key of a stored key
+func (s *SigningKeyService) decodeKey(key *model.SigningKey) (*loadedKey, error) {
+	der, err := decrypt(s.cfg.EncryptionKey, key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+
+	// the algorithm must match the type of the key so that a token cannot pick another way to verify it
+	var method jwt.SigningMethod
+	switch parsed.(type) {
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	}
+	if method == nil || method.Alg() != key.Algorithm {
+		return nil, ErrUnsupportedAlgorithm
+	}
+
+	private := parsed.(crypto.Signer)
+	return &loadedKey{kid: key.KID, method: method, private: private, public: private.Public()}, nil
+}
+
+// toJWK returns the public part of a key as a JSON Web Key
+func toJWK(key *loadedKey) (model.JWK, error) {
+	jwk := model.JWK{Use: "sig", Alg: key.method.Alg(), Kid: key.kid}
+	switch public := key.public.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	default:
+		return jwk, ErrUnsupportedAlgorithm
+	}
+	return jwk, nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// streamChannel is the postgres notification channel updates are fanned out on
+	streamChannel = "chow_stream"
+	// streamBufferSize is the number of updates buffered per client. Updates are dropped for clients that fall behind
+	streamBufferSize = 32
+	// maxStreamListenBackoff caps the delay before reconnecting a failed listener
+	maxStreamListenBackoff = 30 * time.Second
+	// earthRadius is the mean radius of the earth in meters
+	earthRadius = 6371000
+)
+
+// StreamSubscription receives the updates matching its filter until it is unsubscribed
+type StreamSubscription struct {
+	filter   model.StreamFilter
+	messages chan *model.StreamMessage
+}
+
+// Messages returns the channel updates are delivered on. It is closed when the subscription ends
+func (s *StreamSubscription) Messages() <-chan *model.StreamMessage {
+	return s.messages
+}
+
+// matches reports whether an update is about a subscribed joint or located in the subscribed area
+func (s *StreamSubscription) matches(message *model.StreamMessage) bool {
+	if slices.Contains(s.filter.JointIDs, message.JointID) {
+		return true
+	}
+	area := s.filter.Area
+	if area == nil {
+		return false
+	}
+	return distance(area.Center, model.Coordinate{Latitude: message.Latitude, Longitude: message.Longitude}) <= area.Radius
+}
+
+// StreamService pushes real-time joint updates to connected clients. Updates are published through postgres so that clients connected to any instance receive them
+type StreamService struct {
+	cfg        *config.Config
+	streamRepo *repository.StreamRepository
+	jointRepo  *repository.JointRepository
+
+	mu            sync.Mutex
+	subscriptions map[*StreamSubscription]struct{}
+	cancel        context.CancelFunc
+	done          chan struct{}
+}
+
+func NewStreamService(cfg *config.Config, streamRepo *repository.StreamRepository, jointRepo *repository.JointRepository) *StreamService {
+	return &StreamService{
+		cfg:           cfg,
+		streamRepo:    streamRepo,
+		jointRepo:     jointRepo,
+		subscriptions: make(map[*StreamSubscription]struct{}),
+	}
+}
+
+// RegisterSubscribers publishes real-time updates for the domain events clients are interested in
+func (s *StreamService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("stream", s.handleEvent,
+		model.VoteCastEvent,
+		model.VoteRetractedEvent,
+		model.VotesVoidedEvent,
+		model.JointApprovedEvent,
+		model.JointVisibilityChangedEvent,
+		model.JointDeletedEvent,
+		model.JointRestoredEvent,
+	)
+}
+
+// Start listens for updates published by every instance in the background until Shutdown is called
+func (s *StreamService) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+	go s.listen(ctx)
+}
+
+// Shutdown stops listening and ends every subscription so that connected clients are disconnected
+func (s *StreamService) Shutdown() {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+		s.mu.Unlock()
+		<-s.done
+		s.mu.Lock()
+		s.cancel = nil
+	}
+	for sub := range s.subscriptions {
+		delete(s.subscriptions, sub)
+		close(sub.messages)
+	}
+	s.mu.Unlock()
+}
+
+// Subscribe starts delivering the updates matching the filter. The subscription must be ended with Unsubscribe
+func (s *StreamService) Subscribe(filter model.StreamFilter) (*StreamSubscription, error) {
+	if filter.Area != nil && filter.Area.Radius > s.cfg.MaxNearbyRadius {
+		return nil, ErrMaxSearchRadiusExceeded
+	}
+	sub := &StreamSubscription{filter: filter, messages: make(chan *model.StreamMessage, streamBufferSize)}
+
+	s.mu.Lock()
+	s.subscriptions[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub, nil
+}
+
+// Unsubscribe ends a subscription and closes its channel
+func (s *StreamService) Unsubscribe(sub *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscriptions[sub]; ok {
+		delete(s.subscriptions, sub)
+		close(sub.messages)
+	}
+}
+
+// listen receives updates from postgres, reconnecting with backoff when the connection fails
+func (s *StreamService) listen(ctx context.Context) {
+	defer close(s.done)
+
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := s.streamRepo.Listen(ctx, streamChannel, s.broadcast)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// reset the backoff once a connection has been healthy for a while
+		if time.Since(started) > maxStreamListenBackoff {
+			backoff = time.Second
+		}
+		log.Printf("stream listener failed, reconnecting in %s: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxStreamListenBackoff)
+	}
+}
+
+// broadcast delivers an update received from postgres to the matching local subscriptions
+func (s *StreamService) broadcast(payload string) {
+	var message model.StreamMessage
+	if err := json.Unmarshal([]byte(payload), &message); err != nil {
+		log.Printf("invalid stream message: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscriptions {
+		if !sub.matches(&message) {
+			continue
+		}
+		// never block the listener on a slow client
+		select {
+		case sub.messages <- &message:
+		default:
+		}
+	}
+}
+
+// handleEvent converts a domain event into a real-time update and publishes it to every instance. Only updates about publicly listed joints are published
+func (s *StreamService) handleEvent(ctx context.Context, event *model.Event) error {
+	var joint model.Joint
+	var data any
+
+	switch event.Type {
+	case model.VoteCastEvent, model.VoteRetractedEvent, model.VotesVoidedEvent:
+		// every vote payload carries the vote counts after the change
+		var payload model.VoteChangedData
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		current, err := s.jointRepo.GetByID(ctx, event.AggregateID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		if !current.IsApproved || current.Visibility != model.VisibleJoint {
+			return nil
+		}
+		joint = *current
+		data = payload
+
+	case model.JointApprovedEvent:
+		if err := json.Unmarshal(event.Payload, &joint); err != nil {
+			return err
+		}
+		if joint.Visibility != model.VisibleJoint {
+			return nil
+		}
+		data = joint
+
+	case model.JointVisibilityChangedEvent, model.JointRestoredEvent:
+		if err := json.Unmarshal(event.Payload, &joint); err != nil {
+			return err
+		}
+		if !joint.IsApproved {
+			return nil
+		}
+		data = model.JointStatusChangedData{Status: model.JointStatus(joint.Visibility)}
+
+	case model.JointDeletedEvent:
+		if err := json.Unmarshal(event.Payload, &joint); err != nil {
+			return err
+		}
+		if !joint.IsApproved {
+			return nil
+		}
+		data = model.JointStatusChangedData{Status: model.DeletedJointStatus}
+
+	default:
+		return nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	message, err := json.Marshal(model.StreamMessage{
+		ID:        event.ID,
+		Type:      streamEventType(event.Type),
+		JointID:   event.AggregateID,
+		Latitude:  joint.Latitude,
+		Longitude: joint.Longitude,
+		Data:      encoded,
+		CreatedAt: event.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return s.streamRepo.Notify(ctx, streamChannel, string(message))
+}
+
+// streamEventType maps a domain event to the update type sent to clients
+func streamEventType(eventType model.EventType) model.StreamEventType {
+	switch eventType {
+	case model.VoteCastEvent, model.VoteRetractedEvent, model.VotesVoidedEvent:
+		return model.VoteChangedStreamEvent
+	case model.JointApprovedEvent:
+		return model.JointApprovedStreamEvent
+	default:
+		return model.JointStatusChangedStreamEvent
+	}
+}
+
+// distance returns the great-circle distance between two points in meters
+func distance(a, b model.Coordinate) float64 {
+	lat1, lat2 := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Sqrt(h))
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTranslationNotFound      = errors.New("translation not found")
+	ErrDefaultLocaleTranslation = errors.New("joint content in its default locale cannot be translated")
+)
+
+type TranslationService struct {
+	cfg             *config.Config
+	translationRepo *repository.TranslationRepository
+	jointRepo       *repository.JointRepository
+	auditRepo       *repository.AuditRepository
+}
+
+func NewTranslationService(cfg *config.Config, translationRepo *repository.TranslationRepository, jointRepo *repository.JointRepository, auditRepo *repository.AuditRepository) *TranslationService {
+	return &TranslationService{
+		cfg:             cfg,
+		translationRepo: translationRepo,
+		jointRepo:       jointRepo,
+		auditRepo:       auditRepo,
+	}
+}
+
+// LocalizeJoints replaces the name and description of the joints with the translation in the most preferred of the given locales. Joints keep their own content when it is in a more preferred locale or when none of the locales is available
+func (s *TranslationService) LocalizeJoints(ctx context.Context, locales []string, joints ...*model.Joint) error {
+	if len(joints) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(joints))
+	for _, joint := range joints {
+		ids = append(ids, joint.ID)
+	}
+	localizations, err := s.translationRepo.GetLocalizations(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, joint := range joints {
+		localization, ok := localizations[joint.ID]
+		if !ok {
+			continue
+		}
+		joint.DefaultLocale = localization.DefaultLocale
+		joint.Locale = localization.DefaultLocale
+		joint.Locales = []string{localization.DefaultLocale}
+		for _, translation := range localization.Translations {
+			joint.Locales = append(joint.Locales, translation.Locale)
+		}
+
+		for _, locale := range locales {
+			if locale == localization.DefaultLocale {
+				break
+			}
+			if translation := findTranslation(localization.Translations, locale); translation != nil {
+				joint.Locale = translation.Locale
+				joint.Name = translation.Name
+				joint.Description = translation.Description
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// GetJointTranslations returns the translations of a joint
+func (s *TranslationService) GetJointTranslations(ctx context.Context, jointID uuid.UUID) ([]model.JointTranslation, error) {
+	if _, err := s.getJoint(ctx, jointID); err != nil {
+		return nil, err
+	}
+	return s.translationRepo.GetByJointID(ctx, jointID)
+}
+
+// SaveJointTranslation creates or replaces the translation of a joint in a locale. Content in the joint's default locale is edited on the joint itself
+func (s *TranslationService) SaveJointTranslation(ctx context.Context, jointID uuid.UUID, locale string, editorID uuid.UUID, req model.SaveJointTranslationReq) (*model.JointTranslation, error) {
+	joint, err := s.getJoint(ctx, jointID)
+	if err != nil {
+		return nil, err
+	}
+	if locale == joint.DefaultLocale {
+		return nil, ErrDefaultLocaleTranslation
+	}
+
+	tx, err := s.translationRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.translationRepo.GetByLocale(ctx, tx, jointID, locale)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	data := &model.JointTranslation{JointID: jointID, Locale: locale, Name: req.Name, Description: req.Description, UpdatedBy: &editorID}
+	translation, err := s.translationRepo.Upsert(ctx, tx, data)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	// existing is nil when the translation was added
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointTranslationSavedAction, model.JointTarget, jointID, existing, translation); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return translation, nil
+}
+
+// DeleteJointTranslation removes the translation of a joint in a locale
+func (s *TranslationService) DeleteJointTranslation(ctx context.Context, jointID uuid.UUID, locale string) error {
+	tx, err := s.translationRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	existing, err := s.translationRepo.GetByLocale(ctx, tx, jointID, locale)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTranslationNotFound
+		}
+		return err
+	}
+	if err := s.translationRepo.Delete(ctx, tx, jointID, locale); err != nil {
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.JointTranslationDeletedAction, model.JointTarget, jointID, existing, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getJoint returns a joint that has not been deleted along with its default locale
+func (s *TranslationService) getJoint(ctx context.Context, jointID uuid.UUID) (*model.Joint, error) {
+	joint, err := s.jointRepo.GetByID(ctx, jointID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrJointNotFound
+		}
+		return nil, err
+	}
+
+	localizations, err := s.translationRepo.GetLocalizations(ctx, []uuid.UUID{jointID})
+	if err != nil {
+		return nil, err
+	}
+	if localization, ok := localizations[jointID]; ok {
+		joint.DefaultLocale = localization.DefaultLocale
+	}
+	return joint, nil
+}
+
+func findTranslation(translations []model.JointTranslation, locale string) *model.JointTranslation {
+	for i := range translations {
+		if translations[i].Locale == locale {
+			return &translations[i]
+		}
+	}
+	return nil
+}
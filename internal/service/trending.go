@@ -0,0 +1,112 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TrendingService struct {
+	cfg          *config.Config
+	activityRepo *repository.ActivityRepository
+}
+
+func NewTrendingService(cfg *config.Config, activityRepo *repository.ActivityRepository) *TrendingService {
+	return &TrendingService{
+		cfg:          cfg,
+		activityRepo: activityRepo,
+	}
+}
+
+// RegisterSubscribers aggregates votes and check-ins into the hourly activity of joints
+func (s *TrendingService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("trending", s.handleEvent, model.VoteCastEvent, model.CheckinCreatedEvent)
+}
+
+// RecordView counts a view of a joint towards its activity
+func (s *TrendingService) RecordView(ctx context.Context, jointID uuid.UUID) error {
+	return s.activityRepo.AddView(ctx, jointID)
+}
+
+// GetTrendingJoints ranks the joints within the radius by how far their activity within the window is above their usual activity
+func (s *TrendingService) GetTrendingJoints(ctx context.Context, coord model.Coordinate, radius float64, window model.TrendingWindow, query model.PaginationQuery) ([]*model.TrendingJoint, *model.Pagination, error) {
+	if radius > s.cfg.MaxNearbyRadius {
+		return nil, nil, ErrMaxSearchRadiusExceeded
+	}
+
+	duration := window.Duration()
+	since := time.Now().Add(-duration)
+	scope := fmt.Sprintf("trending-joints:%s:%g,%g,%g", window, coord.Latitude, coord.Longitude, radius)
+	return readPage(s.cfg, query, scope, func(page model.Page) (*model.Paged[*model.TrendingJoint], error) {
+		return s.activityRepo.GetTrending(ctx, coord, radius, since, duration, s.cfg.TrendingBaselineWindows, page)
+	}, func(joint *model.TrendingJoint) []any {
+		return []any{joint.Velocity, joint.ID}
+	})
+}
+
+// PruneActivity removes activity that is too old to be part of any window or baseline
+func (s *TrendingService) PruneActivity(ctx context.Context) error {
+	_, err := s.activityRepo.DeleteBefore(ctx, time.Now().Add(-s.cfg.JointActivityRetention))
+	return err
+}
+
+// handleEvent adds a vote or check-in to the activity of its joint in the hour it happened. Events are recorded along with the activity so that redelivered events are only counted once
+func (s *TrendingService) handleEvent(ctx context.Context, event *model.Event) error {
+	var jointID uuid.UUID
+	var activity model.JointActivity
+
+	switch event.Type {
+	case model.VoteCastEvent:
+		var payload model.VoteCastPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		jointID = payload.Vote.JointID
+		if payload.Vote.Direction == model.UpVote {
+			activity.UpVotes = 1
+		} else {
+			activity.DownVotes = 1
+		}
+
+	case model.CheckinCreatedEvent:
+		var checkin model.Checkin
+		if err := json.Unmarshal(event.Payload, &checkin); err != nil {
+			return err
+		}
+		jointID = checkin.JointID
+		activity.Checkins = 1
+
+	default:
+		return nil
+	}
+
+	tx, err := s.activityRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	processed, err := s.activityRepo.MarkEventProcessed(ctx, tx, event.ID)
+	if err != nil {
+		return err
+	}
+	if !processed {
+		return nil
+	}
+	if err := s.activityRepo.AddActivity(ctx, tx, jointID, event.CreatedAt, activity); err != nil {
+		// the joint has since been purged
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorNotSetUp       = errors.New("two-factor authentication has not been set up")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+)
+
+// TOTP parameters from RFC 6238 supported by every authenticator app
+const (
+	totpIssuer = "Chow"
+	totpDigits = 6
+	totpPeriod = 30
+	// codes of the steps before and after the current one are accepted to allow for clock drift
+	totpSkew = 1
+	// number of recovery codes issued at a time and their length
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// RequiresTwoFactor reports whether the privileges of the role only apply to sessions that completed two-factor authentication
+func (s *AuthService) RequiresTwoFactor(role model.UserRole) bool {
+	return slices.Contains(s.cfg.TwoFactorRequiredRoles, string(role))
+}
+
+// GetTwoFactorStatus returns whether a user has enabled two-factor authentication and how many recovery codes they have left
+func (s *AuthService) GetTwoFactorStatus(ctx context.Context, userID uuid.UUID) (*model.TwoFactorStatus, error) {
+	twoFactor, err := s.getEnabledTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			return &model.TwoFactorStatus{}, nil
+		}
+		return nil, err
+	}
+	count, err := s.twoFactorRepo.CountRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TwoFactorStatus{Enabled: true, EnabledAt: twoFactor.EnabledAt, RecoveryCodesLeft: count}, nil
+}
+
+// SetupTwoFactor generates a new TOTP secret for a user. It is not used until the user confirms it with a code from their authenticator app
+func (s *AuthService) SetupTwoFactor(ctx context.Context, userID uuid.UUID) (*model.TwoFactorSetupRes, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	encrypted, err := encrypt(s.cfg.TwoFactorKey, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.SaveSecret(ctx, userID, encrypted); err != nil {
+		if errors.Is(err, repository.ErrAlreadyExist) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	encoded := base32NoPadding.EncodeToString(secret)
+	params := url.Values{}
+	params.Set("secret", encoded)
+	params.Set("issuer", totpIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	uri := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + totpIssuer + ":" + user.Email, RawQuery: params.Encode()}
+
+	return &model.TwoFactorSetupRes{Secret: encoded, ProvisioningURI: uri.String()}, nil
+}
+
+// EnableTwoFactor turns on two-factor authentication once the user proves their authenticator app is set up with a code. The recovery codes are returned once
+func (s *AuthService) EnableTwoFactor(ctx context.Context, userID uuid.UUID, code string) (*model.RecoveryCodesRes, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorNotSetUp
+		}
+		return nil, err
+	}
+	if twoFactor.EnabledAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok, err := s.verifyTOTP(twoFactor, code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	tx, err := s.twoFactorRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.twoFactorRepo.Enable(ctx, tx, userID, step); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, err
+	}
+	codes, err := s.replaceRecoveryCodes(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.recordSecurityEvent(ctx, &userID, model.TwoFactorEnabled)
+	return codes, nil
+}
+
+// DisableTwoFactor turns off two-factor authentication after the user signs in again with their password and a code
+func (s *AuthService) DisableTwoFactor(ctx context.Context, userID uuid.UUID, req model.ReauthenticateReq) error {
+	if err := s.reauthenticate(ctx, userID, req); err != nil {
+		return err
+	}
+
+	tx, err := s.twoFactorRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.twoFactorRepo.Delete(ctx, tx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTwoFactorNotEnabled
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.recordSecurityEvent(ctx, &userID, model.TwoFactorDisabled)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of a user after they sign in again with their password and a code. The new codes are returned once
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, req model.ReauthenticateReq) (*model.RecoveryCodesRes, error) {
+	if err := s.reauthenticate(ctx, userID, req); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.twoFactorRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	codes, err := s.replaceRecoveryCodes(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	s.recordSecurityEvent(ctx, &userID, model.RecoveryCodesRegenerated)
+	return codes, nil
+}
+
+// VerifyTwoFactor completes the login of a user with two-factor authentication by exchanging the pre-auth token and a code for an access token.
+// Wrong codes count as failed logins to the user's email
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, req model.VerifyTwoFactorReq) (*model.LoginUserRes, error) {
+	claims, err := s.parseToken(ctx, req.PreAuthToken, preAuthTokenType)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := claimUserID(claims)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	key := loginKey(user.Email)
+	if err := s.checkLoginAllowed(ctx, key, now); err != nil {
+		return nil, err
+	}
+	if err := s.verifySecondFactor(ctx, user, req.Code, req.RecoveryCode, now); err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, user, true)
+}
+
+// reauthenticate confirms the identity of a signed in user with two-factor authentication before a sensitive change with their password, if they have one, and a code. Failures count as failed logins to the user's email
+func (s *AuthService) reauthenticate(ctx context.Context, userID uuid.UUID, req model.ReauthenticateReq) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.getEnabledTwoFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	key := loginKey(user.Email)
+	if err := s.checkLoginAllowed(ctx, key, now); err != nil {
+		return err
+	}
+	// users without a password signed in with their identity provider and only confirm the code
+	if user.HasPassword {
+		if err := s.verifyPassword(req.Password, user.Password); err != nil {
+			if err := s.recordFailedLogin(ctx, key, user, now); err != nil {
+				return err
+			}
+			return ErrInvalidCredentials
+		}
+	}
+	return s.verifySecondFactor(ctx, user, req.Code, req.RecoveryCode, now)
+}
+
+// confirmIdentity confirms the identity of a signed in user before an irreversible change. Users with two-factor authentication reauthenticate with a code,
+// other users with their password. Users with neither signed in with their identity provider and are confirmed by their session
+func (s *AuthService) confirmIdentity(ctx context.Context, userID uuid.UUID, password, code, recoveryCode string) error {
+	if _, err := s.getEnabledTwoFactor(ctx, userID); err == nil {
+		return s.reauthenticate(ctx, userID, model.ReauthenticateReq{Password: password, Code: code, RecoveryCode: recoveryCode})
+	} else if !errors.Is(err, ErrTwoFactorNotEnabled) {
+		return err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.HasPassword {
+		return nil
+	}
+
+	now := time.Now()
+	key := loginKey(user.Email)
+	if err := s.checkLoginAllowed(ctx, key, now); err != nil {
+		return err
+	}
+	if err := s.verifyPassword(password, user.Password); err != nil {
+		if err := s.recordFailedLogin(ctx, key, user, now); err != nil {
+			return err
+		}
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// verifySecondFactor checks a TOTP code or recovery code of a user. Each code is only accepted once
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *model.User, code, recoveryCode string, now time.Time) error {
+	twoFactor, err := s.getEnabledTwoFactor(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var accepted bool
+	if code != "" {
+		step, ok, err := s.verifyTOTP(twoFactor, code, now)
+		if err != nil {
+			return err
+		}
+		if ok {
+			// a code seen by someone looking over the user's shoulder cannot be replayed
+			if err := s.twoFactorRepo.UseStep(ctx, user.ID, step); err == nil {
+				accepted = true
+			} else if !errors.Is(err, repository.ErrNotFound) {
+				return err
+			}
+		}
+	} else {
+		if err := s.twoFactorRepo.UseRecoveryCode(ctx, user.ID, s.hashRecoveryCode(user.ID, recoveryCode)); err == nil {
+			accepted = true
+			s.recordSecurityEvent(ctx, &user.ID, model.RecoveryCodeUsed)
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+	}
+
+	if !accepted {
+		if err := s.recordFailedLogin(ctx, loginKey(user.Email), user, now); err != nil {
+			return err
+		}
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// getEnabledTwoFactor returns the two-factor authentication of a user or ErrTwoFactorNotEnabled
+func (s *AuthService) getEnabledTwoFactor(ctx context.Context, userID uuid.UUID) (*model.TwoFactor, error) {
+	twoFactor, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+	if twoFactor.EnabledAt == nil {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return twoFactor, nil
+}
+
+// replaceRecoveryCodes issues a new set of recovery codes to a user, invalidating the previous ones
+func (s *AuthService) replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID uuid.UUID) (*model.RecoveryCodesRes, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, recoveryCodeLength*5/8)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		// lowercase base32 is easy to read back and type
+		code := strings.ToLower(base32NoPadding.EncodeToString(b))
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = s.hashRecoveryCode(userID, codes[i])
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, tx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return &model.RecoveryCodesRes{RecoveryCodes: codes}, nil
+}
+
+// verifyTOTP checks a code against the steps around the given time and returns the step it matched
+func (s *AuthService) verifyTOTP(twoFactor *model.TwoFactor, code string, now time.Time) (int64, bool, error) {
+	secret, err := decrypt(s.cfg.TwoFactorKey, twoFactor.Secret)
+	if err != nil {
+		return 0, false, err
+	}
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if hmac.Equal([]byte(totpCode(secret, step)), []byte(code)) {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// totpCode returns the HOTP value of RFC 4226 for the counter, which TOTP derives from the time step
+func totpCode(secret []byte, counter int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%uint32(math.Pow10(totpDigits)))
+}
+
+// hashRecoveryCode returns the hash recovery codes are stored as. Codes are compared regardless of case and separators.
+// The hash is keyed with the two-factor key and salted with the user ID, since the codes are too short to withstand guessing against a plain hash taken from a database dump
+func (s *AuthService) hashRecoveryCode(userID uuid.UUID, code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	mac := hmac.New(sha256.New, []byte(s.cfg.TwoFactorKey))
+	mac.Write([]byte(userID.String()))
+	mac.Write([]byte(":"))
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrVoteReviewNotFound = errors.New("vote review not found")
+	ErrVoteReviewResolved = errors.New("vote review has already been resolved")
+)
+
+// weights of the signals that make up the risk score of a vote. The score is capped at 1
+var voteRiskWeights = map[model.VoteRiskSignal]float64{
+	model.NewAccountSignal:   0.3,
+	model.NoCheckinSignal:    0.15,
+	model.SharedIPSignal:     0.2,
+	model.SharedDeviceSignal: 0.25,
+	model.VoteBurstSignal:    0.1,
+}
+
+// suspiciousRiskScore is the risk score from which votes cast during a burst are queued for review
+const suspiciousRiskScore = 0.5
+
+// voteReviewsGroup aggregates vote review notifications of moderators into a single unread notification
+const voteReviewsGroup = "vote_reviews"
+
+type VoteIntegrityService struct {
+	cfg        *config.Config
+	reviewRepo *repository.VoteReviewRepository
+	jointRepo  *repository.JointRepository
+	auditRepo  *repository.AuditRepository
+	outboxRepo *repository.OutboxRepository
+	notifier   Notifier
+}
+
+func NewVoteIntegrityService(cfg *config.Config, reviewRepo *repository.VoteReviewRepository, jointRepo *repository.JointRepository, auditRepo *repository.AuditRepository, outboxRepo *repository.OutboxRepository, notifier Notifier) *VoteIntegrityService {
+	return &VoteIntegrityService{
+		cfg:        cfg,
+		reviewRepo: reviewRepo,
+		jointRepo:  jointRepo,
+		auditRepo:  auditRepo,
+		outboxRepo: outboxRepo,
+		notifier:   notifier,
+	}
+}
+
+// RegisterSubscribers assesses every vote cast and queues suspicious clusters for review
+func (s *VoteIntegrityService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("vote-integrity", s.handleEvent, model.VoteCastEvent)
+}
+
+// GetVoteReviews retrieves the vote reviews with the given status, most recently updated first
+func (s *VoteIntegrityService) GetVoteReviews(ctx context.Context, status model.VoteReviewStatus, query model.PaginationQuery) ([]*model.VoteReview, *model.Pagination, error) {
+	return readPage(s.cfg, query, "vote-reviews:"+string(status), func(page model.Page) (*model.Paged[*model.VoteReview], error) {
+		return s.reviewRepo.GetAll(ctx, status, page)
+	}, func(review *model.VoteReview) []any {
+		return []any{review.UpdatedAt, review.ID}
+	})
+}
+
+func (s *VoteIntegrityService) GetVoteReviewByID(ctx context.Context, id uuid.UUID) (*model.VoteReview, error) {
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVoteReviewNotFound
+		}
+		return nil, err
+	}
+	return review, err
+}
+
+// GetVoteReviewVotes retrieves the votes in a review, riskiest first
+func (s *VoteIntegrityService) GetVoteReviewVotes(ctx context.Context, id uuid.UUID, query model.PaginationQuery) ([]*model.ReviewedVote, *model.Pagination, error) {
+	if _, err := s.GetVoteReviewByID(ctx, id); err != nil {
+		return nil, nil, err
+	}
+	return readPage(s.cfg, query, "vote-review-votes:"+id.String(), func(page model.Page) (*model.Paged[*model.ReviewedVote], error) {
+		return s.reviewRepo.GetReviewVotes(ctx, id, page)
+	}, func(vote *model.ReviewedVote) []any {
+		// unscored votes are sorted as if their score was -1
+		risk := -1.0
+		if vote.RiskScore != nil {
+			risk = *vote.RiskScore
+		}
+		return []any{risk, vote.CreatedAt, vote.VoteID}
+	})
+}
+
+// VoidVotes voids the votes of an open review, or the given subset of them, and resolves it. Voided votes stop counting towards the joint vote counts and score
+func (s *VoteIntegrityService) VoidVotes(ctx context.Context, id uuid.UUID, voteIDs []uuid.UUID, moderatorID uuid.UUID) (*model.VoteReview, error) {
+	review, err := s.GetVoteReviewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != model.OpenVoteReview {
+		return nil, ErrVoteReviewResolved
+	}
+
+	tx, err := s.reviewRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	voided, err := s.reviewRepo.VoidReviewVotes(ctx, tx, id, voteIDs, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reviewRepo.Resolve(ctx, tx, id, model.VoidedVoteReview, moderatorID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVoteReviewResolved
+		}
+		return nil, err
+	}
+
+	// the vote counts are updated by the database as votes are voided so only the score is recomputed
+	joint, err := s.jointRepo.UpdateScore(ctx, tx, review.JointID, voteWeights(s.cfg))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	resolved := *review
+	resolved.Status = model.VoidedVoteReview
+	resolved.ResolvedBy = &moderatorID
+	after := map[string]any{"review": resolved, "voidedVoteIds": voided}
+	if err := recordAudit(ctx, tx, s.auditRepo, model.VotesVoidedAction, model.VoteReviewTarget, id, review, after); err != nil {
+		return nil, err
+	}
+	if joint != nil && len(voided) > 0 {
+		payload := model.VotesVoidedPayload{ReviewID: id, VoteIDs: voided, UpVotes: joint.UpVotes, DownVotes: joint.DownVotes}
+		if err := publishEvent(ctx, tx, s.outboxRepo, model.VotesVoidedEvent, joint.ID, payload); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetVoteReviewByID(ctx, id)
+}
+
+// DismissVoteReview resolves an open review without voiding any of its votes
+func (s *VoteIntegrityService) DismissVoteReview(ctx context.Context, id uuid.UUID, moderatorID uuid.UUID) (*model.VoteReview, error) {
+	review, err := s.GetVoteReviewByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.reviewRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.reviewRepo.Resolve(ctx, tx, id, model.DismissedVoteReview, moderatorID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrVoteReviewResolved
+		}
+		return nil, err
+	}
+
+	resolved := *review
+	resolved.Status = model.DismissedVoteReview
+	resolved.ResolvedBy = &moderatorID
+	if err := recordAudit(ctx, tx, s.auditRepo, model.VoteReviewDismissedAction, model.VoteReviewTarget, id, review, resolved); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetVoteReviewByID(ctx, id)
+}
+
+// handleEvent assesses a vote once it has been cast. Assessing a vote again only refreshes its risk score and review membership so redelivered events are harmless
+func (s *VoteIntegrityService) handleEvent(ctx context.Context, event *model.Event) error {
+	var payload model.VoteCastPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	clusterSince, burstSince := now.Add(-s.cfg.VoteClusterWindow), now.Add(-s.cfg.VoteBurstWindow)
+	signals, err := s.reviewRepo.GetVoteSignals(ctx, payload.Vote.ID, clusterSince, burstSince)
+	if err != nil {
+		// the vote has since been retracted or voided
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	score, flags := s.assessVote(signals, now)
+
+	tx, err := s.reviewRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.reviewRepo.UpdateVoteRisk(ctx, tx, signals.VoteID, score, flags); err != nil {
+		return err
+	}
+
+	// queue the clusters the vote belongs to for review
+	type cluster struct {
+		reason model.VoteReviewReason
+		key    string
+		since  time.Time
+	}
+	var clusters []cluster
+	if signals.IPAddress != nil && signals.SharedIPVoters+1 >= s.cfg.VoteClusterMinSize {
+		clusters = append(clusters, cluster{model.IPClusterReview, *signals.IPAddress, clusterSince})
+	}
+	if signals.Fingerprint != nil && signals.SharedDeviceVoters+1 >= s.cfg.VoteClusterMinSize {
+		clusters = append(clusters, cluster{model.DeviceClusterReview, *signals.Fingerprint, clusterSince})
+	}
+	if signals.BurstVotes >= s.cfg.VoteBurstThreshold && score >= suspiciousRiskScore {
+		clusters = append(clusters, cluster{model.VoteBurstReview, "", burstSince})
+	}
+
+	var opened []uuid.UUID
+	for _, c := range clusters {
+		reviewID, created, err := s.reviewRepo.OpenReview(ctx, tx, signals.JointID, c.reason, c.key, signals.Direction)
+		if err != nil {
+			return err
+		}
+		if err := s.reviewRepo.AddClusterVotes(ctx, tx, reviewID, signals.JointID, c.reason, c.key, signals.Direction, c.since, suspiciousRiskScore); err != nil {
+			return err
+		}
+		if created {
+			opened = append(opened, reviewID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, reviewID := range opened {
+		group := voteReviewsGroup
+		notification := &model.Notification{
+			Type:       model.VoteReviewNotification,
+			Title:      "Suspicious votes awaiting review",
+			Body:       fmt.Sprintf("A cluster of %s votes on a joint has been flagged for review", signals.Direction),
+			TargetType: targetType(model.VoteReviewTarget),
+			TargetID:   &reviewID,
+			GroupKey:   &group,
+			EventID:    &event.ID,
+		}
+		if err := s.notifier.NotifyModerators(ctx, notification); err != nil {
+			log.Println(err)
+		}
+	}
+	return nil
+}
+
+// assessVote scores a vote between 0 and 1 from the signals that suggest it is not genuine
+func (s *VoteIntegrityService) assessVote(signals *model.VoteSignals, now time.Time) (float64, []model.VoteRiskSignal) {
+	flags := make([]model.VoteRiskSignal, 0)
+	if now.Sub(signals.AccountCreated) < s.cfg.VoteNewAccountAge {
+		flags = append(flags, model.NewAccountSignal)
+	}
+	if !signals.Verified {
+		flags = append(flags, model.NoCheckinSignal)
+	}
+	if signals.SharedIPVoters > 0 {
+		flags = append(flags, model.SharedIPSignal)
+	}
+	if signals.SharedDeviceVoters > 0 {
+		flags = append(flags, model.SharedDeviceSignal)
+	}
+	if signals.BurstVotes >= s.cfg.VoteBurstThreshold {
+		flags = append(flags, model.VoteBurstSignal)
+	}
+
+	var score float64
+	for _, flag := range flags {
+		score += voteRiskWeights[flag]
+	}
+	return min(score, 1), flags
+}
+
+// voteOrigin returns the IP address and device fingerprint of the request a vote was cast from, if known.
+// The fingerprint is only derived from a device ID sent by the client, since other request details such as the user agent are shared by unrelated devices
+func voteOrigin(ctx context.Context) (*string, *string) {
+	info := RequestInfoFromContext(ctx)
+
+	var ip, fingerprint *string
+	if info.IPAddress != "" {
+		ip = &info.IPAddress
+	}
+
+	if info.DeviceID != "" {
+		sum := sha256.Sum256([]byte(info.DeviceID))
+		hash := hex.EncodeToString(sum[:])
+		fingerprint = &hash
+	}
+	return ip, fingerprint
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"bytes"
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// errors
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// webhook signing headers. The signature header holds the unix timestamp and the hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret
+const (
+	WebhookSignatureHeader = "X-Chow-Signature"
+	WebhookEventHeader     = "X-Chow-Event"
+	WebhookDeliveryHeader  = "X-Chow-Delivery"
+)
+
+const (
+	// webhookBatchSize is the maximum number of deliveries sent at once
+	webhookBatchSize = 20
+	// webhookBaseBackoff is the delay before the first retry of a failed delivery, doubled on every attempt
+	webhookBaseBackoff = 30 * time.Second
+	// maxWebhookBackoff caps the delay between attempts of a failing delivery
+	maxWebhookBackoff = 6 * time.Hour
+	// maxWebhookResponseBody is the number of bytes of the response kept in the delivery log
+	maxWebhookResponseBody = 2000
+)
+
+type WebhookService struct {
+	cfg         *config.Config
+	webhookRepo *repository.WebhookRepository
+	auditRepo   *repository.AuditRepository
+	client      *http.Client
+}
+
+func NewWebhookService(cfg *config.Config, webhookRepo *repository.WebhookRepository, auditRepo *repository.AuditRepository) *WebhookService {
+	return &WebhookService{
+		cfg:         cfg,
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		client:      &http.Client{Timeout: cfg.WebhookTimeout},
+	}
+}
+
+// RegisterSubscribers queues deliveries for subscribed webhooks whenever a public event is dispatched
+func (s *WebhookService) RegisterSubscribers(dispatcher *EventDispatcher) {
+	dispatcher.Subscribe("webhooks", s.handleEvent, model.WebhookEventTypes...)
+}
+
+// CreateWebhook adds a webhook, generating a secret when none is provided. The secret is returned only once
+func (s *WebhookService) CreateWebhook(ctx context.Context, data *model.Webhook) (*model.CreatedWebhook, error) {
+	if data.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, err
+		}
+		data.Secret = secret
+	}
+
+	tx, err := s.webhookRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	webhook, err := s.webhookRepo.Create(ctx, tx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.WebhookCreatedAction, model.WebhookTarget, webhook.ID, nil, webhook); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &model.CreatedWebhook{Webhook: *webhook, Secret: webhook.Secret}, nil
+}
+
+func (s *WebhookService) GetWebhooks(ctx context.Context, query model.PaginationQuery) ([]*model.Webhook, *model.Pagination, error) {
+	return readPage(s.cfg, query, "webhooks", func(page model.Page) (*model.Paged[*model.Webhook], error) {
+		return s.webhookRepo.GetAll(ctx, page)
+	}, func(webhook *model.Webhook) []any {
+		return []any{webhook.CreatedAt, webhook.ID}
+	})
+}
+
+func (s *WebhookService) GetWebhookByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateWebhookByID changes the endpoint, secret, subscribed events or active state of a webhook. Re-activating a disabled webhook resumes its pending deliveries
+func (s *WebhookService) UpdateWebhookByID(ctx context.Context, id uuid.UUID, data *model.Webhook) (*model.Webhook, error) {
+	existing, err := s.GetWebhookByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.webhookRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	webhook, err := s.webhookRepo.UpdateByID(ctx, tx, id, data)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.WebhookUpdatedAction, model.WebhookTarget, id, existing, webhook); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// DeleteWebhookByID removes a webhook and its delivery log
+func (s *WebhookService) DeleteWebhookByID(ctx context.Context, id uuid.UUID) error {
+	existing, err := s.GetWebhookByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.webhookRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.webhookRepo.DeleteByID(ctx, tx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrWebhookNotFound
+		}
+		return err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.WebhookDeletedAction, model.WebhookTarget, id, existing, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetWebhookDeliveries retrieves the delivery log of a webhook, newest first
+func (s *WebhookService) GetWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, query model.PaginationQuery) ([]*model.WebhookDelivery, *model.Pagination, error) {
+	if _, err := s.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, nil, err
+	}
+	return readPage(s.cfg, query, "webhook-deliveries:"+webhookID.String(), func(page model.Page) (*model.Paged[*model.WebhookDelivery], error) {
+		return s.webhookRepo.GetDeliveries(ctx, webhookID, page)
+	}, func(delivery *model.WebhookDelivery) []any {
+		return []any{delivery.CreatedAt, delivery.ID}
+	})
+}
+
+// RedeliverWebhookDelivery queues a delivery to be sent again on the next run, regardless of its previous outcome
+func (s *WebhookService) RedeliverWebhookDelivery(ctx context.Context, webhookID, id uuid.UUID) (*model.WebhookDelivery, error) {
+	existing, err := s.webhookRepo.GetDeliveryByID(ctx, webhookID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	tx, err := s.webhookRepo.GetTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	delivery, err := s.webhookRepo.ResetDelivery(ctx, tx, webhookID, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, tx, s.auditRepo, model.WebhookRedeliveredAction, model.WebhookTarget, webhookID, existing, delivery); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// DeliverPendingWebhooks sends the deliveries that are due until none is left
+func (s *WebhookService) DeliverPendingWebhooks(ctx context.Context) error {
+	// deliveries are leased for longer than a request can take so that they are not sent twice
+	lease := s.cfg.WebhookTimeout + time.Minute
+
+	for {
+		dispatches, err := s.webhookRepo.ClaimDueDeliveries(ctx, lease, webhookBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var wg sync.WaitGroup
+		for _, dispatch := range dispatches {
+			wg.Add(1)
+			go func(dispatch *model.WebhookDispatch) {
+				defer wg.Done()
+				if err := s.deliver(ctx, dispatch); err != nil && ctx.Err() == nil {
+					log.Printf("failed to record webhook delivery %s: %v", dispatch.Delivery.ID, err)
+				}
+			}(dispatch)
+		}
+		wg.Wait()
+
+		if len(dispatches) < webhookBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// deliver sends a delivery and records the response. Repeated failures schedule a retry with exponential backoff and eventually disable the webhook
+func (s *WebhookService) deliver(ctx context.Context, dispatch *model.WebhookDispatch) error {
+	delivery := dispatch.Delivery
+	code, body, sendErr := s.send(ctx, dispatch)
+
+	// the lease expires and the delivery is retried if sending was interrupted by a shutdown
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	tx, err := s.webhookRepo.GetTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if sendErr == nil && code >= 200 && code < 300 {
+		if err := s.webhookRepo.MarkDeliverySucceeded(ctx, tx, delivery.ID, code, body); err != nil {
+			return err
+		}
+		if err := s.webhookRepo.RecordSuccess(ctx, tx, delivery.WebhookID); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	var responseCode *int
+	var responseBody *string
+	lastError := fmt.Sprintf("unexpected status code %d", code)
+	if sendErr != nil {
+		lastError = sendErr.Error()
+	} else {
+		responseCode, responseBody = &code, &body
+	}
+
+	status := model.PendingDelivery
+	if delivery.Attempts+1 >= s.cfg.WebhookMaxAttempts {
+		status = model.FailedDelivery
+	}
+	if err := s.webhookRepo.MarkDeliveryFailed(ctx, tx, delivery.ID, status, responseCode, responseBody, lastError, webhookBackoff(delivery.Attempts)); err != nil {
+		return err
+	}
+
+	disabled, err := s.webhookRepo.RecordFailure(ctx, tx, delivery.WebhookID, s.cfg.WebhookDisableAfter)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		log.Printf("webhook %s disabled after %d consecutive failed deliveries", delivery.WebhookID, s.cfg.WebhookDisableAfter)
+	}
+	return tx.Commit()
+}
+
+// webhookBackoff returns the delay before retrying a delivery that has failed after the given number of previous attempts
+func webhookBackoff(attempts int) time.Duration {
+	return min(webhookBaseBackoff*time.Duration(1<<min(attempts, 12)), maxWebhookBackoff)
+}
+
+// send posts the signed payload of a delivery and returns the response status code and the start of the response body
+func (s *WebhookService) send(ctx context.Context, dispatch *model.WebhookDispatch) (int, string, error) {
+	delivery := dispatch.Delivery
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Chow-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookSignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, SignWebhookPayload(dispatch.Secret, timestamp, delivery.Payload)))
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxWebhookResponseBody))
+	if err != nil {
+		return 0, "", err
+	}
+	return res.StatusCode, strings.ToValidUTF8(string(body), ""), nil
+}
+
+// handleEvent queues a delivery of the event for every active webhook subscribed to it
+func (s *WebhookService) handleEvent(ctx context.Context, event *model.Event) error {
+	webhooks, err := s.webhookRepo.GetActiveByEventType(ctx, event.Type)
+	if err != nil || len(webhooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(model.WebhookPayload{ID: event.ID, Type: event.Type, CreatedAt: event.CreatedAt, Data: event.Payload})
+	if err != nil {
+		return err
+	}
+
+	for _, webhook := range webhooks {
+		_, err := s.webhookRepo.CreateDelivery(ctx, &model.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+		// deliveries already queued by a previous attempt are skipped
+		if err != nil && !errors.Is(err, repository.ErrAlreadyExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the hex encoded HMAC-SHA256 signature of a payload sent at the given unix timestamp. Receivers verify a delivery by computing the same signature over the raw request body
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret returns a random secret for signing webhook payloads
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"chow/internal/config"
+	"chow/internal/model"
+	"chow/internal/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// webhookReceiver is a local endpoint that records the deliveries it receives and answers with a fixed response
+type webhookReceiver struct {
+	*httptest.Server
+	requests chan *receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T, status int, body string) *webhookReceiver {
+	t.Helper()
+	receiver := &webhookReceiver{requests: make(chan *receivedWebhook, 10)}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		receiver.requests <- &receivedWebhook{header: r.Header.Clone(), body: payload}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver
+}
+
+// received returns the next delivery the receiver got
+func (r *webhookReceiver) received(t *testing.T) *receivedWebhook {
+	t.Helper()
+	select {
+	case req := <-r.requests:
+		return req
+	default:
+		t.Fatal("no delivery received")
+		return nil
+	}
+}
+
+func newTestWebhookService(t *testing.T) (*WebhookService, sqlmock.Sqlmock) {
+	db, mock := newMockDB(t)
+	cfg := &config.Config{WebhookTimeout: 5 * time.Second, WebhookMaxAttempts: 5, WebhookDisableAfter: 3}
+	return NewWebhookService(cfg, repository.NewWebhookRepository(db), repository.NewAuditRepository(db)), mock
+}
+
+func testDelivery(attempts int, status model.WebhookDeliveryStatus) *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: uuid.New(),
+		EventID:   uuid.New(),
+		EventType: model.JointApprovedEvent,
+		Payload:   []byte(`{"type":"joint.approved","data":{"name":"Auntie Muni"}}`),
+		Status:    status,
+		Attempts:  attempts,
+	}
+}
+
+var deliveryColumns = []string{"id", "webhook_id", "event_id", "event_type", "payload", "status", "attempts", "response_code", "response_body", "last_error", "next_attempt_at", "delivered_at", "created_at", "updated_at"}
+
+func deliveryValues(d *model.WebhookDelivery) []driver.Value {
+	now := time.Now()
+	return []driver.Value{d.ID, d.WebhookID, d.EventID, string(d.EventType), []byte(d.Payload), string(d.Status), d.Attempts, nil, nil, nil, now, nil, now, now}
+}
+
+// expectClaim expects the deliveries to be claimed for sending to the url
+func expectClaim(mock sqlmock.Sqlmock, url string, deliveries ...*model.WebhookDelivery) {
+	rows := sqlmock.NewRows(append(append([]string{}, deliveryColumns...), "url", "secret"))
+	for _, d := range deliveries {
+		rows.AddRow(append(deliveryValues(d), url, testWebhookSecret)...)
+	}
+	mock.ExpectQuery(`UPDATE webhook_deliveries d\s+SET next_attempt_at`).WithArgs(webhookBatchSize, sqlmock.AnyArg()).WillReturnRows(rows)
+}
+
+// expectFailure expects a failed attempt to be recorded and the failure count of its webhook to be incremented
+func expectFailure(mock sqlmock.Sqlmock, d *model.WebhookDelivery, status model.WebhookDeliveryStatus, code any, body any, lastError string, disabled bool) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE webhook_deliveries\s+SET status = \$1`).
+		WithArgs(string(status), code, body, lastError, webhookBackoff(d.Attempts).Seconds(), d.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`UPDATE webhooks\s+SET consecutive_failures = consecutive_failures \+ 1`).
+		WithArgs(3, d.WebhookID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"disabled"}).AddRow(disabled))
+	mock.ExpectCommit()
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	payload := []byte(`{"id":"1"}`)
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(`1700000000.{"id":"1"}`))
+	want := hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhookPayload(testWebhookSecret, 1700000000, payload); got != want {
+		t.Errorf("SignWebhookPayload() = %s, want %s", got, want)
+	}
+	if got := SignWebhookPayload("another secret", 1700000000, payload); got == want {
+		t.Error("SignWebhookPayload() does not depend on the secret")
+	}
+	if got := SignWebhookPayload(testWebhookSecret, 1700000001, payload); got == want {
+		t.Error("SignWebhookPayload() does not depend on the timestamp")
+	}
+}
+
+func TestDeliverPendingWebhooksSignsAndRecordsSuccess(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	receiver := newWebhookReceiver(t, http.StatusNoContent, "")
+	delivery := testDelivery(0, model.PendingDelivery)
+
+	expectClaim(mock, receiver.URL, delivery)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET status = 'succeeded'`).WithArgs(http.StatusNoContent, "", delivery.ID.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE webhooks SET consecutive_failures = 0`).WithArgs(delivery.WebhookID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := service.DeliverPendingWebhooks(context.Background()); err != nil {
+		t.Fatalf("DeliverPendingWebhooks() error = %v", err)
+	}
+
+	req := receiver.received(t)
+	if string(req.body) != string(delivery.Payload) {
+		t.Errorf("body = %s, want %s", req.body, delivery.Payload)
+	}
+	if got := req.header.Get(WebhookEventHeader); got != string(delivery.EventType) {
+		t.Errorf("%s = %q, want %q", WebhookEventHeader, got, delivery.EventType)
+	}
+	if got := req.header.Get(WebhookDeliveryHeader); got != delivery.ID.String() {
+		t.Errorf("%s = %q, want %q", WebhookDeliveryHeader, got, delivery.ID)
+	}
+
+	// receivers verify the signature over "<t>.<raw body>" with the shared secret
+	match := regexp.MustCompile(`^t=(\d+),v1=([0-9a-f]{64})$`).FindStringSubmatch(req.header.Get(WebhookSignatureHeader))
+	if match == nil {
+		t.Fatalf("%s = %q, want t=<timestamp>,v1=<signature>", WebhookSignatureHeader, req.header.Get(WebhookSignatureHeader))
+	}
+	timestamp, _ := strconv.ParseInt(match[1], 10, 64)
+	if age := time.Since(time.Unix(timestamp, 0)); age < -time.Minute || age > time.Minute {
+		t.Errorf("signature timestamp is %s away from now", age)
+	}
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(match[1] + "."))
+	mac.Write(req.body)
+	if !hmac.Equal([]byte(match[2]), []byte(hex.EncodeToString(mac.Sum(nil)))) {
+		t.Error("signature does not match the body")
+	}
+}
+
+func TestDeliverPendingWebhooksRecordsErrorResponse(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	receiver := newWebhookReceiver(t, http.StatusServiceUnavailable, "try later")
+	delivery := testDelivery(2, model.PendingDelivery)
+
+	expectClaim(mock, receiver.URL, delivery)
+	expectFailure(mock, delivery, model.PendingDelivery, http.StatusServiceUnavailable, "try later", "unexpected status code 503", false)
+
+	if err := service.DeliverPendingWebhooks(context.Background()); err != nil {
+		t.Fatalf("DeliverPendingWebhooks() error = %v", err)
+	}
+	receiver.received(t)
+}
+
+func TestDeliverPendingWebhooksRecordsUnreachableEndpoint(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	receiver := newWebhookReceiver(t, http.StatusOK, "")
+	url := receiver.URL
+	receiver.Close()
+	delivery := testDelivery(0, model.PendingDelivery)
+
+	expectClaim(mock, url, delivery)
+	mock.ExpectBegin()
+	// no response code or body is recorded when the endpoint could not be reached
+	mock.ExpectExec(`UPDATE webhook_deliveries\s+SET status = \$1`).
+		WithArgs(string(model.PendingDelivery), nil, nil, sqlmock.AnyArg(), webhookBaseBackoff.Seconds(), delivery.ID.String()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SET consecutive_failures = consecutive_failures \+ 1`).
+		WithArgs(3, delivery.WebhookID.String()).
+		WillReturnRows(sqlmock.NewRows([]string{"disabled"}).AddRow(false))
+	mock.ExpectCommit()
+
+	if err := service.DeliverPendingWebhooks(context.Background()); err != nil {
+		t.Fatalf("DeliverPendingWebhooks() error = %v", err)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{5, 16 * time.Minute},
+		{9, 256 * time.Minute},
+		{10, maxWebhookBackoff},
+		{100, maxWebhookBackoff},
+	}
+	for _, tt := range tests {
+		if got := webhookBackoff(tt.attempts); got != tt.want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverPendingWebhooksGivesUpAfterMaxAttempts(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError, "")
+	delivery := testDelivery(4, model.PendingDelivery)
+
+	expectClaim(mock, receiver.URL, delivery)
+	expectFailure(mock, delivery, model.FailedDelivery, http.StatusInternalServerError, "", "unexpected status code 500", false)
+
+	if err := service.DeliverPendingWebhooks(context.Background()); err != nil {
+		t.Fatalf("DeliverPendingWebhooks() error = %v", err)
+	}
+}
+
+func TestDeliverPendingWebhooksDisablesFailingWebhook(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError, "")
+	deliveries := []*model.WebhookDelivery{testDelivery(0, model.PendingDelivery), testDelivery(0, model.PendingDelivery), testDelivery(0, model.PendingDelivery)}
+	for _, d := range deliveries[1:] {
+		d.WebhookID = deliveries[0].WebhookID
+	}
+
+	// the webhook is disabled by the failure that brings its count to WebhookDisableAfter, after which its deliveries are no longer claimed
+	for i, d := range deliveries {
+		expectClaim(mock, receiver.URL, d)
+		expectFailure(mock, d, model.PendingDelivery, http.StatusInternalServerError, "", "unexpected status code 500", i == len(deliveries)-1)
+	}
+	mock.ExpectQuery(`WHERE d.status = 'pending' AND d.next_attempt_at <= NOW\(\) AND w.is_active`).WithArgs(webhookBatchSize, sqlmock.AnyArg()).WillReturnRows(sqlmock.NewRows(deliveryColumns))
+
+	for range len(deliveries) + 1 {
+		if err := service.DeliverPendingWebhooks(context.Background()); err != nil {
+			t.Fatalf("DeliverPendingWebhooks() error = %v", err)
+		}
+	}
+	if got := len(receiver.requests); got != len(deliveries) {
+		t.Errorf("receiver got %d deliveries, want %d", got, len(deliveries))
+	}
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	receiver := newWebhookReceiver(t, http.StatusOK, "ok")
+	failed := testDelivery(5, model.FailedDelivery)
+	reset := *failed
+	reset.Status, reset.Attempts = model.PendingDelivery, 0
+
+	mock.ExpectQuery(`SELECT .+ FROM webhook_deliveries WHERE id = \$1 AND webhook_id = \$2`).
+		WithArgs(failed.ID.String(), failed.WebhookID.String()).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).AddRow(deliveryValues(failed)...))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SET status = 'pending', attempts = 0, next_attempt_at = NOW\(\)`).
+		WithArgs(failed.ID.String(), failed.WebhookID.String()).
+		WillReturnRows(sqlmock.NewRows(deliveryColumns).AddRow(deliveryValues(&reset)...))
+	expectAudit(mock, string(model.WebhookRedeliveredAction))
+	mock.ExpectCommit()
+
+	delivery, err := service.RedeliverWebhookDelivery(context.Background(), failed.WebhookID, failed.ID)
+	if err != nil {
+		t.Fatalf("RedeliverWebhookDelivery() error = %v", err)
+	}
+	if delivery.Status != model.PendingDelivery || delivery.Attempts != 0 {
+		t.Errorf("redelivered delivery is %s after %d attempts, want pending after 0", delivery.Status, delivery.Attempts)
+	}
+
+	// the reset delivery is sent again on the next run
+	expectClaim(mock, receiver.URL, delivery)
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET status = 'succeeded'`).WithArgs(http.StatusOK, "ok", delivery.ID.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE webhooks SET consecutive_failures = 0`).WithArgs(delivery.WebhookID.String()).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := service.DeliverPendingWebhooks(context.Background()); err != nil {
+		t.Fatalf("DeliverPendingWebhooks() error = %v", err)
+	}
+	if req := receiver.received(t); req.header.Get(WebhookDeliveryHeader) != failed.ID.String() {
+		t.Errorf("%s = %q, want %q", WebhookDeliveryHeader, req.header.Get(WebhookDeliveryHeader), failed.ID)
+	}
+}
+
+func TestRedeliverWebhookDeliveryNotFound(t *testing.T) {
+	service, mock := newTestWebhookService(t)
+	mock.ExpectQuery(`FROM webhook_deliveries WHERE id = \$1 AND webhook_id = \$2`).WillReturnRows(sqlmock.NewRows(deliveryColumns))
+
+	_, err := service.RedeliverWebhookDelivery(context.Background(), uuid.New(), uuid.New())
+	if err != ErrWebhookDeliveryNotFound {
+		t.Errorf("RedeliverWebhookDelivery() error = %v, want %v", err, ErrWebhookDeliveryNotFound)
+	}
+}